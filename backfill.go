@@ -0,0 +1,208 @@
+package currencycom
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// DEFAULT_KLINE_LIMIT is the number of bars asked per request by
+// KlineBackfill when Limit is not set.
+const DEFAULT_KLINE_LIMIT int32 = 1000
+
+// KlineGap is a range of bars the exchange returned nothing for, From is
+// the open time of the first missing bar and To the open time of the
+// first bar after the gap.
+type KlineGap struct {
+	From time.Time
+	To   time.Time
+}
+
+// KlineBackfill loads a range of klines of any length by splitting it into
+// requests, joins the pages dropping repeated bars and reports the bars
+// that are missing.
+type KlineBackfill struct {
+	Symbol   Symbol
+	Interval Interval
+	Type     CandleType // CandleTypeClassic (default) or CandleTypeHeikinAshi
+	Limit    int32      // bars per request, 0 means DEFAULT_KLINE_LIMIT
+
+	// Hours, when set, limits gap detection to bars that overlap a trading
+	// session, so nights and weekends of equities are not gaps.
+	Hours *TradingHours
+
+	// Pause is the least delay between requests, on top of the rate
+	// limiter and retry policy of the client.
+	Pause time.Duration
+
+	client *Client
+}
+
+// NewKlineBackfill creates a backfill for the symbol and interval, nil
+// client means the default client.
+func NewKlineBackfill(client *Client, symbol Symbol, interval Interval) *KlineBackfill {
+	if client == nil {
+		client = defaultClient()
+	}
+
+	return &KlineBackfill{Symbol: symbol, Interval: interval, client: client}
+}
+
+// Fetch loads all bars opened in [start, end).
+func (b *KlineBackfill) Fetch(ctx context.Context, start, end time.Time) ([]Kline, []KlineGap, error) {
+	var out []Kline
+
+	gaps, err := b.Run(ctx, start, end, func(klines []Kline) error {
+		out = append(out, klines...)
+		return nil
+	})
+
+	return out, gaps, err
+}
+
+// Run loads the bars opened in [start, end) and passes them to store one
+// page at a time, oldest first. It stops on the first error of the
+// exchange or store, the gaps found until then are returned either way.
+// An interrupted run can be continued with Resume.
+func (b *KlineBackfill) Run(ctx context.Context, start, end time.Time, store func([]Kline) error) ([]KlineGap, error) {
+	d := b.Interval.Duration()
+	if !b.Interval.IsValid() {
+		return nil, fmt.Errorf("error params: unknown Interval %q", b.Interval)
+	}
+
+	// the bar in progress is not missing yet
+	complete := end
+	if now := time.Now(); end.IsZero() || end.After(now) {
+		end = now
+		complete = now.Add(-d + time.Millisecond)
+	}
+
+	limit := b.Limit
+	if limit <= 0 {
+		limit = DEFAULT_KLINE_LIMIT
+	}
+
+	var gaps []KlineGap
+	var last time.Time // open time of the last stored bar
+	var requested time.Time
+
+	for cursor := start; cursor.Before(end); {
+		if err := ctx.Err(); err != nil {
+			return gaps, err
+		}
+
+		if b.Pause > 0 && !requested.IsZero() {
+			if err := sleep(ctx, b.Pause-time.Since(requested)); err != nil {
+				return gaps, err
+			}
+		}
+
+		chunkEnd := cursor.Add(time.Duration(limit) * d)
+		if chunkEnd.After(end) {
+			chunkEnd = end
+		}
+
+		requested = time.Now()
+
+		klines, err := b.client.KlinesWithContext(ctx, &KLinesRequest{
+			Symbol:    b.Symbol,
+			Interval:  b.Interval,
+			Type:      b.Type,
+			Limit:     limit,
+			StartTime: cursor.UnixMilli(),
+			EndTime:   chunkEnd.UnixMilli() - 1,
+		})
+		if err != nil {
+			return gaps, err
+		}
+
+		page := klines[:0]
+		for _, k := range klines {
+			if k.OpenTime.Before(start) || !k.OpenTime.Before(end) || !last.IsZero() && !k.OpenTime.After(last) {
+				continue
+			}
+
+			if last.IsZero() {
+				// the first bar tells the grid of bar open times
+				gaps = b.appendGap(gaps, k.OpenTime.Add(-k.OpenTime.Sub(start)/d*d), k.OpenTime, d)
+			} else {
+				gaps = b.appendGap(gaps, last.Add(d), k.OpenTime, d)
+			}
+
+			page = append(page, k)
+			last = k.OpenTime
+		}
+
+		if len(page) > 0 {
+			if err := store(page); err != nil {
+				return gaps, err
+			}
+		}
+
+		// a full page may stop before the chunk end, continue after its last
+		// bar as long as that moves the cursor forward
+		next := chunkEnd
+		if len(klines) >= int(limit) && !last.IsZero() {
+			if after := last.Add(d); after.After(cursor) && after.Before(chunkEnd) {
+				next = after
+			}
+		}
+		cursor = next
+	}
+
+	from := last.Add(d)
+	if last.IsZero() {
+		// no bars at all, assume the grid of Truncate, which counts from
+		// the zero time and matches the epoch grid for intervals up to a day
+		from = start.Truncate(d)
+		if from.Before(start) {
+			from = from.Add(d)
+		}
+	}
+	gaps = b.appendGap(gaps, from, complete, d)
+
+	return gaps, nil
+}
+
+// Resume continues a backfill after last, the newest bar already stored.
+func (b *KlineBackfill) Resume(ctx context.Context, last Kline, end time.Time, store func([]Kline) error) ([]KlineGap, error) {
+	d := b.Interval.Duration()
+	if !b.Interval.IsValid() {
+		return nil, fmt.Errorf("error params: unknown Interval %q", b.Interval)
+	}
+
+	return b.Run(ctx, last.OpenTime.Add(d), end, store)
+}
+
+// appendGap adds the bars opened in [from, to) that should have traded,
+// from is the open time of a bar.
+func (b *KlineBackfill) appendGap(gaps []KlineGap, from, to time.Time, d time.Duration) []KlineGap {
+	var gap *KlineGap
+
+	for t := from; t.Before(to); t = t.Add(d) {
+		if !b.traded(t, d) {
+			gap = nil
+			continue
+		}
+
+		if gap == nil {
+			gaps = append(gaps, KlineGap{From: t, To: t.Add(d)})
+			gap = &gaps[len(gaps)-1]
+		} else {
+			gap.To = t.Add(d)
+		}
+	}
+
+	return gaps
+}
+
+// traded reports whether the bar opened at t overlaps a trading session.
+func (b *KlineBackfill) traded(t time.Time, d time.Duration) bool {
+	if b.Hours == nil || b.Hours.IsOpen(t) {
+		return true
+	}
+
+	next, ok := b.Hours.NextOpen(t)
+
+	return ok && next.Before(t.Add(d))
+}
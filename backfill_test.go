@@ -0,0 +1,189 @@
+package currencycom
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// klineServer serves the klines of the requested range like the exchange,
+// with stuck set it ignores startTime and always returns the first page.
+func klineServer(t *testing.T, klines []Kline, stuck bool) *Client {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		start, _ := strconv.ParseInt(query.Get("startTime"), 10, 64)
+		end, _ := strconv.ParseInt(query.Get("endTime"), 10, 64)
+		limit, _ := strconv.Atoi(query.Get("limit"))
+		if stuck {
+			start = 0
+		}
+
+		out := []Kline{}
+		for _, k := range klines {
+			if ms := k.OpenTime.UnixMilli(); ms >= start && ms <= end && len(out) < limit {
+				out = append(out, k)
+			}
+		}
+
+		_ = json.NewEncoder(w).Encode(out)
+	}))
+	t.Cleanup(srv.Close)
+
+	return NewClient(srv.URL, WithRetryPolicy(NoRetry))
+}
+
+// bars returns bars opened at start + every offset of d.
+func bars(start time.Time, d time.Duration, offsets ...int) []Kline {
+	out := make([]Kline, len(offsets))
+	for i, offset := range offsets {
+		out[i] = Kline{OpenTime: start.Add(time.Duration(offset) * d), Close: NewDecimalFromInt(int64(offset))}
+	}
+
+	return out
+}
+
+func TestKlineBackfillGaps(t *testing.T) {
+	day := time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)
+	// Friday to Monday, 4h bars, the exchange is closed on the weekend
+	friday := time.Date(2021, 6, 4, 0, 0, 0, 0, time.UTC)
+	weekdays, err := ParseTradingHours("UTC; Mon -; Tue -; Wed -; Thu -; Fri -", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		interval   Interval
+		hours      *TradingHours
+		klines     []Kline
+		start, end time.Time
+		gaps       []KlineGap
+	}{
+		{
+			name:     "no gaps",
+			interval: Interval1h,
+			klines:   bars(day, time.Hour, 0, 1, 2, 3, 4, 5, 6, 7, 8, 9),
+			start:    day,
+			end:      day.Add(10 * time.Hour),
+		},
+		{
+			name:     "gaps in the middle, at the start and at the end",
+			interval: Interval1h,
+			klines:   bars(day, time.Hour, 1, 2, 5, 6, 7),
+			start:    day,
+			end:      day.Add(10 * time.Hour),
+			gaps: []KlineGap{
+				{day, day.Add(time.Hour)},
+				{day.Add(3 * time.Hour), day.Add(5 * time.Hour)},
+				{day.Add(8 * time.Hour), day.Add(10 * time.Hour)},
+			},
+		},
+		{
+			name:     "weekend without hours is a gap",
+			interval: Interval4h,
+			klines:   bars(friday, 4*time.Hour, 0, 1, 2, 3, 4, 5, 18, 19),
+			start:    friday,
+			end:      friday.Add(21 * 4 * time.Hour),
+			gaps: []KlineGap{
+				{friday.Add(6 * 4 * time.Hour), friday.Add(18 * 4 * time.Hour)},
+				{friday.Add(20 * 4 * time.Hour), friday.Add(21 * 4 * time.Hour)},
+			},
+		},
+		{
+			name:     "weekend with hours is not a gap",
+			interval: Interval4h,
+			hours:    weekdays,
+			klines:   bars(friday, 4*time.Hour, 0, 1, 2, 3, 4, 5, 18, 19),
+			start:    friday,
+			end:      friday.Add(21 * 4 * time.Hour),
+			gaps: []KlineGap{
+				{friday.Add(20 * 4 * time.Hour), friday.Add(21 * 4 * time.Hour)},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewKlineBackfill(klineServer(t, tt.klines, false), "BTC/USD", tt.interval)
+			b.Limit = 3
+			b.Hours = tt.hours
+
+			klines, gaps, err := b.Fetch(context.Background(), tt.start, tt.end)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(klines) != len(tt.klines) {
+				t.Errorf("got %d klines, want %d", len(klines), len(tt.klines))
+			}
+
+			assertGaps(t, gaps, tt.gaps)
+		})
+	}
+}
+
+func TestKlineBackfillResume(t *testing.T) {
+	day := time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)
+	klines := bars(day, time.Hour, 0, 1, 2, 3, 4, 5, 6, 7)
+	b := NewKlineBackfill(klineServer(t, klines, false), "BTC/USD", Interval1h)
+	b.Limit = 2
+
+	var stored []Kline
+	gaps, err := b.Resume(context.Background(), klines[3], day.Add(8*time.Hour), func(page []Kline) error {
+		stored = append(stored, page...)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertGaps(t, gaps, nil)
+
+	if len(stored) != 4 {
+		t.Fatalf("stored %d klines, want 4 after the last one", len(stored))
+	}
+	for i, k := range stored {
+		if !k.OpenTime.Equal(klines[4+i].OpenTime) {
+			t.Errorf("stored[%d] opened at %v, want %v", i, k.OpenTime, klines[4+i].OpenTime)
+		}
+	}
+}
+
+func TestKlineBackfillCursorProgress(t *testing.T) {
+	day := time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)
+	b := NewKlineBackfill(klineServer(t, bars(day, time.Hour, 0, 1, 2, 3, 4, 5), true), "BTC/USD", Interval1h)
+	b.Limit = 2
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// the server repeats the first page, the backfill must still finish
+	klines, _, err := b.Fetch(ctx, day, day.Add(6*time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(klines) != 2 {
+		t.Errorf("got %d klines, want the 2 of the repeated page", len(klines))
+	}
+}
+
+func assertGaps(t *testing.T, got, want []KlineGap) {
+	t.Helper()
+
+	if len(got) != len(want) {
+		t.Fatalf("gaps %v, want %v", got, want)
+	}
+
+	for i := range want {
+		if !got[i].From.Equal(want[i].From) || !got[i].To.Equal(want[i].To) {
+			t.Errorf("gaps[%d] = %v, want %v", i, got[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,775 @@
+package backtest
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	currencycom "github.com/scientistnik/currency.com"
+)
+
+// DEFAULT_SWAP_INTERVAL is the swap charge period of symbols that have no
+// SwapChargeInterval.
+const DEFAULT_SWAP_INTERVAL = 24 * time.Hour
+
+var hundred = currencycom.NewDecimalFromInt(100)
+
+// Order is a working or finished order of the broker.
+type Order struct {
+	Id      string
+	Request currencycom.CreateOrderRequest
+	Status  currencycom.OrderStatus
+	Created time.Time
+	Updated time.Time
+
+	FilledPrice currencycom.Decimal // zero until filled
+}
+
+// Position is the open net position of a symbol, Quantity is negative for
+// a short. Stop loss and take profit of orders move to the position they
+// open or add to.
+type Position struct {
+	Id         string
+	Symbol     string
+	OrderId    string // order that opened the position
+	Quantity   currencycom.Decimal
+	OpenPrice  currencycom.Decimal // average
+	OpenTime   time.Time
+	StopLoss   currencycom.Decimal
+	TakeProfit currencycom.Decimal
+	Fee        currencycom.Decimal // fees paid by the open quantity
+	Swap       currencycom.Decimal // swap of the open quantity, negative when paid
+
+	nextSwap time.Time
+}
+
+// Side returns OrderSideBuy for a long and OrderSideSell for a short.
+func (p Position) Side() currencycom.OrderSide {
+	if p.Quantity.IsNegative() {
+		return currencycom.OrderSideSell
+	}
+
+	return currencycom.OrderSideBuy
+}
+
+// Upl returns the unrealized profit at price.
+func (p Position) Upl(price currencycom.Decimal) currencycom.Decimal {
+	return price.Sub(p.OpenPrice).Mul(p.Quantity)
+}
+
+// Fill is an execution of an order, or of a stop loss or take profit of a
+// position, then OrderId is empty.
+type Fill struct {
+	OrderId    string
+	PositionId string
+	Symbol     string
+	Side       currencycom.OrderSide
+	Quantity   currencycom.Decimal
+	Price      currencycom.Decimal
+	Fee        currencycom.Decimal
+	Maker      bool
+	Time       time.Time
+}
+
+// Trade is a closed round trip, a position closed in parts makes a trade
+// per part.
+type Trade struct {
+	PositionId string
+	Symbol     string
+	Side       currencycom.OrderSide // side of the position
+	Quantity   currencycom.Decimal
+	OpenPrice  currencycom.Decimal
+	ClosePrice currencycom.Decimal
+	OpenTime   time.Time
+	CloseTime  time.Time
+	Profit     currencycom.Decimal // price difference times quantity
+	Fee        currencycom.Decimal // opening and closing fees
+	Swap       currencycom.Decimal
+}
+
+// Net returns the profit after fees and swap.
+func (t Trade) Net() currencycom.Decimal {
+	return t.Profit.Sub(t.Fee).Add(t.Swap)
+}
+
+// Broker simulates an account trading net positions against a price feed.
+// Accounting is the one of CFDs: positions don't buy the asset, the cash
+// balance changes by realized profit, fees and swap, equity adds the
+// unrealized profit. Margin is not checked.
+//
+// Fees are percents of the notional: TradingFee of leverage symbols when
+// set, MakerFee for resting limit orders and TakerFee otherwise. Positions
+// are charged LongRate or ShortRate percent of their notional every
+// SwapChargeInterval minutes, negative rates are paid.
+//
+// A Broker is safe for concurrent use.
+type Broker struct {
+	// Immediate fills market orders at the last price when they are made,
+	// instead of at the open of the next price update.
+	Immediate bool
+
+	// Validator, when set, checks orders like RestAPI does.
+	Validator *currencycom.OrderValidator
+
+	// OnFill is called with every fill, with the broker locked.
+	OnFill func(Fill)
+
+	mu        sync.Mutex
+	symbols   map[string]currencycom.ExchangeSymbolInfo
+	cash      currencycom.Decimal
+	orders    []*Order // working, oldest first
+	positions map[string]*Position
+	prices    map[string]currencycom.Decimal
+	fills     []Fill
+	trades    []Trade
+	fees      currencycom.Decimal
+	swaps     currencycom.Decimal
+	now       time.Time
+	seq       int64
+}
+
+// NewBroker creates a broker with a cash balance trading symbols.
+func NewBroker(cash currencycom.Decimal, symbols ...currencycom.ExchangeSymbolInfo) *Broker {
+	b := &Broker{
+		symbols:   make(map[string]currencycom.ExchangeSymbolInfo),
+		cash:      cash,
+		positions: make(map[string]*Position),
+		prices:    make(map[string]currencycom.Decimal),
+	}
+
+	for _, s := range symbols {
+		b.symbols[s.Symbol] = s
+	}
+
+	return b
+}
+
+// Now returns the time of the last price update.
+func (b *Broker) Now() time.Time {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.now
+}
+
+// Cash returns the balance without unrealized profit.
+func (b *Broker) Cash() currencycom.Decimal {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.cash
+}
+
+// Equity returns the balance with the unrealized profit at last prices.
+func (b *Broker) Equity() currencycom.Decimal {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.equity()
+}
+
+func (b *Broker) equity() currencycom.Decimal {
+	equity := b.cash
+	for symbol, p := range b.positions {
+		equity = equity.Add(p.Upl(b.prices[symbol]))
+	}
+
+	return equity
+}
+
+// Fees returns the fees paid so far.
+func (b *Broker) Fees() currencycom.Decimal {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.fees
+}
+
+// Swaps returns the swap charged so far, negative when paid.
+func (b *Broker) Swaps() currencycom.Decimal {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.swaps
+}
+
+// Price returns the last price of symbol.
+func (b *Broker) Price(symbol string) (currencycom.Decimal, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	price, ok := b.prices[symbol]
+
+	return price, ok
+}
+
+// Symbol returns the exchange info of symbol.
+func (b *Broker) Symbol(symbol string) (currencycom.ExchangeSymbolInfo, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	info, ok := b.symbols[symbol]
+
+	return info, ok
+}
+
+// Position returns the open position of symbol.
+func (b *Broker) Position(symbol string) (Position, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if p, ok := b.positions[symbol]; ok {
+		return *p, true
+	}
+
+	return Position{}, false
+}
+
+// Positions returns the open positions ordered by symbol.
+func (b *Broker) Positions() []Position {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	out := make([]Position, 0, len(b.positions))
+	for _, p := range b.positions {
+		out = append(out, *p)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Symbol < out[j].Symbol })
+
+	return out
+}
+
+// Orders returns the working orders, oldest first.
+func (b *Broker) Orders() []Order {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	out := make([]Order, len(b.orders))
+	for i, o := range b.orders {
+		out[i] = *o
+	}
+
+	return out
+}
+
+// Fills returns all fills, oldest first.
+func (b *Broker) Fills() []Fill {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return append([]Fill(nil), b.fills...)
+}
+
+// Trades returns the closed trades, oldest first.
+func (b *Broker) Trades() []Trade {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return append([]Trade(nil), b.trades...)
+}
+
+// CreateOrder places an order. MARKET orders fill at the next price,
+// LIMIT, LIMIT_MAKER and TAKE_PROFIT when the price reaches Price, STOP and
+// STOP_LOSS when it crosses Price. A bar opening past Price fills at
+// the open.
+func (b *Broker) CreateOrder(params *currencycom.CreateOrderRequest) (*currencycom.NewOrderResponseRESULT, error) {
+	if params == nil {
+		return nil, fmt.Errorf("error params: params need to set")
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	req := *params
+
+	if _, ok := b.symbols[string(req.Symbol)]; !ok {
+		return nil, fmt.Errorf("%w %q", currencycom.ErrInvalidSymbol, req.Symbol)
+	}
+
+	if !req.Side.IsValid() {
+		return nil, fmt.Errorf("error params: unknown Side %q", req.Side)
+	}
+
+	if !req.Quantity.IsPositive() {
+		return nil, fmt.Errorf("%w: quantity must be positive", currencycom.ErrInvalidQuantity)
+	}
+
+	switch req.Type {
+	case currencycom.OrderTypeMarket:
+	case currencycom.OrderTypeLimit, currencycom.OrderTypeLimitMaker, currencycom.OrderTypeTakeProfit,
+		currencycom.OrderTypeStop, currencycom.OrderTypeStopLoss:
+		if !req.Price.IsPositive() {
+			return nil, fmt.Errorf("%w: Price need to set for %s", currencycom.ErrInvalidPrice, req.Type)
+		}
+	default:
+		return nil, fmt.Errorf("%w: unsupported Type %q", currencycom.ErrInvalidOrder, req.Type)
+	}
+
+	if b.Validator != nil {
+		if err := b.Validator.ValidateOrder(&req); err != nil {
+			return nil, err
+		}
+	}
+
+	b.seq++
+	o := &Order{
+		Id:      fmt.Sprintf("order-%d", b.seq),
+		Request: req,
+		Status:  currencycom.OrderStatusNew,
+		Created: b.now,
+		Updated: b.now,
+	}
+
+	price, ok := b.prices[string(req.Symbol)]
+	if req.Type == currencycom.OrderTypeMarket && b.Immediate {
+		if !ok {
+			return nil, fmt.Errorf("%w: no price of %s yet", currencycom.ErrMarketClosed, req.Symbol)
+		}
+		b.fill(o, price, false)
+	} else {
+		b.orders = append(b.orders, o)
+	}
+
+	return &currencycom.NewOrderResponseRESULT{
+		ExecutedQty:        executedQty(o),
+		ExpireTimestamp:    req.ExpireTimestamp,
+		GuaranteedStopLoss: req.GuaranteedStopLoss,
+		OrderId:            o.Id,
+		OrigQty:            req.Quantity,
+		Price:              orderPrice(o),
+		Side:               req.Side,
+		StopLoss:           req.StopLoss,
+		Symbol:             string(req.Symbol),
+		TakeProfit:         req.TakeProfit,
+		TimeInForce:        currencycom.TimeInForceGTC,
+		TransactTime:       b.now.UnixMilli(),
+		Type:               req.Type,
+	}, nil
+}
+
+// CancelOrder cancels a working order and returns it.
+func (b *Broker) CancelOrder(orderId string) (Order, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for i, o := range b.orders {
+		if o.Id != orderId {
+			continue
+		}
+
+		o.Status, o.Updated = currencycom.OrderStatusCanceled, b.now
+		b.orders = append(b.orders[:i], b.orders[i+1:]...)
+
+		return *o, nil
+	}
+
+	return Order{}, fmt.Errorf("%w: %s", currencycom.ErrOrderNotFound, orderId)
+}
+
+// UpdateOrder changes the price, stop loss, take profit and expiry of a
+// working order, zero keeps the current value.
+func (b *Broker) UpdateOrder(orderId string, price, stopLoss, takeProfit currencycom.Decimal, expireTimestamp int64) (Order, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, o := range b.orders {
+		if o.Id != orderId {
+			continue
+		}
+
+		if !price.IsZero() {
+			if o.Request.Type == currencycom.OrderTypeMarket {
+				return Order{}, fmt.Errorf("%w: price of a MARKET order", currencycom.ErrInvalidOrder)
+			}
+			o.Request.Price = price
+		}
+		if !stopLoss.IsZero() {
+			o.Request.StopLoss = stopLoss
+		}
+		if !takeProfit.IsZero() {
+			o.Request.TakeProfit = takeProfit
+		}
+		if expireTimestamp != 0 {
+			o.Request.ExpireTimestamp = expireTimestamp
+		}
+		o.Updated = b.now
+
+		return *o, nil
+	}
+
+	return Order{}, fmt.Errorf("%w: %s", currencycom.ErrOrderNotFound, orderId)
+}
+
+// UpdatePosition sets the stop loss and take profit of a position, zero
+// removes them.
+func (b *Broker) UpdatePosition(positionId string, stopLoss, takeProfit currencycom.Decimal) (Position, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	p := b.position(positionId)
+	if p == nil {
+		return Position{}, fmt.Errorf("%w: %s", currencycom.ErrPositionNotFound, positionId)
+	}
+
+	p.StopLoss, p.TakeProfit = stopLoss, takeProfit
+
+	return *p, nil
+}
+
+// ClosePosition closes a position at the last price and returns the
+// closing fill.
+func (b *Broker) ClosePosition(positionId string) (Fill, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	p := b.position(positionId)
+	if p == nil {
+		return Fill{}, fmt.Errorf("%w: %s", currencycom.ErrPositionNotFound, positionId)
+	}
+
+	return b.exit(p, b.prices[p.Symbol], false), nil
+}
+
+func (b *Broker) position(id string) *Position {
+	for _, p := range b.positions {
+		if p.Id == id {
+			return p
+		}
+	}
+
+	return nil
+}
+
+// Update moves symbol through a bar: working orders fill, expire or stay,
+// then stop losses and take profits of positions are checked, and swap is
+// charged up to the end of the bar. When a bar reaches both the stop loss
+// and the take profit, the stop loss is assumed to come first.
+func (b *Broker) Update(symbol string, bar currencycom.Kline) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	end := bar.CloseTime
+	if end.IsZero() {
+		end = bar.OpenTime
+	}
+
+	if bar.OpenTime.After(b.now) {
+		b.now = bar.OpenTime
+	}
+
+	working := b.orders[:0]
+	for _, o := range b.orders {
+		if string(o.Request.Symbol) != symbol {
+			working = append(working, o)
+			continue
+		}
+
+		if o.Request.ExpireTimestamp > 0 && o.Request.ExpireTimestamp <= bar.OpenTime.UnixMilli() {
+			o.Status, o.Updated = currencycom.OrderStatusExpired, b.now
+			continue
+		}
+
+		if price, maker, ok := touch(o, bar); ok {
+			b.fill(o, price, maker)
+			continue
+		}
+
+		working = append(working, o)
+	}
+	b.orders = working
+
+	if p, ok := b.positions[symbol]; ok {
+		if price, ok := protect(p, bar); ok {
+			b.exit(p, price, false)
+		}
+	}
+
+	if end.After(b.now) {
+		b.now = end
+	}
+	b.prices[symbol] = bar.Close
+
+	b.chargeSwap(symbol)
+}
+
+// UpdatePrice moves symbol to price at time at, like a bar of one trade.
+func (b *Broker) UpdatePrice(symbol string, at time.Time, price currencycom.Decimal) {
+	b.Update(symbol, currencycom.Kline{OpenTime: at, Open: price, High: price, Low: price, Close: price})
+}
+
+// touch returns the fill price of an order in bar, or false if the order
+// is not reached.
+func touch(o *Order, bar currencycom.Kline) (currencycom.Decimal, bool, bool) {
+	req := o.Request
+	buy := req.Side == currencycom.OrderSideBuy
+
+	switch req.Type {
+	case currencycom.OrderTypeMarket:
+		return bar.Open, false, true
+
+	case currencycom.OrderTypeLimit, currencycom.OrderTypeLimitMaker, currencycom.OrderTypeTakeProfit:
+		maker := req.Type != currencycom.OrderTypeTakeProfit
+		if buy {
+			return below(bar, req.Price, maker)
+		}
+		return above(bar, req.Price, maker)
+
+	default: // stops
+		if buy {
+			return above(bar, req.Price, false)
+		}
+		return below(bar, req.Price, false)
+	}
+}
+
+// below fills at price when the bar goes down to it, at the open when the
+// bar opens under it.
+func below(bar currencycom.Kline, price currencycom.Decimal, maker bool) (currencycom.Decimal, bool, bool) {
+	switch {
+	case bar.Open.LessThanOrEqual(price):
+		return bar.Open, maker, true
+	case bar.Low.LessThanOrEqual(price):
+		return price, maker, true
+	}
+
+	return currencycom.Decimal{}, false, false
+}
+
+// above fills at price when the bar goes up to it, at the open when the
+// bar opens over it.
+func above(bar currencycom.Kline, price currencycom.Decimal, maker bool) (currencycom.Decimal, bool, bool) {
+	switch {
+	case bar.Open.GreaterThanOrEqual(price):
+		return bar.Open, maker, true
+	case bar.High.GreaterThanOrEqual(price):
+		return price, maker, true
+	}
+
+	return currencycom.Decimal{}, false, false
+}
+
+// protect returns the exit price of a position whose stop loss or take
+// profit is reached in bar.
+func protect(p *Position, bar currencycom.Kline) (currencycom.Decimal, bool) {
+	long := p.Quantity.IsPositive()
+
+	if !p.StopLoss.IsZero() {
+		var price currencycom.Decimal
+		var ok bool
+		if long {
+			price, _, ok = below(bar, p.StopLoss, false)
+		} else {
+			price, _, ok = above(bar, p.StopLoss, false)
+		}
+		if ok {
+			return price, true
+		}
+	}
+
+	if !p.TakeProfit.IsZero() {
+		var price currencycom.Decimal
+		var ok bool
+		if long {
+			price, _, ok = above(bar, p.TakeProfit, false)
+		} else {
+			price, _, ok = below(bar, p.TakeProfit, false)
+		}
+		if ok {
+			return price, true
+		}
+	}
+
+	return currencycom.Decimal{}, false
+}
+
+// fill executes an order at price.
+func (b *Broker) fill(o *Order, price currencycom.Decimal, maker bool) {
+	o.Status, o.Updated, o.FilledPrice = currencycom.OrderStatusFilled, b.now, price
+
+	req := o.Request
+	quantity := req.Quantity
+	if req.Side == currencycom.OrderSideSell {
+		quantity = quantity.Neg()
+	}
+
+	p := b.execute(string(req.Symbol), o.Id, quantity, price, maker)
+	if p != nil {
+		if !req.StopLoss.IsZero() {
+			p.StopLoss = req.StopLoss
+		}
+		if !req.TakeProfit.IsZero() {
+			p.TakeProfit = req.TakeProfit
+		}
+	}
+}
+
+// exit closes a whole position at price.
+func (b *Broker) exit(p *Position, price currencycom.Decimal, maker bool) Fill {
+	b.execute(p.Symbol, "", p.Quantity.Neg(), price, maker)
+
+	return b.fills[len(b.fills)-1]
+}
+
+// execute trades a signed quantity of symbol at price and returns the
+// position left, nil if it was closed.
+func (b *Broker) execute(symbol, orderId string, quantity, price currencycom.Decimal, maker bool) *Position {
+	fee := quantity.Abs().Mul(price).Mul(b.feeRate(symbol, maker)).Div(hundred)
+	b.cash = b.cash.Sub(fee)
+	b.fees = b.fees.Add(fee)
+
+	f := Fill{OrderId: orderId, Symbol: symbol, Side: currencycom.OrderSideBuy, Quantity: quantity.Abs(),
+		Price: price, Fee: fee, Maker: maker, Time: b.now}
+	if quantity.IsNegative() {
+		f.Side = currencycom.OrderSideSell
+	}
+
+	p := b.positions[symbol]
+	positionId := ""
+
+	// the part of the fill that reduces the position
+	if p != nil && p.Quantity.Sign() != quantity.Sign() {
+		closed := currencycom.MinDecimal(p.Quantity.Abs(), quantity.Abs())
+		openFee := p.Fee.Mul(closed).Div(p.Quantity.Abs())
+		closeFee := fee.Mul(closed).Div(quantity.Abs())
+
+		trade := Trade{
+			PositionId: p.Id,
+			Symbol:     symbol,
+			Side:       p.Side(),
+			Quantity:   closed,
+			OpenPrice:  p.OpenPrice,
+			ClosePrice: price,
+			OpenTime:   p.OpenTime,
+			CloseTime:  b.now,
+			Fee:        openFee.Add(closeFee),
+			Swap:       p.Swap.Mul(closed).Div(p.Quantity.Abs()),
+		}
+		if p.Quantity.IsPositive() {
+			trade.Profit = price.Sub(p.OpenPrice).Mul(closed)
+		} else {
+			trade.Profit = p.OpenPrice.Sub(price).Mul(closed)
+		}
+
+		b.cash = b.cash.Add(trade.Profit)
+		b.trades = append(b.trades, trade)
+		positionId = p.Id
+
+		p.Fee = p.Fee.Sub(openFee)
+		p.Swap = p.Swap.Sub(trade.Swap)
+		p.Quantity = p.Quantity.Add(quantity)
+
+		fee = fee.Sub(closeFee)
+		if p.Quantity.Sign() == quantity.Sign() {
+			// reversed, the rest opens a new position
+			quantity = p.Quantity
+			delete(b.positions, symbol)
+			p = nil
+		} else {
+			quantity = currencycom.Decimal{}
+			if p.Quantity.IsZero() {
+				delete(b.positions, symbol)
+				p = nil
+			}
+		}
+	}
+
+	switch {
+	case quantity.IsZero():
+		// only reduced the position
+
+	case p == nil:
+		b.seq++
+		p = &Position{
+			Id:        fmt.Sprintf("position-%d", b.seq),
+			Symbol:    symbol,
+			OrderId:   orderId,
+			Quantity:  quantity,
+			OpenPrice: price,
+			OpenTime:  b.now,
+			Fee:       fee,
+			nextSwap:  b.now.Truncate(b.swapInterval(symbol)).Add(b.swapInterval(symbol)),
+		}
+		b.positions[symbol] = p
+		positionId = p.Id
+
+	default:
+		total := p.Quantity.Add(quantity)
+		p.OpenPrice = p.OpenPrice.Mul(p.Quantity).Add(price.Mul(quantity)).Div(total)
+		p.Quantity = total
+		p.Fee = p.Fee.Add(fee)
+		positionId = p.Id
+	}
+
+	f.PositionId = positionId
+	b.fills = append(b.fills, f)
+	if b.OnFill != nil {
+		b.OnFill(f)
+	}
+
+	return p
+}
+
+// feeRate returns the fee percent of a fill.
+func (b *Broker) feeRate(symbol string, maker bool) currencycom.Decimal {
+	info := b.symbols[symbol]
+
+	switch {
+	case info.MarketType == currencycom.MarketTypeLeverage && !info.TradingFee.IsZero():
+		return info.TradingFee
+	case maker:
+		return info.MakerFee
+	default:
+		return info.TakerFee
+	}
+}
+
+func (b *Broker) swapInterval(symbol string) time.Duration {
+	if minutes := b.symbols[symbol].SwapChargeInterval; minutes > 0 {
+		return time.Duration(minutes) * time.Minute
+	}
+
+	return DEFAULT_SWAP_INTERVAL
+}
+
+// chargeSwap charges the position of symbol for every swap time passed.
+func (b *Broker) chargeSwap(symbol string) {
+	p, ok := b.positions[symbol]
+	if !ok {
+		return
+	}
+
+	info := b.symbols[symbol]
+	rate := info.LongRate
+	if p.Quantity.IsNegative() {
+		rate = info.ShortRate
+	}
+
+	for !p.nextSwap.After(b.now) {
+		p.nextSwap = p.nextSwap.Add(b.swapInterval(symbol))
+		if rate.IsZero() {
+			continue
+		}
+
+		swap := p.Quantity.Abs().Mul(b.prices[symbol]).Mul(rate).Div(hundred)
+		p.Swap = p.Swap.Add(swap)
+		b.cash = b.cash.Add(swap)
+		b.swaps = b.swaps.Add(swap)
+	}
+}
+
+func executedQty(o *Order) currencycom.Decimal {
+	if o.Status == currencycom.OrderStatusFilled {
+		return o.Request.Quantity
+	}
+
+	return currencycom.Decimal{}
+}
+
+func orderPrice(o *Order) currencycom.Decimal {
+	if o.Status == currencycom.OrderStatusFilled {
+		return o.FilledPrice
+	}
+
+	return o.Request.Price
+}
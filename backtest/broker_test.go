@@ -0,0 +1,331 @@
+package backtest
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	currencycom "github.com/scientistnik/currency.com"
+)
+
+var (
+	start = time.Date(2021, 6, 1, 10, 0, 0, 0, time.UTC)
+	d     = currencycom.MustDecimal
+)
+
+func testSymbol() currencycom.ExchangeSymbolInfo {
+	return currencycom.ExchangeSymbolInfo{
+		Symbol:             "BTC/USD",
+		MakerFee:           d("0.1"),
+		TakerFee:           d("0.2"),
+		LongRate:           d("-0.01"),
+		ShortRate:          d("-0.02"),
+		SwapChargeInterval: 60,
+	}
+}
+
+func hourBar(hour int, open, high, low, closePrice string) currencycom.Kline {
+	openTime := start.Add(time.Duration(hour) * time.Hour)
+
+	return currencycom.Kline{
+		OpenTime:  openTime,
+		CloseTime: openTime.Add(time.Hour - time.Millisecond),
+		Open:      d(open),
+		High:      d(high),
+		Low:       d(low),
+		Close:     d(closePrice),
+	}
+}
+
+func TestBrokerFills(t *testing.T) {
+	tests := []struct {
+		name      string
+		orderType currencycom.OrderType
+		side      currencycom.OrderSide
+		price     string
+		bar       currencycom.Kline
+		filled    bool
+		fillPrice string
+		maker     bool
+	}{
+		{"market at the open", currencycom.OrderTypeMarket, currencycom.OrderSideBuy, "", hourBar(0, "100", "102", "98", "101"), true, "100", false},
+		{"buy limit touched", currencycom.OrderTypeLimit, currencycom.OrderSideBuy, "99", hourBar(0, "100", "102", "98", "101"), true, "99", true},
+		{"buy limit gapped", currencycom.OrderTypeLimit, currencycom.OrderSideBuy, "99", hourBar(0, "97", "98", "96", "97"), true, "97", true},
+		{"buy limit not reached", currencycom.OrderTypeLimit, currencycom.OrderSideBuy, "97", hourBar(0, "100", "102", "98", "101"), false, "", false},
+		{"sell limit maker", currencycom.OrderTypeLimitMaker, currencycom.OrderSideSell, "102", hourBar(0, "100", "102", "98", "101"), true, "102", true},
+		{"take profit is taker", currencycom.OrderTypeTakeProfit, currencycom.OrderSideSell, "101", hourBar(0, "100", "102", "98", "101"), true, "101", false},
+		{"buy stop crossed", currencycom.OrderTypeStop, currencycom.OrderSideBuy, "101", hourBar(0, "100", "102", "98", "101"), true, "101", false},
+		{"buy stop gapped", currencycom.OrderTypeStop, currencycom.OrderSideBuy, "101", hourBar(0, "103", "104", "102", "103"), true, "103", false},
+		{"sell stop loss crossed", currencycom.OrderTypeStopLoss, currencycom.OrderSideSell, "99", hourBar(0, "100", "102", "98", "101"), true, "99", false},
+		{"sell stop not reached", currencycom.OrderTypeStopLoss, currencycom.OrderSideSell, "97", hourBar(0, "100", "102", "98", "101"), false, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewBroker(d("10000"), testSymbol())
+
+			req := &currencycom.CreateOrderRequest{Symbol: "BTC/USD", Side: tt.side, Type: tt.orderType, Quantity: d("2")}
+			if tt.price != "" {
+				req.Price = d(tt.price)
+			}
+			if _, err := b.CreateOrder(req); err != nil {
+				t.Fatal(err)
+			}
+
+			b.Update("BTC/USD", tt.bar)
+
+			fills := b.Fills()
+			if !tt.filled {
+				if len(fills) != 0 || len(b.Orders()) != 1 {
+					t.Fatalf("fills %v, orders %v, want the order working", fills, b.Orders())
+				}
+				return
+			}
+
+			if len(fills) != 1 {
+				t.Fatalf("got %d fills, want 1", len(fills))
+			}
+
+			f := fills[0]
+			if !f.Price.Equal(d(tt.fillPrice)) || f.Maker != tt.maker || f.Side != tt.side {
+				t.Errorf("fill %s %s maker %v, want %s %s maker %v", f.Side, f.Price, f.Maker, tt.side, tt.fillPrice, tt.maker)
+			}
+
+			// 0.1% maker, 0.2% taker of the notional
+			rate := d("0.002")
+			if tt.maker {
+				rate = d("0.001")
+			}
+			if fee := d("2").Mul(d(tt.fillPrice)).Mul(rate); !f.Fee.Equal(fee) {
+				t.Errorf("fee %s, want %s", f.Fee, fee)
+			}
+		})
+	}
+}
+
+func TestBrokerLeverageFee(t *testing.T) {
+	symbol := testSymbol()
+	symbol.MarketType = currencycom.MarketTypeLeverage
+	symbol.TradingFee = d("0.05")
+
+	b := NewBroker(d("10000"), symbol)
+	b.Immediate = true
+	b.UpdatePrice("BTC/USD", start, d("100"))
+
+	if _, err := b.CreateOrder(&currencycom.CreateOrderRequest{Symbol: "BTC/USD", Side: currencycom.OrderSideBuy, Type: currencycom.OrderTypeMarket, Quantity: d("4")}); err != nil {
+		t.Fatal(err)
+	}
+
+	// TradingFee replaces maker and taker fees of leverage symbols
+	if fee := b.Fills()[0].Fee; !fee.Equal(d("0.2")) {
+		t.Errorf("fee %s, want 0.05%% of 400", fee)
+	}
+}
+
+func TestBrokerPartialClose(t *testing.T) {
+	b := NewBroker(d("10000"), testSymbol())
+	b.Immediate = true
+
+	b.UpdatePrice("BTC/USD", start, d("100"))
+	if _, err := b.CreateOrder(&currencycom.CreateOrderRequest{Symbol: "BTC/USD", Side: currencycom.OrderSideBuy, Type: currencycom.OrderTypeMarket, Quantity: d("3")}); err != nil {
+		t.Fatal(err)
+	}
+
+	// one swap of -0.01% on 300
+	b.UpdatePrice("BTC/USD", start.Add(time.Hour), d("100"))
+	b.UpdatePrice("BTC/USD", start.Add(90*time.Minute), d("110"))
+
+	if _, err := b.CreateOrder(&currencycom.CreateOrderRequest{Symbol: "BTC/USD", Side: currencycom.OrderSideSell, Type: currencycom.OrderTypeMarket, Quantity: d("1")}); err != nil {
+		t.Fatal(err)
+	}
+
+	p, ok := b.Position("BTC/USD")
+	if !ok {
+		t.Fatal("position closed, want 2 left")
+	}
+
+	// a third of the open fee 0.6 and of the swap -0.03 goes to the trade
+	assertDecimal(t, "position quantity", p.Quantity, "2")
+	assertDecimal(t, "position fee", p.Fee, "0.4")
+	assertDecimal(t, "position swap", p.Swap, "-0.02")
+
+	if _, err := b.ClosePosition(p.Id); err != nil {
+		t.Fatal(err)
+	}
+
+	trades := b.Trades()
+	if len(trades) != 2 {
+		t.Fatalf("got %d trades, want 2", len(trades))
+	}
+
+	want := []struct{ quantity, profit, fee, swap string }{
+		{"1", "10", "0.42", "-0.01"}, // 0.2 open + 0.22 close fee
+		{"2", "20", "0.84", "-0.02"}, // 0.4 open + 0.44 close fee
+	}
+	for i, w := range want {
+		assertDecimal(t, "trade quantity", trades[i].Quantity, w.quantity)
+		assertDecimal(t, "trade profit", trades[i].Profit, w.profit)
+		assertDecimal(t, "trade fee", trades[i].Fee, w.fee)
+		assertDecimal(t, "trade swap", trades[i].Swap, w.swap)
+	}
+
+	assertDecimal(t, "cash", b.Cash(), "10028.71")
+}
+
+func TestBrokerProRataPrecision(t *testing.T) {
+	b := NewBroker(d("10000"), testSymbol())
+	b.Immediate = true
+
+	// fees 0.2 and 0.404 make an open fee of 0.604 over 3 units
+	b.UpdatePrice("BTC/USD", start, d("100"))
+	buy := &currencycom.CreateOrderRequest{Symbol: "BTC/USD", Side: currencycom.OrderSideBuy, Type: currencycom.OrderTypeMarket, Quantity: d("1")}
+	if _, err := b.CreateOrder(buy); err != nil {
+		t.Fatal(err)
+	}
+
+	b.UpdatePrice("BTC/USD", start, d("101"))
+	buy.Quantity = d("2")
+	if _, err := b.CreateOrder(buy); err != nil {
+		t.Fatal(err)
+	}
+
+	// close 2 of 3, then reverse through the last one with a sell of 3
+	sell := &currencycom.CreateOrderRequest{Symbol: "BTC/USD", Side: currencycom.OrderSideSell, Type: currencycom.OrderTypeMarket, Quantity: d("2")}
+	if _, err := b.CreateOrder(sell); err != nil {
+		t.Fatal(err)
+	}
+
+	sell.Quantity = d("3")
+	if _, err := b.CreateOrder(sell); err != nil {
+		t.Fatal(err)
+	}
+
+	trades := b.Trades()
+	if len(trades) != 2 {
+		t.Fatalf("got %d trades, want 2", len(trades))
+	}
+
+	// 2/3 of 0.604 rounds once, dividing first would lose the last digit
+	openFee := d("1.208").Div(d("3"))
+	assertDecimal(t, "first trade fee", trades[0].Fee, openFee.Add(d("0.404")).String())
+
+	// a third of the reversing fee 0.606 closes the last unit, the rest
+	// stays with the new short
+	p, _ := b.Position("BTC/USD")
+	assertDecimal(t, "short quantity", p.Quantity, "-2")
+	assertDecimal(t, "short fee", p.Fee, "0.404")
+	assertDecimal(t, "second trade fee", trades[1].Fee, d("0.604").Sub(openFee).Add(d("0.202")).String())
+
+	// nothing is lost to rounding
+	total := trades[0].Fee.Add(trades[1].Fee).Add(p.Fee)
+	assertDecimal(t, "fees", total, b.Fees().String())
+}
+
+func TestComputeStats(t *testing.T) {
+	at := func(hour int) time.Time { return start.Add(time.Duration(hour) * time.Hour) }
+
+	equity := []EquityPoint{
+		{at(0), d("1000")},
+		{at(1), d("1100")},
+		{at(2), d("990")}, // 10% under 1100
+		{at(3), d("1050")},
+		{at(4), d("1200")},
+		{at(5), d("1140")}, // 5% under 1200
+	}
+
+	trades := []Trade{
+		{Profit: d("110"), Fee: d("10")},                 // +100
+		{Profit: d("-45"), Fee: d("5")},                  // -50
+		{Profit: d("30")},                                // +30
+		{Profit: d("-15"), Swap: d("-5")},                // -20
+		{Profit: d("1"), Fee: d("0.5"), Swap: d("-0.5")}, // break even
+	}
+
+	s := ComputeStats(d("1000"), equity, trades)
+
+	assertDecimal(t, "NetProfit", s.NetProfit, "140")
+	assertFloat(t, "Return", s.Return, 0.14)
+	assertFloat(t, "MaxDrawdown", s.MaxDrawdown, 0.1)
+	assertDecimal(t, "MaxDrawdownAmount", s.MaxDrawdownAmount, "110")
+	if s.MaxDrawdownDuration != 2*time.Hour {
+		t.Errorf("MaxDrawdownDuration = %v, want 2h", s.MaxDrawdownDuration)
+	}
+
+	if s.Trades != 5 || s.Wins != 2 || s.Losses != 2 {
+		t.Errorf("trades %d wins %d losses %d, want 5 2 2", s.Trades, s.Wins, s.Losses)
+	}
+	assertFloat(t, "WinRate", s.WinRate, 0.4)
+	assertFloat(t, "ProfitFactor", s.ProfitFactor, 130.0/70)
+	assertDecimal(t, "AverageWin", s.AverageWin, "65")
+	assertDecimal(t, "AverageLoss", s.AverageLoss, "-35")
+	assertDecimal(t, "LargestWin", s.LargestWin, "100")
+	assertDecimal(t, "LargestLoss", s.LargestLoss, "-50")
+	assertDecimal(t, "Fees", s.Fees, "15.5")
+	assertDecimal(t, "Swap", s.Swap, "-5.5")
+
+	noLosses := ComputeStats(d("1000"), nil, trades[:1])
+	if !math.IsInf(noLosses.ProfitFactor, 1) {
+		t.Errorf("ProfitFactor without losses = %v, want +Inf", noLosses.ProfitFactor)
+	}
+}
+
+func assertDecimal(t *testing.T, name string, got currencycom.Decimal, want string) {
+	t.Helper()
+
+	if !got.Equal(d(want)) {
+		t.Errorf("%s = %s, want %s", name, got, want)
+	}
+}
+
+func assertFloat(t *testing.T, name string, got, want float64) {
+	t.Helper()
+
+	if math.Abs(got-want) > 1e-9 {
+		t.Errorf("%s = %v, want %v", name, got, want)
+	}
+}
+
+func TestBrokerUpdateOrder(t *testing.T) {
+	b := NewBroker(d("10000"), testSymbol())
+
+	limit, err := b.CreateOrder(&currencycom.CreateOrderRequest{Symbol: "BTC/USD", Side: currencycom.OrderSideBuy, Type: currencycom.OrderTypeLimit, Quantity: d("1"), Price: d("90")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	market, err := b.CreateOrder(&currencycom.CreateOrderRequest{Symbol: "BTC/USD", Side: currencycom.OrderSideBuy, Type: currencycom.OrderTypeMarket, Quantity: d("1")})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	o, err := b.UpdateOrder(limit.OrderId, d("95"), d("80"), d("120"), 1700000000000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !o.Request.Price.Equal(d("95")) || !o.Request.StopLoss.Equal(d("80")) || !o.Request.TakeProfit.Equal(d("120")) || o.Request.ExpireTimestamp != 1700000000000 {
+		t.Errorf("updated %+v, want price 95, sl 80, tp 120 and the expiry", o.Request)
+	}
+
+	// zero values keep what was set
+	o, err = b.UpdateOrder(limit.OrderId, currencycom.Decimal{}, currencycom.Decimal{}, d("125"), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !o.Request.Price.Equal(d("95")) || !o.Request.StopLoss.Equal(d("80")) || !o.Request.TakeProfit.Equal(d("125")) || o.Request.ExpireTimestamp != 1700000000000 {
+		t.Errorf("updated %+v, want only the take profit changed", o.Request)
+	}
+
+	if _, err := b.UpdateOrder(market.OrderId, d("100"), currencycom.Decimal{}, currencycom.Decimal{}, 0); !errors.Is(err, currencycom.ErrInvalidOrder) {
+		t.Errorf("price of a market order: err = %v, want ErrInvalidOrder", err)
+	}
+	if _, err := b.UpdateOrder("order-404", d("100"), currencycom.Decimal{}, currencycom.Decimal{}, 0); !errors.Is(err, currencycom.ErrOrderNotFound) {
+		t.Errorf("unknown order: err = %v, want ErrOrderNotFound", err)
+	}
+
+	// the new price is the one the next bar is matched against
+	b.Update("BTC/USD", hourBar(0, "100", "101", "94", "96"))
+	fills := b.Fills()
+	if len(fills) != 2 || fills[0].OrderId != limit.OrderId || !fills[0].Price.Equal(d("95")) {
+		t.Errorf("fills %+v, want the limit at 95 and the market at the open", fills)
+	}
+}
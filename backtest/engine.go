@@ -0,0 +1,105 @@
+// Package backtest replays a strategy over historical klines or aggregated
+// trades with simulated fills, fees and swap:
+//
+//	engine := backtest.NewEngine(info, currencycom.NewDecimalFromInt(10000))
+//	result := engine.Run(strategy, klines)
+//	fmt.Println(result.Stats.Return, result.Stats.MaxDrawdown)
+//
+// Strategies place orders with the CreateOrderRequest sent to
+// RestAPI.CreateOrder, so the same code can trade live.
+package backtest
+
+import (
+	"time"
+
+	currencycom "github.com/scientistnik/currency.com"
+)
+
+// Strategy is called at the close of every bar. Market orders it places
+// fill at the open of the next bar.
+type Strategy interface {
+	OnBar(b *Broker, bar currencycom.Kline)
+}
+
+// TradeStrategy is called after every trade. Market orders it places fill
+// at the next trade.
+type TradeStrategy interface {
+	OnTrade(b *Broker, trade currencycom.AggTrades)
+}
+
+// StrategyFunc adapts a function to Strategy.
+type StrategyFunc func(b *Broker, bar currencycom.Kline)
+
+func (f StrategyFunc) OnBar(b *Broker, bar currencycom.Kline) { f(b, bar) }
+
+// TradeStrategyFunc adapts a function to TradeStrategy.
+type TradeStrategyFunc func(b *Broker, trade currencycom.AggTrades)
+
+func (f TradeStrategyFunc) OnTrade(b *Broker, trade currencycom.AggTrades) { f(b, trade) }
+
+// Engine runs a strategy over the history of one symbol. An engine runs
+// once, the broker keeps its state.
+type Engine struct {
+	Symbol currencycom.ExchangeSymbolInfo
+
+	// Broker is the simulated account, e.g. to set a Validator before Run.
+	Broker *Broker
+
+	// CloseAtEnd closes open positions at the last price, so they count in
+	// the trade statistics.
+	CloseAtEnd bool
+
+	start currencycom.Decimal
+}
+
+// NewEngine creates an engine trading symbol with a cash balance.
+func NewEngine(symbol currencycom.ExchangeSymbolInfo, cash currencycom.Decimal) *Engine {
+	return &Engine{Symbol: symbol, Broker: NewBroker(cash, symbol), start: cash}
+}
+
+// Run replays klines, oldest first.
+func (e *Engine) Run(s Strategy, klines []currencycom.Kline) *Result {
+	var equity []EquityPoint
+
+	for _, k := range klines {
+		e.Broker.Update(e.Symbol.Symbol, k)
+		s.OnBar(e.Broker, k)
+		equity = append(equity, EquityPoint{Time: e.Broker.Now(), Equity: e.Broker.Equity()})
+	}
+
+	return e.result(equity)
+}
+
+// RunTrades replays aggregated trades, oldest first.
+func (e *Engine) RunTrades(s TradeStrategy, trades []currencycom.AggTrades) *Result {
+	var equity []EquityPoint
+
+	for _, t := range trades {
+		e.Broker.UpdatePrice(e.Symbol.Symbol, time.UnixMilli(t.Timestamp), t.Price)
+		s.OnTrade(e.Broker, t)
+		equity = append(equity, EquityPoint{Time: e.Broker.Now(), Equity: e.Broker.Equity()})
+	}
+
+	return e.result(equity)
+}
+
+func (e *Engine) result(equity []EquityPoint) *Result {
+	if e.CloseAtEnd {
+		for _, p := range e.Broker.Positions() {
+			e.Broker.ClosePosition(p.Id)
+		}
+
+		if len(equity) > 0 {
+			equity[len(equity)-1].Equity = e.Broker.Equity()
+		}
+	}
+
+	trades := e.Broker.Trades()
+
+	return &Result{
+		Equity: equity,
+		Trades: trades,
+		Fills:  e.Broker.Fills(),
+		Stats:  ComputeStats(e.start, equity, trades),
+	}
+}
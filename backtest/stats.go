@@ -0,0 +1,124 @@
+package backtest
+
+import (
+	"math"
+	"time"
+
+	currencycom "github.com/scientistnik/currency.com"
+)
+
+// EquityPoint is the equity at a time.
+type EquityPoint struct {
+	Time   time.Time
+	Equity currencycom.Decimal
+}
+
+// Result is the outcome of a run.
+type Result struct {
+	Equity []EquityPoint // after every bar or trade
+	Trades []Trade
+	Fills  []Fill
+	Stats  Stats
+}
+
+// Stats summarizes a run. Wins and losses are counted by the net result of
+// trades, after fees and swap.
+type Stats struct {
+	StartEquity currencycom.Decimal
+	EndEquity   currencycom.Decimal
+	NetProfit   currencycom.Decimal
+	Return      float64 // fraction of StartEquity
+
+	MaxDrawdown         float64 // largest fall from a peak, fraction of the peak
+	MaxDrawdownAmount   currencycom.Decimal
+	MaxDrawdownDuration time.Duration // longest time under a previous peak
+
+	Fees currencycom.Decimal // of the closed trades
+	Swap currencycom.Decimal // of the closed trades, negative when paid
+
+	Trades       int
+	Wins         int
+	Losses       int
+	WinRate      float64
+	ProfitFactor float64 // gross win / gross loss, +Inf without losses
+	AverageWin   currencycom.Decimal
+	AverageLoss  currencycom.Decimal // negative
+	LargestWin   currencycom.Decimal
+	LargestLoss  currencycom.Decimal // negative
+}
+
+// ComputeStats summarizes an equity curve and trades starting from start.
+func ComputeStats(start currencycom.Decimal, equity []EquityPoint, trades []Trade) Stats {
+	s := Stats{StartEquity: start, EndEquity: start}
+	if len(equity) > 0 {
+		s.EndEquity = equity[len(equity)-1].Equity
+	}
+
+	s.NetProfit = s.EndEquity.Sub(start)
+	if start.IsPositive() {
+		s.Return = s.NetProfit.Div(start).Float64()
+	}
+
+	peak := start
+	var peakTime time.Time
+	if len(equity) > 0 {
+		peakTime = equity[0].Time
+	}
+
+	for _, p := range equity {
+		if p.Equity.GreaterThanOrEqual(peak) {
+			peak, peakTime = p.Equity, p.Time
+			continue
+		}
+
+		drawdown := peak.Sub(p.Equity)
+		if drawdown.GreaterThan(s.MaxDrawdownAmount) {
+			s.MaxDrawdownAmount = drawdown
+		}
+		if peak.IsPositive() {
+			s.MaxDrawdown = math.Max(s.MaxDrawdown, drawdown.Div(peak).Float64())
+		}
+		if d := p.Time.Sub(peakTime); d > s.MaxDrawdownDuration {
+			s.MaxDrawdownDuration = d
+		}
+	}
+
+	var grossWin, grossLoss currencycom.Decimal
+	for _, t := range trades {
+		net := t.Net()
+
+		s.Fees = s.Fees.Add(t.Fee)
+		s.Swap = s.Swap.Add(t.Swap)
+		s.Trades++
+
+		switch {
+		case net.IsPositive():
+			s.Wins++
+			grossWin = grossWin.Add(net)
+			s.LargestWin = currencycom.MaxDecimal(s.LargestWin, net)
+		case net.IsNegative():
+			s.Losses++
+			grossLoss = grossLoss.Add(net)
+			s.LargestLoss = currencycom.MinDecimal(s.LargestLoss, net)
+		}
+	}
+
+	if s.Trades > 0 {
+		s.WinRate = float64(s.Wins) / float64(s.Trades)
+	}
+	if s.Wins > 0 {
+		s.AverageWin = grossWin.Div(currencycom.NewDecimalFromInt(int64(s.Wins)))
+	}
+	if s.Losses > 0 {
+		s.AverageLoss = grossLoss.Div(currencycom.NewDecimalFromInt(int64(s.Losses)))
+	}
+
+	switch {
+	case !grossLoss.IsZero():
+		s.ProfitFactor = grossWin.Div(grossLoss.Neg()).Float64()
+	case !grossWin.IsZero():
+		s.ProfitFactor = math.Inf(1)
+	}
+
+	return s
+}
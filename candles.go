@@ -0,0 +1,241 @@
+package currencycom
+
+import (
+	"fmt"
+	"time"
+)
+
+// mondayEpoch is the first Monday after the Unix epoch, weekly bars are
+// aligned to it.
+var mondayEpoch = time.Date(1970, 1, 5, 0, 0, 0, 0, time.UTC)
+
+// CandleBuilder builds bars of any interval, including ones the exchange
+// doesn't serve like 2h or 3d, from finer klines or from trades. Data must
+// come in time order, data older than the bar in progress is ignored.
+type CandleBuilder struct {
+	Interval time.Duration
+
+	// Origin is the open time of any bar, the others are aligned to it,
+	// e.g. 17:00 New York for FX days. Zero means the Unix epoch, or the
+	// first Monday after it for intervals of whole weeks.
+	Origin time.Time
+
+	// Hours, when set, starts a bar at every session open and cuts the
+	// last bar of a session at its close, e.g. 2h bars of a 09:30 - 16:00
+	// session open at 09:30, 11:30, 13:30 and 15:30. Data outside the
+	// sessions is grouped as without Hours, cut at the session edges so
+	// off-session bars never overlap a session.
+	Hours *TradingHours
+
+	// OnBar is called with every completed bar.
+	OnBar func(Kline)
+
+	current Kline
+	started bool
+	empty   bool      // the bar in progress has no data yet
+	closeAt time.Time // end of the bar in progress
+}
+
+// NewCandleBuilder creates a builder of interval bars.
+func NewCandleBuilder(interval time.Duration, onBar func(Kline)) *CandleBuilder {
+	return &CandleBuilder{Interval: interval, OnBar: onBar}
+}
+
+// AddKline adds a finer bar, it belongs to the bar containing its open
+// time.
+func (b *CandleBuilder) AddKline(k Kline) error {
+	if b.Interval <= 0 {
+		return fmt.Errorf("error params: Interval need to be positive")
+	}
+
+	if !b.bar(k.OpenTime) {
+		return nil
+	}
+
+	if b.empty {
+		b.current.Open, b.current.High, b.current.Low = k.Open, k.High, k.Low
+		b.empty = false
+	} else {
+		b.current.High = MaxDecimal(b.current.High, k.High)
+		b.current.Low = MinDecimal(b.current.Low, k.Low)
+	}
+
+	b.current.Close = k.Close
+	b.current.Volume = b.current.Volume.Add(k.Volume)
+
+	return nil
+}
+
+// AddTrade adds a trade of quantity at price.
+func (b *CandleBuilder) AddTrade(at time.Time, price, quantity Decimal) error {
+	return b.AddKline(Kline{OpenTime: at, Open: price, High: price, Low: price, Close: price, Volume: quantity})
+}
+
+// AddAggTrade adds an aggregated trade.
+func (b *CandleBuilder) AddAggTrade(t AggTrades) error {
+	return b.AddTrade(time.UnixMilli(t.Timestamp), t.Price, t.Quantity)
+}
+
+// Current returns the bar in progress.
+func (b *CandleBuilder) Current() (Kline, bool) {
+	return b.current, b.started
+}
+
+// Flush completes the bar in progress, e.g. at the end of the data.
+func (b *CandleBuilder) Flush() {
+	if !b.started {
+		return
+	}
+
+	b.started = false
+	if b.OnBar != nil {
+		b.OnBar(b.current)
+	}
+}
+
+// bar makes the bar containing t current, completing the previous one.
+// It returns false if t is older than the bar in progress.
+func (b *CandleBuilder) bar(t time.Time) bool {
+	if b.started && t.Before(b.current.OpenTime) {
+		return false
+	}
+
+	if b.started && t.Before(b.closeAt) {
+		return true
+	}
+
+	b.Flush()
+
+	openAt, closeAt := b.bounds(t)
+	b.current = Kline{OpenTime: openAt, CloseTime: closeAt.Add(-time.Millisecond)}
+	b.closeAt = closeAt
+	b.started, b.empty = true, true
+
+	return true
+}
+
+// bounds returns the open and close time of the bar containing t.
+func (b *CandleBuilder) bounds(t time.Time) (time.Time, time.Time) {
+	if b.Hours != nil && len(b.Hours.Sessions) > 0 {
+		for _, s := range b.Hours.spans(t) {
+			if t.Before(s.open) || !t.Before(s.close) {
+				continue
+			}
+
+			openAt := s.open.Add(t.Sub(s.open) / b.Interval * b.Interval)
+			closeAt := openAt.Add(b.Interval)
+			if closeAt.After(s.close) {
+				closeAt = s.close
+			}
+
+			return openAt, closeAt
+		}
+	}
+
+	origin := b.Origin
+	if origin.IsZero() {
+		origin = time.Unix(0, 0)
+		if b.Interval%(7*24*time.Hour) == 0 {
+			origin = mondayEpoch
+		}
+	}
+
+	n := t.Sub(origin) / b.Interval
+	if t.Before(origin.Add(n * b.Interval)) {
+		n-- // floor for times before the origin
+	}
+
+	openAt := origin.Add(n * b.Interval)
+	closeAt := openAt.Add(b.Interval)
+
+	// an off-session bar starts no earlier than the last session close and
+	// ends no later than the next session open
+	if b.Hours != nil && len(b.Hours.Sessions) > 0 {
+		for _, s := range b.Hours.spans(t) {
+			if !s.close.After(t) && s.close.After(openAt) {
+				openAt = s.close
+			}
+
+			if s.open.After(t) && s.open.Before(closeAt) {
+				closeAt = s.open
+			}
+		}
+	}
+
+	return openAt, closeAt
+}
+
+// ResampleKlines joins finer klines into bars of interval. The last bar
+// may be incomplete.
+func ResampleKlines(klines []Kline, interval time.Duration) ([]Kline, error) {
+	var out []Kline
+
+	b := NewCandleBuilder(interval, func(k Kline) { out = append(out, k) })
+	for _, k := range klines {
+		if err := b.AddKline(k); err != nil {
+			return nil, err
+		}
+	}
+	b.Flush()
+
+	return out, nil
+}
+
+// KlinesFromAggTrades builds bars of interval from aggregated trades. The
+// last bar may be incomplete.
+func KlinesFromAggTrades(trades []AggTrades, interval time.Duration) ([]Kline, error) {
+	var out []Kline
+
+	b := NewCandleBuilder(interval, func(k Kline) { out = append(out, k) })
+	for _, t := range trades {
+		if err := b.AddAggTrade(t); err != nil {
+			return nil, err
+		}
+	}
+	b.Flush()
+
+	return out, nil
+}
+
+// HeikinAshi converts classic bars to Heikin-Ashi one at a time, for
+// streams. The zero value is ready to use.
+type HeikinAshi struct {
+	open, close Decimal
+	started     bool
+}
+
+// Next converts the next classic bar.
+func (h *HeikinAshi) Next(k Kline) Kline {
+	four := NewDecimalFromInt(4)
+	two := NewDecimalFromInt(2)
+
+	haClose := k.Open.Add(k.High).Add(k.Low).Add(k.Close).Div(four)
+	haOpen := k.Open.Add(k.Close).Div(two)
+	if h.started {
+		haOpen = h.open.Add(h.close).Div(two)
+	}
+
+	h.open, h.close, h.started = haOpen, haClose, true
+
+	return Kline{
+		OpenTime:  k.OpenTime,
+		CloseTime: k.CloseTime,
+		Open:      haOpen,
+		High:      MaxDecimal(k.High, haOpen, haClose),
+		Low:       MinDecimal(k.Low, haOpen, haClose),
+		Close:     haClose,
+		Volume:    k.Volume,
+	}
+}
+
+// ToHeikinAshi converts classic bars to Heikin-Ashi bars.
+func ToHeikinAshi(klines []Kline) []Kline {
+	var h HeikinAshi
+
+	out := make([]Kline, len(klines))
+	for i, k := range klines {
+		out[i] = h.Next(k)
+	}
+
+	return out
+}
@@ -0,0 +1,212 @@
+package currencycom
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCandleBuilderSessions(t *testing.T) {
+	hours, err := ParseTradingHours("UTC; Mon 09:30 - 16:00", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	monday := time.Date(2021, 6, 7, 0, 0, 0, 0, time.UTC)
+	at := func(h, m int) time.Time { return monday.Add(time.Duration(h)*time.Hour + time.Duration(m)*time.Minute) }
+
+	var bars []Kline
+	b := NewCandleBuilder(2*time.Hour, func(k Kline) { bars = append(bars, k) })
+	b.Hours = hours
+
+	// 30m klines from 08:00 to 17:00, one unit of volume each
+	for ts := at(8, 0); ts.Before(at(17, 0)); ts = ts.Add(30 * time.Minute) {
+		if err := b.AddKline(Kline{OpenTime: ts, Open: NewDecimalFromInt(1), High: NewDecimalFromInt(1), Low: NewDecimalFromInt(1), Close: NewDecimalFromInt(1), Volume: NewDecimalFromInt(1)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	b.Flush()
+
+	want := []struct {
+		open, close time.Time
+		volume      int64
+	}{
+		{at(8, 0), at(9, 30), 3}, // off-session, cut at the session open
+		{at(9, 30), at(11, 30), 4},
+		{at(11, 30), at(13, 30), 4},
+		{at(13, 30), at(15, 30), 4},
+		{at(15, 30), at(16, 0), 1}, // cut at the session close
+		{at(16, 0), at(18, 0), 2},
+	}
+
+	if len(bars) != len(want) {
+		t.Fatalf("got %d bars, want %d: %v", len(bars), len(want), bars)
+	}
+
+	for i, w := range want {
+		bar := bars[i]
+		if !bar.OpenTime.Equal(w.open) || !bar.CloseTime.Equal(w.close.Add(-time.Millisecond)) || !bar.Volume.Equal(NewDecimalFromInt(w.volume)) {
+			t.Errorf("bar %d = %v - %v volume %v, want %v - %v volume %d",
+				i, bar.OpenTime, bar.CloseTime, bar.Volume, w.open, w.close, w.volume)
+		}
+	}
+}
+
+func TestCandleBuilderInterval(t *testing.T) {
+	b := NewCandleBuilder(0, nil)
+	if err := b.AddTrade(time.Now(), NewDecimalFromInt(1), NewDecimalFromInt(1)); err == nil {
+		t.Error("AddTrade with zero Interval: want error")
+	}
+
+	if _, err := ResampleKlines([]Kline{{OpenTime: time.Now()}}, 0); err == nil {
+		t.Error("ResampleKlines with zero interval: want error")
+	}
+}
+
+func TestResampleKlines(t *testing.T) {
+	base := time.Date(2021, 6, 7, 10, 0, 0, 0, time.UTC)
+	minute := func(m int) time.Time { return base.Add(time.Duration(m) * time.Minute) }
+
+	// 1m klines from 10:03 to 10:12, then one at 10:21 after a gap
+	var klines []Kline
+	for i := 0; i < 10; i++ {
+		open := int64(100 + i)
+		klines = append(klines, Kline{
+			OpenTime: minute(3 + i),
+			Open:     NewDecimalFromInt(open),
+			High:     NewDecimalFromInt(open + 2),
+			Low:      NewDecimalFromInt(open - 1),
+			Close:    NewDecimalFromInt(open + 1),
+			Volume:   NewDecimalFromInt(int64(i + 1)),
+		})
+	}
+	klines = append(klines,
+		Kline{OpenTime: minute(21), Open: MustDecimal("120"), High: MustDecimal("121"), Low: MustDecimal("119"), Close: MustDecimal("120.5"), Volume: MustDecimal("1")},
+		// older than the bar in progress, ignored
+		Kline{OpenTime: minute(2), Open: MustDecimal("1"), High: MustDecimal("1000"), Low: MustDecimal("1"), Close: MustDecimal("1"), Volume: MustDecimal("1")},
+	)
+
+	bars, err := ResampleKlines(klines, 5*time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []struct {
+		open               time.Time
+		o, h, l, c, volume string
+	}{
+		{minute(0), "100", "103", "99", "102", "3"},
+		{minute(5), "102", "108", "101", "107", "25"},
+		{minute(10), "107", "111", "106", "110", "27"},
+		{minute(20), "120", "121", "119", "120.5", "1"},
+	}
+
+	if len(bars) != len(want) {
+		t.Fatalf("got %d bars, want %d: %v", len(bars), len(want), bars)
+	}
+
+	for i, w := range want {
+		bar := bars[i]
+		if !bar.OpenTime.Equal(w.open) || !bar.CloseTime.Equal(w.open.Add(5*time.Minute-time.Millisecond)) {
+			t.Errorf("bar %d = %v - %v, want %v", i, bar.OpenTime, bar.CloseTime, w.open)
+		}
+		if !bar.Open.Equal(MustDecimal(w.o)) || !bar.High.Equal(MustDecimal(w.h)) || !bar.Low.Equal(MustDecimal(w.l)) ||
+			!bar.Close.Equal(MustDecimal(w.c)) || !bar.Volume.Equal(MustDecimal(w.volume)) {
+			t.Errorf("bar %d = %s %s %s %s %s, want %s %s %s %s %s", i,
+				bar.Open, bar.High, bar.Low, bar.Close, bar.Volume, w.o, w.h, w.l, w.c, w.volume)
+		}
+	}
+}
+
+func TestCandleBuilderAlignment(t *testing.T) {
+	newYork := time.FixedZone("EST", -5*3600)
+
+	tests := []struct {
+		name     string
+		interval time.Duration
+		origin   time.Time
+		at       time.Time
+		open     time.Time
+	}{
+		{"hours from the epoch", 2 * time.Hour, time.Time{}, time.Date(2021, 6, 2, 13, 59, 0, 0, time.UTC), time.Date(2021, 6, 2, 12, 0, 0, 0, time.UTC)},
+		{"weeks from a monday", 7 * 24 * time.Hour, time.Time{}, time.Date(2021, 6, 6, 23, 0, 0, 0, time.UTC), time.Date(2021, 5, 31, 0, 0, 0, 0, time.UTC)},
+		{"days from the origin", 24 * time.Hour, time.Date(2021, 1, 4, 17, 0, 0, 0, newYork), time.Date(2021, 6, 3, 10, 0, 0, 0, newYork), time.Date(2021, 6, 2, 17, 0, 0, 0, newYork)},
+		{"before the origin", 24 * time.Hour, time.Date(2021, 1, 4, 17, 0, 0, 0, newYork), time.Date(2020, 12, 31, 10, 0, 0, 0, newYork), time.Date(2020, 12, 30, 17, 0, 0, 0, newYork)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewCandleBuilder(tt.interval, nil)
+			b.Origin = tt.origin
+
+			if err := b.AddTrade(tt.at, NewDecimalFromInt(1), NewDecimalFromInt(1)); err != nil {
+				t.Fatal(err)
+			}
+
+			bar, ok := b.Current()
+			if !ok || !bar.OpenTime.Equal(tt.open) || !bar.CloseTime.Equal(tt.open.Add(tt.interval-time.Millisecond)) {
+				t.Errorf("bar %v - %v, want open at %v", bar.OpenTime, bar.CloseTime, tt.open)
+			}
+		})
+	}
+}
+
+func TestHeikinAshi(t *testing.T) {
+	base := time.Date(2021, 6, 7, 10, 0, 0, 0, time.UTC)
+
+	classic := []Kline{
+		{Open: MustDecimal("10"), High: MustDecimal("12"), Low: MustDecimal("9"), Close: MustDecimal("11"), Volume: MustDecimal("1")},
+		{Open: MustDecimal("11"), High: MustDecimal("13"), Low: MustDecimal("10"), Close: MustDecimal("12"), Volume: MustDecimal("2")},
+		{Open: MustDecimal("12"), High: MustDecimal("12.5"), Low: MustDecimal("11"), Close: MustDecimal("11.5"), Volume: MustDecimal("3")},
+		// a gap down leaves the Heikin-Ashi open over the classic high
+		{Open: MustDecimal("8"), High: MustDecimal("8.5"), Low: MustDecimal("7"), Close: MustDecimal("8"), Volume: MustDecimal("4")},
+	}
+	for i := range classic {
+		classic[i].OpenTime = base.Add(time.Duration(i) * time.Hour)
+		classic[i].CloseTime = classic[i].OpenTime.Add(time.Hour - time.Millisecond)
+	}
+
+	// open: (O+C)/2 of the first bar, then (haOpen+haClose)/2 of the
+	// previous one; close: (O+H+L+C)/4; high and low include both
+	want := []struct{ o, h, l, c string }{
+		{"10.5", "12", "9", "10.5"},
+		{"10.5", "13", "10", "11.5"},
+		{"11", "12.5", "11", "11.75"},
+		{"11.375", "11.375", "7", "7.875"},
+	}
+
+	check := func(t *testing.T, bars []Kline) {
+		t.Helper()
+
+		if len(bars) != len(want) {
+			t.Fatalf("got %d bars, want %d", len(bars), len(want))
+		}
+
+		for i, w := range want {
+			bar := bars[i]
+			if !bar.Open.Equal(MustDecimal(w.o)) || !bar.High.Equal(MustDecimal(w.h)) || !bar.Low.Equal(MustDecimal(w.l)) || !bar.Close.Equal(MustDecimal(w.c)) {
+				t.Errorf("bar %d = %s %s %s %s, want %s %s %s %s", i, bar.Open, bar.High, bar.Low, bar.Close, w.o, w.h, w.l, w.c)
+			}
+			if !bar.OpenTime.Equal(classic[i].OpenTime) || !bar.CloseTime.Equal(classic[i].CloseTime) || !bar.Volume.Equal(classic[i].Volume) {
+				t.Errorf("bar %d = %v - %v volume %s, want the times and volume of the classic bar", i, bar.OpenTime, bar.CloseTime, bar.Volume)
+			}
+		}
+	}
+
+	t.Run("ToHeikinAshi", func(t *testing.T) {
+		check(t, ToHeikinAshi(classic))
+	})
+
+	t.Run("Next", func(t *testing.T) {
+		var h HeikinAshi
+
+		var bars []Kline
+		for _, k := range classic {
+			bars = append(bars, h.Next(k))
+		}
+		check(t, bars)
+	})
+
+	if bars := ToHeikinAshi(nil); len(bars) != 0 {
+		t.Errorf("ToHeikinAshi(nil) = %v, want none", bars)
+	}
+}
@@ -0,0 +1,134 @@
+package currencycom
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+)
+
+// Client gives access to the public market data endpoints and needs no
+// credentials. Clients are independent, so several of them can point to
+// different endpoints in one process.
+type Client struct {
+	Endpoint   string
+	httpClient *http.Client
+	retry      RetryPolicy
+	limiter    *RateLimiter
+}
+
+// defaultClientValue holds the *Client serving the package-level market
+// data functions. It is never changed in place: setters store a changed
+// copy, so requests in flight keep the settings they started with.
+var (
+	defaultClientValue atomic.Value
+	defaultClientMu    sync.Mutex // serializes the setters
+)
+
+func init() {
+	defaultClientValue.Store(NewClient(DEFAULT_ENDPOINT))
+}
+
+// defaultClient returns the client of the package-level functions.
+func defaultClient() *Client {
+	return defaultClientValue.Load().(*Client)
+}
+
+// updateDefaultClient replaces the default client with a copy changed by
+// update, safe to call while package-level functions run.
+func updateDefaultClient(update func(c *Client)) {
+	defaultClientMu.Lock()
+	defer defaultClientMu.Unlock()
+
+	c := *defaultClient()
+	update(&c)
+	defaultClientValue.Store(&c)
+}
+
+func newClient(endpoint string) Client {
+	if endpoint == "" {
+		endpoint = DEFAULT_ENDPOINT
+	}
+
+	return Client{
+		Endpoint:   endpoint,
+		httpClient: &http.Client{Timeout: DEFAULT_TIMEOUT},
+		retry:      DefaultRetryPolicy,
+	}
+}
+
+// NewClient creates a market data client for the endpoint, empty endpoint
+// means DEFAULT_ENDPOINT.
+func NewClient(endpoint string, opts ...Option) *Client {
+	api := &RestAPI{Client: newClient(endpoint)}
+	for _, opt := range opts {
+		opt(api)
+	}
+
+	return &api.Client
+}
+
+// SetHTTPClient replaces the client used by the package-level market data
+// functions (ServerTime, Klines, OrderBook, ...). It is safe to call while
+// they run.
+func SetHTTPClient(client *http.Client) {
+	if client == nil {
+		client = &http.Client{Timeout: DEFAULT_TIMEOUT}
+	}
+
+	updateDefaultClient(func(c *Client) {
+		c.httpClient = client
+	})
+}
+
+func ServerTime() (*ServerTimeResponse, error) {
+	return defaultClient().ServerTime()
+}
+
+func ServerTimeWithContext(ctx context.Context) (*ServerTimeResponse, error) {
+	return defaultClient().ServerTimeWithContext(ctx)
+}
+
+func TradesAggregated(params *AggTradesRequest) ([]AggTrades, error) {
+	return defaultClient().TradesAggregated(params)
+}
+
+func TradesAggregatedWithContext(ctx context.Context, params *AggTradesRequest) ([]AggTrades, error) {
+	return defaultClient().TradesAggregatedWithContext(ctx, params)
+}
+
+func TradesAggregatedIterator(ctx context.Context, params *AggTradesRequest) *AggTradeIterator {
+	return defaultClient().TradesAggregatedIterator(ctx, params)
+}
+
+func OrderBook(params *DepthRequest) (*DepthResponse, error) {
+	return defaultClient().OrderBook(params)
+}
+
+func OrderBookWithContext(ctx context.Context, params *DepthRequest) (*DepthResponse, error) {
+	return defaultClient().OrderBookWithContext(ctx, params)
+}
+
+func ExchangeInfo() (*ExchangeInfoResponse, error) {
+	return defaultClient().ExchangeInfo()
+}
+
+func ExchangeInfoWithContext(ctx context.Context) (*ExchangeInfoResponse, error) {
+	return defaultClient().ExchangeInfoWithContext(ctx)
+}
+
+func Klines(params *KLinesRequest) ([]Kline, error) {
+	return defaultClient().Klines(params)
+}
+
+func KlinesWithContext(ctx context.Context, params *KLinesRequest) ([]Kline, error) {
+	return defaultClient().KlinesWithContext(ctx, params)
+}
+
+func PriceChange(params *BySymbolRequest) (*Ticker24hr, error) {
+	return defaultClient().PriceChange(params)
+}
+
+func PriceChangeWithContext(ctx context.Context, params *BySymbolRequest) (*Ticker24hr, error) {
+	return defaultClient().PriceChangeWithContext(ctx, params)
+}
@@ -0,0 +1,73 @@
+package currencycom
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+)
+
+// redirectTransport sends every request to target, whatever its host.
+type redirectTransport struct {
+	target *url.URL
+}
+
+func (t redirectTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	r = r.Clone(r.Context())
+	r.URL.Scheme, r.URL.Host = t.target.Scheme, t.target.Host
+
+	return http.DefaultTransport.RoundTrip(r)
+}
+
+// TestDefaultClientSetters changes the default client while package-level
+// calls run, run it with -race.
+func TestDefaultClientSetters(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"serverTime":1600000000000}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	saved := defaultClient()
+	t.Cleanup(func() { defaultClientValue.Store(saved) })
+
+	target, _ := url.Parse(srv.URL)
+	httpClient := &http.Client{Transport: redirectTransport{target}}
+	SetHTTPClient(httpClient)
+
+	ctx := context.Background()
+	var wg sync.WaitGroup
+
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+
+		go func() {
+			defer wg.Done()
+
+			for j := 0; j < 20; j++ {
+				if _, err := ServerTimeWithContext(ctx); err != nil {
+					t.Error(err)
+					return
+				}
+			}
+		}()
+
+		go func() {
+			defer wg.Done()
+
+			for j := 0; j < 20; j++ {
+				SetRetryPolicy(RetryPolicy{MaxAttempts: 2, MinBackoff: time.Millisecond})
+				SetRateLimiter(NewRateLimiter(RateLimitBlock))
+				SetHTTPClient(httpClient)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if got := defaultClient().retry.MaxAttempts; got != 2 {
+		t.Errorf("retry MaxAttempts = %d, want 2", got)
+	}
+}
@@ -0,0 +1,144 @@
+package currencycom
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// serverClock keeps the difference between the exchange and the local
+// clock, used to stamp signed requests.
+type serverClock struct {
+	interval time.Duration
+
+	mu       sync.Mutex
+	offset   time.Duration
+	syncedAt time.Time
+	failures int        // failed syncs in a row
+	retryAt  time.Time  // no automatic sync before it after a failure
+	inflight *clockSync // the sync in progress, nil if none
+}
+
+// clockSync is a server time request shared by the callers that need the
+// offset at the same time.
+type clockSync struct {
+	done chan struct{}
+	err  error
+}
+
+// bounds of the backoff between failed automatic syncs
+const (
+	clockRetryMin = time.Second
+	clockRetryMax = time.Minute
+)
+
+// WithServerTimeSync makes RestAPI measure the offset of the local clock
+// against ServerTime and use it for the timestamp of signed requests.
+// The offset is measured before the first signed request, then every
+// interval (0 means only once) and after every timestamp rejection.
+func WithServerTimeSync(interval time.Duration) Option {
+	return func(r *RestAPI) {
+		r.clock = &serverClock{interval: interval}
+	}
+}
+
+// SyncTime measures the offset of the local clock against the server
+// time. It is a no-op unless RestAPI is created with WithServerTimeSync.
+func (r RestAPI) SyncTime(ctx context.Context) error {
+	if r.clock == nil {
+		return nil
+	}
+
+	return r.clock.sync(ctx, &r)
+}
+
+// TimeOffset returns the last measured difference between the server and
+// the local clock.
+func (r RestAPI) TimeOffset() time.Duration {
+	if r.clock == nil {
+		return 0
+	}
+
+	r.clock.mu.Lock()
+	defer r.clock.mu.Unlock()
+
+	return r.clock.offset
+}
+
+// sync measures the offset. Concurrent callers share one server time
+// request: the first one sends it and the others wait for its result.
+func (c *serverClock) sync(ctx context.Context, r *RestAPI) error {
+	c.mu.Lock()
+	if call := c.inflight; call != nil {
+		c.mu.Unlock()
+
+		select {
+		case <-call.done:
+			return call.err
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	call := &clockSync{done: make(chan struct{})}
+	c.inflight = call
+	c.mu.Unlock()
+
+	call.err = c.measure(ctx, r)
+
+	c.mu.Lock()
+	c.inflight = nil
+	c.mu.Unlock()
+	close(call.done)
+
+	return call.err
+}
+
+func (c *serverClock) measure(ctx context.Context, r *RestAPI) error {
+	sent := time.Now()
+	out, err := r.ServerTimeWithContext(ctx)
+	received := time.Now()
+	if err != nil {
+		c.mu.Lock()
+		c.failures++
+		c.retryAt = received.Add(backoff(clockRetryMin, clockRetryMax, c.failures))
+		c.mu.Unlock()
+
+		return err
+	}
+
+	// the server stamped its time roughly in the middle of the round trip
+	local := sent.Add(received.Sub(sent) / 2)
+
+	c.mu.Lock()
+	c.offset = time.UnixMilli(out.ServerTime).Sub(local)
+	c.syncedAt = received
+	c.failures = 0
+	c.retryAt = time.Time{}
+	c.mu.Unlock()
+
+	return nil
+}
+
+// now returns the current server time, syncing the offset first if it
+// was never measured or is older than the sync interval. After a failed
+// sync the next one waits for a growing backoff, so an unreachable time
+// endpoint doesn't double every request. Concurrent requests finding the
+// offset stale share one sync.
+func (c *serverClock) now(ctx context.Context, r *RestAPI) time.Time {
+	c.mu.Lock()
+	stale := c.syncedAt.IsZero() || c.interval > 0 && time.Since(c.syncedAt) > c.interval
+	stale = stale && !time.Now().Before(c.retryAt)
+	c.mu.Unlock()
+
+	if stale {
+		// on failure keep the previous offset, the request itself will tell
+		// if the clock is too far off
+		_ = c.sync(ctx, r)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return time.Now().Add(c.offset)
+}
@@ -0,0 +1,143 @@
+package currencycom
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// clockServer serves a server time ahead of the local clock by offset,
+// stamped delay after the request arrives and sent delay later. Account
+// requests fail with an invalid timestamp while rejects is positive.
+func clockServer(t *testing.T, offset, delay time.Duration, rejects int32) (*httptest.Server, *int32, *int32) {
+	t.Helper()
+
+	var syncs, accounts int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v2/time" {
+			atomic.AddInt32(&syncs, 1)
+			time.Sleep(delay)
+			serverTime := time.Now().Add(offset).UnixMilli()
+			time.Sleep(delay)
+			_, _ = w.Write([]byte(`{"serverTime":` + strconv.FormatInt(serverTime, 10) + `}`))
+			return
+		}
+
+		if atomic.AddInt32(&accounts, 1) <= rejects {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"code":-1021,"msg":"Timestamp for this request is outside of the recvWindow."}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"balances":[]}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv, &syncs, &accounts
+}
+
+func TestServerClockBackoff(t *testing.T) {
+	var syncs int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v2/time" {
+			atomic.AddInt32(&syncs, 1)
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"code":-1000,"msg":"unavailable"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"balances":[]}`))
+	}))
+	defer srv.Close()
+
+	api := NewRestAPI("key", "secret", srv.URL, WithServerTimeSync(0), WithRetryPolicy(NoRetry))
+	for i := 0; i < 5; i++ {
+		if _, err := api.AccountInfo(nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if got := atomic.LoadInt32(&syncs); got != 1 {
+		t.Errorf("time synced %d times, want 1 until the backoff passes", got)
+	}
+}
+
+func TestServerClockStale(t *testing.T) {
+	srv, syncs, _ := clockServer(t, 0, 0, 0)
+	api := NewRestAPI("key", "secret", srv.URL, WithServerTimeSync(100*time.Millisecond), WithRetryPolicy(NoRetry))
+
+	for i := 0; i < 3; i++ {
+		if _, err := api.AccountInfo(nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := atomic.LoadInt32(syncs); got != 1 {
+		t.Errorf("time synced %d times, want 1 while the offset is fresh", got)
+	}
+
+	time.Sleep(150 * time.Millisecond)
+	if _, err := api.AccountInfo(nil); err != nil {
+		t.Fatal(err)
+	}
+	if got := atomic.LoadInt32(syncs); got != 2 {
+		t.Errorf("time synced %d times, want 2 after the interval", got)
+	}
+}
+
+func TestServerClockInvalidTimestamp(t *testing.T) {
+	srv, syncs, accounts := clockServer(t, 0, 0, 1)
+	api := NewRestAPI("key", "secret", srv.URL, WithServerTimeSync(0), WithRetryPolicy(NoRetry))
+
+	// the rejected request is repeated once after a new sync
+	if _, err := api.AccountInfo(nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := atomic.LoadInt32(syncs); got != 2 {
+		t.Errorf("time synced %d times, want the first sync and one after the rejection", got)
+	}
+	if got := atomic.LoadInt32(accounts); got != 2 {
+		t.Errorf("account requested %d times, want 2", got)
+	}
+}
+
+func TestServerClockRoundTrip(t *testing.T) {
+	// the server stamps its time in the middle of a 200ms round trip
+	srv, _, _ := clockServer(t, time.Hour, 100*time.Millisecond, 0)
+	api := NewRestAPI("key", "secret", srv.URL, WithServerTimeSync(0), WithRetryPolicy(NoRetry))
+
+	if err := api.SyncTime(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	// without compensation the offset would be off by half the round trip
+	if diff := api.TimeOffset() - time.Hour; diff < -30*time.Millisecond || diff > 30*time.Millisecond {
+		t.Errorf("TimeOffset = %v, want 1h", api.TimeOffset())
+	}
+}
+
+func TestServerClockConcurrent(t *testing.T) {
+	srv, syncs, _ := clockServer(t, 0, 50*time.Millisecond, 0)
+	api := NewRestAPI("key", "secret", srv.URL, WithServerTimeSync(0), WithRetryPolicy(NoRetry))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			if _, err := api.AccountInfo(nil); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := atomic.LoadInt32(syncs); got != 1 {
+		t.Errorf("time synced %d times, want 1 shared by all requests", got)
+	}
+}
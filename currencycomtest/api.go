@@ -0,0 +1,191 @@
+package currencycomtest
+
+import (
+	"context"
+
+	currencycom "github.com/scientistnik/currency.com"
+)
+
+func (f *Fake) ServerTime() (*currencycom.ServerTimeResponse, error) {
+	return f.ServerTimeWithContext(context.Background())
+}
+
+func (f *Fake) ServerTimeWithContext(ctx context.Context) (*currencycom.ServerTimeResponse, error) {
+	return call[*currencycom.ServerTimeResponse](ctx, f, "ServerTime", nil)
+}
+
+func (f *Fake) ExchangeInfo() (*currencycom.ExchangeInfoResponse, error) {
+	return f.ExchangeInfoWithContext(context.Background())
+}
+
+func (f *Fake) ExchangeInfoWithContext(ctx context.Context) (*currencycom.ExchangeInfoResponse, error) {
+	return call[*currencycom.ExchangeInfoResponse](ctx, f, "ExchangeInfo", nil)
+}
+
+func (f *Fake) OrderBook(params *currencycom.DepthRequest) (*currencycom.DepthResponse, error) {
+	return f.OrderBookWithContext(context.Background(), params)
+}
+
+func (f *Fake) OrderBookWithContext(ctx context.Context, params *currencycom.DepthRequest) (*currencycom.DepthResponse, error) {
+	return call[*currencycom.DepthResponse](ctx, f, "OrderBook", params)
+}
+
+func (f *Fake) Klines(params *currencycom.KLinesRequest) ([]currencycom.Kline, error) {
+	return f.KlinesWithContext(context.Background(), params)
+}
+
+func (f *Fake) KlinesWithContext(ctx context.Context, params *currencycom.KLinesRequest) ([]currencycom.Kline, error) {
+	return call[[]currencycom.Kline](ctx, f, "Klines", params)
+}
+
+func (f *Fake) TradesAggregated(params *currencycom.AggTradesRequest) ([]currencycom.AggTrades, error) {
+	return f.TradesAggregatedWithContext(context.Background(), params)
+}
+
+func (f *Fake) TradesAggregatedWithContext(ctx context.Context, params *currencycom.AggTradesRequest) ([]currencycom.AggTrades, error) {
+	return call[[]currencycom.AggTrades](ctx, f, "TradesAggregated", params)
+}
+
+func (f *Fake) PriceChange(params *currencycom.BySymbolRequest) (*currencycom.Ticker24hr, error) {
+	return f.PriceChangeWithContext(context.Background(), params)
+}
+
+func (f *Fake) PriceChangeWithContext(ctx context.Context, params *currencycom.BySymbolRequest) (*currencycom.Ticker24hr, error) {
+	return call[*currencycom.Ticker24hr](ctx, f, "PriceChange", params)
+}
+
+func (f *Fake) AccountInfo(params *currencycom.AccountRequest) (*currencycom.AccountResponse, error) {
+	return f.AccountInfoWithContext(context.Background(), params)
+}
+
+func (f *Fake) AccountInfoWithContext(ctx context.Context, params *currencycom.AccountRequest) (*currencycom.AccountResponse, error) {
+	return call[*currencycom.AccountResponse](ctx, f, "AccountInfo", params)
+}
+
+func (f *Fake) LeverageSettings(params *currencycom.LeverageSettingsRequest) (*currencycom.LeverageSettingsResponse, error) {
+	return f.LeverageSettingsWithContext(context.Background(), params)
+}
+
+func (f *Fake) LeverageSettingsWithContext(ctx context.Context, params *currencycom.LeverageSettingsRequest) (*currencycom.LeverageSettingsResponse, error) {
+	return call[*currencycom.LeverageSettingsResponse](ctx, f, "LeverageSettings", params)
+}
+
+func (f *Fake) ListOfTrades(params *currencycom.AllMyTradesRequest) ([]currencycom.MyTradesResponse, error) {
+	return f.ListOfTradesWithContext(context.Background(), params)
+}
+
+func (f *Fake) ListOfTradesWithContext(ctx context.Context, params *currencycom.AllMyTradesRequest) ([]currencycom.MyTradesResponse, error) {
+	return call[[]currencycom.MyTradesResponse](ctx, f, "ListOfTrades", params)
+}
+
+func (f *Fake) ListOfHistoricalPositions(params *currencycom.PositionHistoryRequest) (*currencycom.TradingPositionHistoryResponse, error) {
+	return f.ListOfHistoricalPositionsWithContext(context.Background(), params)
+}
+
+func (f *Fake) ListOfHistoricalPositionsWithContext(ctx context.Context, params *currencycom.PositionHistoryRequest) (*currencycom.TradingPositionHistoryResponse, error) {
+	return call[*currencycom.TradingPositionHistoryResponse](ctx, f, "ListOfHistoricalPositions", params)
+}
+
+func (f *Fake) ListOfTransactions(params *currencycom.TransactionsRequest) ([]currencycom.TransactionDTOResponse, error) {
+	return f.ListOfTransactionsWithContext(context.Background(), params)
+}
+
+func (f *Fake) ListOfTransactionsWithContext(ctx context.Context, params *currencycom.TransactionsRequest) ([]currencycom.TransactionDTOResponse, error) {
+	return call[[]currencycom.TransactionDTOResponse](ctx, f, "ListOfTransactions", params)
+}
+
+func (f *Fake) ListOfLedgers(params *currencycom.TransactionsRequest) ([]currencycom.TransactionDTOResponse, error) {
+	return f.ListOfLedgersWithContext(context.Background(), params)
+}
+
+func (f *Fake) ListOfLedgersWithContext(ctx context.Context, params *currencycom.TransactionsRequest) ([]currencycom.TransactionDTOResponse, error) {
+	return call[[]currencycom.TransactionDTOResponse](ctx, f, "ListOfLedgers", params)
+}
+
+func (f *Fake) CreateOrder(params *currencycom.CreateOrderRequest) (*currencycom.NewOrderResponseRESULT, error) {
+	return f.CreateOrderWithContext(context.Background(), params)
+}
+
+func (f *Fake) CreateOrderWithContext(ctx context.Context, params *currencycom.CreateOrderRequest) (*currencycom.NewOrderResponseRESULT, error) {
+	return call[*currencycom.NewOrderResponseRESULT](ctx, f, "CreateOrder", params)
+}
+
+func (f *Fake) CancelOrder(params *currencycom.CancelOrderRequest) (*currencycom.CancelOrderResponse, error) {
+	return f.CancelOrderWithContext(context.Background(), params)
+}
+
+func (f *Fake) CancelOrderWithContext(ctx context.Context, params *currencycom.CancelOrderRequest) (*currencycom.CancelOrderResponse, error) {
+	return call[*currencycom.CancelOrderResponse](ctx, f, "CancelOrder", params)
+}
+
+func (f *Fake) ListOfOpenOrder(params *currencycom.PositionHistoryRequest) ([]currencycom.QueryOrderResponse, error) {
+	return f.ListOfOpenOrderWithContext(context.Background(), params)
+}
+
+func (f *Fake) ListOfOpenOrderWithContext(ctx context.Context, params *currencycom.PositionHistoryRequest) ([]currencycom.QueryOrderResponse, error) {
+	return call[[]currencycom.QueryOrderResponse](ctx, f, "ListOfOpenOrder", params)
+}
+
+func (f *Fake) LeverageOrdersEdit(params *currencycom.UpdateTradingOrderRequest) (*currencycom.TradingOrderUpdateResponse, error) {
+	return f.LeverageOrdersEditWithContext(context.Background(), params)
+}
+
+func (f *Fake) LeverageOrdersEditWithContext(ctx context.Context, params *currencycom.UpdateTradingOrderRequest) (*currencycom.TradingOrderUpdateResponse, error) {
+	return call[*currencycom.TradingOrderUpdateResponse](ctx, f, "LeverageOrdersEdit", params)
+}
+
+func (f *Fake) ListOfLeverageTrades(params *currencycom.SignedRequest) (*currencycom.TradingPositionListResponse, error) {
+	return f.ListOfLeverageTradesWithContext(context.Background(), params)
+}
+
+func (f *Fake) ListOfLeverageTradesWithContext(ctx context.Context, params *currencycom.SignedRequest) (*currencycom.TradingPositionListResponse, error) {
+	return call[*currencycom.TradingPositionListResponse](ctx, f, "ListOfLeverageTrades", params)
+}
+
+func (f *Fake) LeverageTradeEdit(params *currencycom.UpdateTradingPositionRequest) (*currencycom.TradingPositionUpdateResponse, error) {
+	return f.LeverageTradeEditWithContext(context.Background(), params)
+}
+
+func (f *Fake) LeverageTradeEditWithContext(ctx context.Context, params *currencycom.UpdateTradingPositionRequest) (*currencycom.TradingPositionUpdateResponse, error) {
+	return call[*currencycom.TradingPositionUpdateResponse](ctx, f, "LeverageTradeEdit", params)
+}
+
+func (f *Fake) TradingPositionClose(params *currencycom.CloseTradingPositionRequest) (*currencycom.TradingPositionCloseAllResponse, error) {
+	return f.TradingPositionCloseWithContext(context.Background(), params)
+}
+
+func (f *Fake) TradingPositionCloseWithContext(ctx context.Context, params *currencycom.CloseTradingPositionRequest) (*currencycom.TradingPositionCloseAllResponse, error) {
+	return call[*currencycom.TradingPositionCloseAllResponse](ctx, f, "TradingPositionClose", params)
+}
+
+func (f *Fake) ListOfCurrencies(params *currencycom.SignedRequest) ([]currencycom.CurrencyDtoResponse, error) {
+	return f.ListOfCurrenciesWithContext(context.Background(), params)
+}
+
+func (f *Fake) ListOfCurrenciesWithContext(ctx context.Context, params *currencycom.SignedRequest) ([]currencycom.CurrencyDtoResponse, error) {
+	return call[[]currencycom.CurrencyDtoResponse](ctx, f, "ListOfCurrencies", params)
+}
+
+func (f *Fake) StringOfAddress(params *currencycom.BlockchainAddressRequest) (*currencycom.BlockchainAddressGetResponse, error) {
+	return f.StringOfAddressWithContext(context.Background(), params)
+}
+
+func (f *Fake) StringOfAddressWithContext(ctx context.Context, params *currencycom.BlockchainAddressRequest) (*currencycom.BlockchainAddressGetResponse, error) {
+	return call[*currencycom.BlockchainAddressGetResponse](ctx, f, "StringOfAddress", params)
+}
+
+func (f *Fake) ListOfDeposits(params *currencycom.TransactionsRequest) ([]currencycom.TransactionDTOResponse, error) {
+	return f.ListOfDepositsWithContext(context.Background(), params)
+}
+
+func (f *Fake) ListOfDepositsWithContext(ctx context.Context, params *currencycom.TransactionsRequest) ([]currencycom.TransactionDTOResponse, error) {
+	return call[[]currencycom.TransactionDTOResponse](ctx, f, "ListOfDeposits", params)
+}
+
+func (f *Fake) ListOfWithdrawals(params *currencycom.TransactionsRequest) ([]currencycom.TransactionDTOResponse, error) {
+	return f.ListOfWithdrawalsWithContext(context.Background(), params)
+}
+
+func (f *Fake) ListOfWithdrawalsWithContext(ctx context.Context, params *currencycom.TransactionsRequest) ([]currencycom.TransactionDTOResponse, error) {
+	return call[[]currencycom.TransactionDTOResponse](ctx, f, "ListOfWithdrawals", params)
+}
@@ -0,0 +1,706 @@
+package currencycomtest
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	currencycom "github.com/scientistnik/currency.com"
+	"github.com/scientistnik/currency.com/paper"
+)
+
+// DEFAULT_RECV_WINDOW is the recvWindow of signed requests that don't send
+// one, like on the exchange.
+const DEFAULT_RECV_WINDOW = 5 * time.Second
+
+// MAX_RECV_WINDOW is the largest recvWindow accepted.
+const MAX_RECV_WINDOW = 60 * time.Second
+
+// codeBadParam is the error code of missing or malformed parameters.
+const codeBadParam int64 = -1102
+
+// Fault changes the responses of the emulator, e.g. to test retries.
+type Fault struct {
+	// Path is the method of the requests affected, e.g. "order" for
+	// /api/v2/order, "" means every request.
+	Path string
+
+	// Delay is waited before the response, the fault or the normal one.
+	Delay time.Duration
+
+	// Status, when set, fails the request with an error body of Code and
+	// Message.
+	Status     int
+	Code       int64
+	Message    string
+	RetryAfter time.Duration
+
+	// Times is the number of requests affected, 0 means all of them.
+	Times int
+}
+
+// Request is a request served by the emulator.
+type Request struct {
+	Method string // HTTP method
+	Path   string // method of the API, e.g. "order"
+	Query  url.Values
+}
+
+type route struct {
+	signed bool
+	handle func(e *Emulator, ctx context.Context, q url.Values) (interface{}, error)
+}
+
+// Emulator is an http.Handler serving the /api/v2 routes of the exchange
+// from memory, for integration tests of the real signing and decoding:
+//
+//	emu := currencycomtest.NewEmulator("key", "secret", currencycom.NewDecimalFromInt(10000), info)
+//	emu.SetPrice("BTC/USD_LEVERAGE", currencycom.MustDecimal("27000"))
+//
+//	server := httptest.NewServer(emu)
+//	defer server.Close()
+//
+//	api := currencycom.NewRestAPI("key", "secret", server.URL)
+//
+// Signed requests need the API key header, a valid HMAC signature and a
+// timestamp within recvWindow. Orders are matched against the prices set
+// with SetPrice, like by paper.Client.
+type Emulator struct {
+	APIKey string
+	Secret string
+
+	// Now is the server clock, time.Now when nil.
+	Now func() time.Time
+
+	trader *paper.Client
+
+	mu       sync.Mutex
+	symbols  []currencycom.ExchangeSymbolInfo
+	prices   map[string]currencycom.Decimal
+	books    map[string]currencycom.DepthResponse
+	tickers  map[string]currencycom.Ticker24hr
+	klines   map[string][]currencycom.Kline // by symbol and interval
+	trades   map[string][]currencycom.AggTrades
+	faults   []*Fault
+	requests []Request
+}
+
+// NewEmulator creates an emulator of an account with a cash balance
+// trading symbols.
+func NewEmulator(apiKey, secret string, cash currencycom.Decimal, symbols ...currencycom.ExchangeSymbolInfo) *Emulator {
+	e := &Emulator{
+		APIKey:  apiKey,
+		Secret:  secret,
+		symbols: symbols,
+		prices:  make(map[string]currencycom.Decimal),
+		books:   make(map[string]currencycom.DepthResponse),
+		tickers: make(map[string]currencycom.Ticker24hr),
+		klines:  make(map[string][]currencycom.Kline),
+		trades:  make(map[string][]currencycom.AggTrades),
+	}
+
+	e.trader = paper.NewClient(paper.PriceSourceFunc(e.price), cash, symbols...)
+
+	return e
+}
+
+// Trader returns the simulated account behind the trading routes.
+func (e *Emulator) Trader() *paper.Client {
+	return e.trader
+}
+
+// SetPrice sets the price of symbol, filling the orders it reaches.
+func (e *Emulator) SetPrice(symbol string, price currencycom.Decimal) {
+	e.mu.Lock()
+	e.prices[symbol] = price
+	e.mu.Unlock()
+
+	e.trader.Broker().UpdatePrice(symbol, e.now(), price)
+}
+
+func (e *Emulator) price(ctx context.Context, symbol string) (currencycom.Decimal, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	price, ok := e.prices[symbol]
+	if !ok {
+		return currencycom.Decimal{}, fmt.Errorf("%w: no price of %s", currencycom.ErrMarketClosed, symbol)
+	}
+
+	return price, nil
+}
+
+// SetOrderBook sets the depth of symbol, by default it is one level at
+// the price on both sides.
+func (e *Emulator) SetOrderBook(symbol string, book currencycom.DepthResponse) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.books[symbol] = book
+}
+
+// SetTicker sets the 24h ticker of symbol, by default it is built from
+// the price.
+func (e *Emulator) SetTicker(ticker currencycom.Ticker24hr) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.tickers[ticker.Symbol] = ticker
+}
+
+// SetKlines sets the bars of symbol and interval, oldest first.
+func (e *Emulator) SetKlines(symbol string, interval currencycom.Interval, klines []currencycom.Kline) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.klines[symbol+" "+string(interval)] = klines
+}
+
+// SetAggTrades sets the aggregated trades of symbol, oldest first.
+func (e *Emulator) SetAggTrades(symbol string, trades []currencycom.AggTrades) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.trades[symbol] = trades
+}
+
+// Inject adds a fault, faults are checked in the order they were added.
+func (e *Emulator) Inject(f Fault) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.faults = append(e.faults, &f)
+}
+
+// Throttle fails the next times requests of path with 429 Too Many
+// Requests, 0 times means until ClearFaults.
+func (e *Emulator) Throttle(path string, times int, retryAfter time.Duration) {
+	e.Inject(Fault{
+		Path:       path,
+		Status:     http.StatusTooManyRequests,
+		Code:       currencycom.CodeTooManyRequests,
+		Message:    "Too many requests, THROTTLING",
+		RetryAfter: retryAfter,
+		Times:      times,
+	})
+}
+
+// ClearFaults removes all faults.
+func (e *Emulator) ClearFaults() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.faults = nil
+}
+
+// Requests returns the requests served, oldest first.
+func (e *Emulator) Requests() []Request {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return append([]Request(nil), e.requests...)
+}
+
+func (e *Emulator) now() time.Time {
+	if e.Now != nil {
+		return e.Now()
+	}
+
+	return time.Now()
+}
+
+// ServeHTTP serves a request of the API.
+func (e *Emulator) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	prefix := "/api/" + currencycom.VERSION_API + "/"
+	if !strings.HasPrefix(r.URL.Path, prefix) {
+		http.NotFound(w, r)
+		return
+	}
+
+	path := strings.TrimPrefix(r.URL.Path, prefix)
+	q := r.URL.Query()
+
+	e.mu.Lock()
+	e.requests = append(e.requests, Request{Method: r.Method, Path: path, Query: q})
+	fault := e.fault(path)
+	e.mu.Unlock()
+
+	if fault != nil && fault.Delay > 0 {
+		select {
+		case <-time.After(fault.Delay):
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+	if fault != nil && fault.Status != 0 {
+		if fault.RetryAfter > 0 {
+			w.Header().Set("Retry-After", strconv.Itoa(int((fault.RetryAfter+time.Second-1)/time.Second)))
+		}
+		writeError(w, fault.Status, fault.Code, fault.Message)
+		return
+	}
+
+	rt, ok := routes[r.Method+" "+path]
+	if !ok {
+		writeError(w, http.StatusNotFound, 0, "unknown route "+r.Method+" "+path)
+		return
+	}
+
+	if rt.signed {
+		if status, code, msg := e.authorize(r, q); status != 0 {
+			writeError(w, status, code, msg)
+			return
+		}
+	}
+
+	out, err := rt.handle(e, r.Context(), q)
+	if err != nil {
+		status, code, msg := apiError(err)
+		writeError(w, status, code, msg)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(out)
+}
+
+// fault returns the first fault of path and uses it up once.
+func (e *Emulator) fault(path string) *Fault {
+	for i, f := range e.faults {
+		if f.Path != "" && f.Path != path {
+			continue
+		}
+
+		if f.Times > 0 {
+			f.Times--
+			if f.Times == 0 {
+				e.faults = append(e.faults[:i:i], e.faults[i+1:]...)
+			}
+		}
+
+		return f
+	}
+
+	return nil
+}
+
+// authorize checks the API key, signature and timestamp of a signed
+// request, it returns a zero status when they are fine.
+func (e *Emulator) authorize(r *http.Request, q url.Values) (int, int64, string) {
+	if key := r.Header.Get("X-MBX-APIKEY"); key == "" || key != e.APIKey {
+		return http.StatusUnauthorized, currencycom.CodeRejectedAPIKey, "Invalid API-key, IP, or permissions for action."
+	}
+
+	// the exchange signs the query as sent, not a re-encoding of it, so
+	// only the signature pair is cut out of the raw query
+	var signed []string
+	for _, pair := range strings.Split(r.URL.RawQuery, "&") {
+		if !strings.HasPrefix(pair, "signature=") {
+			signed = append(signed, pair)
+		}
+	}
+
+	mac := hmac.New(sha256.New, []byte(e.Secret))
+	mac.Write([]byte(strings.Join(signed, "&")))
+	want := hex.EncodeToString(mac.Sum(nil))
+	if !hmac.Equal([]byte(q.Get("signature")), []byte(want)) {
+		return http.StatusBadRequest, currencycom.CodeInvalidSignature, "Signature for this request is not valid."
+	}
+
+	ts, err := strconv.ParseInt(q.Get("timestamp"), 10, 64)
+	if err != nil {
+		return http.StatusBadRequest, codeBadParam, "Mandatory parameter 'timestamp' was not sent, was empty/null, or malformed."
+	}
+
+	window := DEFAULT_RECV_WINDOW
+	if s := q.Get("recvWindow"); s != "" {
+		ms, err := strconv.ParseInt(s, 10, 64)
+		if err != nil || ms <= 0 || time.Duration(ms)*time.Millisecond > MAX_RECV_WINDOW {
+			return http.StatusBadRequest, codeBadParam, "Parameter 'recvWindow' is invalid."
+		}
+		window = time.Duration(ms) * time.Millisecond
+	}
+
+	now := e.now().UnixMilli()
+	if ts > now+1000 || now-ts > window.Milliseconds() {
+		return http.StatusBadRequest, currencycom.CodeInvalidTimestamp, "Timestamp for this request is outside of the recvWindow."
+	}
+
+	return 0, 0, ""
+}
+
+// apiError turns an error of a handler into an error response.
+func apiError(err error) (int, int64, string) {
+	reason := currencycom.RejectReasonInvalidOrder
+
+	switch {
+	case strings.HasPrefix(err.Error(), "error params:"):
+		return http.StatusBadRequest, codeBadParam, err.Error()
+	case errors.Is(err, currencycom.ErrInvalidSymbol):
+		return http.StatusBadRequest, currencycom.CodeInvalidSymbol, "Invalid symbol."
+	case errors.Is(err, currencycom.ErrOrderNotFound):
+		return http.StatusBadRequest, currencycom.CodeNoSuchOrder, "Order does not exist."
+	case errors.Is(err, currencycom.ErrPositionNotFound):
+		reason = currencycom.RejectReasonPositionNotFound
+	case errors.Is(err, currencycom.ErrInvalidQuantity):
+		reason = currencycom.RejectReasonInvalidOrderQty
+	case errors.Is(err, currencycom.ErrInvalidPrice):
+		reason = currencycom.RejectReasonInvalidPrice
+	case errors.Is(err, currencycom.ErrMarketClosed):
+		reason = currencycom.RejectReasonClosedMarket
+	}
+
+	return http.StatusBadRequest, currencycom.CodeNewOrderRejected, fmt.Sprintf("Order rejected: %s, %v", reason, err)
+}
+
+func writeError(w http.ResponseWriter, status int, code int64, msg string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(struct {
+		Code    int64  `json:"code"`
+		Message string `json:"msg"`
+	}{code, msg})
+}
+
+// window filters records by the startTime and endTime of q and keeps the
+// first limit of them.
+func window[T any](records []T, q url.Values, ts func(T) int64) ([]T, error) {
+	start, err := int64Param(q, "startTime")
+	if err != nil {
+		return nil, err
+	}
+
+	end, err := int64Param(q, "endTime")
+	if err != nil {
+		return nil, err
+	}
+
+	limit, err := int64Param(q, "limit")
+	if err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = 500
+	}
+
+	out := []T{}
+	for _, r := range records {
+		t := ts(r)
+		if start != 0 && t < start || end != 0 && t > end {
+			continue
+		}
+
+		out = append(out, r)
+		if int64(len(out)) >= limit {
+			break
+		}
+	}
+
+	return out, nil
+}
+
+func int64Param(q url.Values, name string) (int64, error) {
+	s := q.Get(name)
+	if s == "" {
+		return 0, nil
+	}
+
+	v, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("error params: invalid %s %q", name, s)
+	}
+
+	return v, nil
+}
+
+func decimalParam(q url.Values, name string) (currencycom.Decimal, error) {
+	s := q.Get(name)
+	if s == "" {
+		return currencycom.Decimal{}, nil
+	}
+
+	v, err := currencycom.ParseDecimal(s)
+	if err != nil {
+		return currencycom.Decimal{}, fmt.Errorf("error params: invalid %s %q", name, s)
+	}
+
+	return v, nil
+}
+
+// symbolParam returns the symbol of q, it must be a symbol of the
+// emulator.
+func (e *Emulator) symbolParam(q url.Values) (string, error) {
+	symbol := q.Get("symbol")
+	if symbol == "" {
+		return "", fmt.Errorf("error params: symbol need to set")
+	}
+
+	if _, ok := e.trader.Broker().Symbol(symbol); !ok {
+		return "", fmt.Errorf("%w %q", currencycom.ErrInvalidSymbol, symbol)
+	}
+
+	return symbol, nil
+}
+
+var routes = map[string]route{
+	"GET time": {handle: func(e *Emulator, ctx context.Context, q url.Values) (interface{}, error) {
+		return currencycom.ServerTimeResponse{ServerTime: e.now().UnixMilli()}, nil
+	}},
+
+	"GET exchangeInfo": {handle: func(e *Emulator, ctx context.Context, q url.Values) (interface{}, error) {
+		e.mu.Lock()
+		defer e.mu.Unlock()
+
+		return currencycom.ExchangeInfoResponse{
+			ServerTime: e.now().UnixMilli(),
+			Symbols:    append([]currencycom.ExchangeSymbolInfo{}, e.symbols...),
+			Timezone:   "UTC",
+		}, nil
+	}},
+
+	"GET depth": {handle: func(e *Emulator, ctx context.Context, q url.Values) (interface{}, error) {
+		symbol, err := e.symbolParam(q)
+		if err != nil {
+			return nil, err
+		}
+
+		limit, err := int64Param(q, "limit")
+		if err != nil {
+			return nil, err
+		}
+
+		e.mu.Lock()
+		book, ok := e.books[symbol]
+		price, priced := e.prices[symbol]
+		e.mu.Unlock()
+
+		if !ok {
+			book = currencycom.DepthResponse{Asks: [][]currencycom.Decimal{}, Bids: [][]currencycom.Decimal{}}
+			if priced {
+				one := currencycom.NewDecimalFromInt(1)
+				book.Asks = [][]currencycom.Decimal{{price, one}}
+				book.Bids = [][]currencycom.Decimal{{price, one}}
+			}
+		}
+
+		if limit > 0 && int64(len(book.Asks)) > limit {
+			book.Asks = book.Asks[:limit]
+		}
+		if limit > 0 && int64(len(book.Bids)) > limit {
+			book.Bids = book.Bids[:limit]
+		}
+
+		return book, nil
+	}},
+
+	"GET klines": {handle: func(e *Emulator, ctx context.Context, q url.Values) (interface{}, error) {
+		symbol, err := e.symbolParam(q)
+		if err != nil {
+			return nil, err
+		}
+
+		interval := currencycom.Interval(q.Get("interval"))
+		if !interval.IsValid() {
+			return nil, fmt.Errorf("error params: unknown interval %q", interval)
+		}
+
+		e.mu.Lock()
+		klines := e.klines[symbol+" "+string(interval)]
+		e.mu.Unlock()
+
+		return window(klines, q, func(k currencycom.Kline) int64 { return k.OpenTime.UnixMilli() })
+	}},
+
+	"GET aggTrades": {handle: func(e *Emulator, ctx context.Context, q url.Values) (interface{}, error) {
+		symbol, err := e.symbolParam(q)
+		if err != nil {
+			return nil, err
+		}
+
+		e.mu.Lock()
+		trades := e.trades[symbol]
+		e.mu.Unlock()
+
+		return window(trades, q, func(t currencycom.AggTrades) int64 { return t.Timestamp })
+	}},
+
+	"GET ticker/24hr": {handle: func(e *Emulator, ctx context.Context, q url.Values) (interface{}, error) {
+		symbol, err := e.symbolParam(q)
+		if err != nil {
+			return nil, err
+		}
+
+		e.mu.Lock()
+		defer e.mu.Unlock()
+
+		if ticker, ok := e.tickers[symbol]; ok {
+			return ticker, nil
+		}
+
+		price := e.prices[symbol]
+
+		return currencycom.Ticker24hr{
+			AskPrice:  price,
+			BidPrice:  price,
+			CloseTime: e.now().UnixMilli(),
+			HighPrice: price,
+			LastPrice: price,
+			LowPrice:  price,
+			OpenPrice: price,
+			Symbol:    symbol,
+		}, nil
+	}},
+
+	"GET account": {signed: true, handle: func(e *Emulator, ctx context.Context, q url.Values) (interface{}, error) {
+		return e.trader.AccountInfoWithContext(ctx, nil)
+	}},
+
+	"POST order": {signed: true, handle: func(e *Emulator, ctx context.Context, q url.Values) (interface{}, error) {
+		req := currencycom.CreateOrderRequest{
+			Side:   currencycom.OrderSide(q.Get("side")),
+			Symbol: currencycom.Symbol(q.Get("symbol")),
+			Type:   currencycom.OrderType(q.Get("type")),
+		}
+
+		var err error
+		if req.Quantity, err = decimalParam(q, "quantity"); err != nil {
+			return nil, err
+		}
+		if req.Price, err = decimalParam(q, "price"); err != nil {
+			return nil, err
+		}
+		if req.StopLoss, err = decimalParam(q, "stopLoss"); err != nil {
+			return nil, err
+		}
+		if req.TakeProfit, err = decimalParam(q, "takeProfit"); err != nil {
+			return nil, err
+		}
+		if req.ExpireTimestamp, err = int64Param(q, "expireTimestamp"); err != nil {
+			return nil, err
+		}
+		req.GuaranteedStopLoss = q.Get("guaranteedStopLoss") == "true"
+
+		return e.trader.CreateOrderWithContext(ctx, &req)
+	}},
+
+	"DELETE order": {signed: true, handle: func(e *Emulator, ctx context.Context, q url.Values) (interface{}, error) {
+		return e.trader.CancelOrderWithContext(ctx, &currencycom.CancelOrderRequest{
+			OrderId: q.Get("orderId"),
+			Symbol:  currencycom.Symbol(q.Get("symbol")),
+		})
+	}},
+
+	"GET openOrders": {signed: true, handle: func(e *Emulator, ctx context.Context, q url.Values) (interface{}, error) {
+		return e.trader.ListOfOpenOrderWithContext(ctx, &currencycom.PositionHistoryRequest{Symbol: currencycom.Symbol(q.Get("symbol"))})
+	}},
+
+	"POST updateTradingOrder": {signed: true, handle: func(e *Emulator, ctx context.Context, q url.Values) (interface{}, error) {
+		req := currencycom.UpdateTradingOrderRequest{OrderId: q.Get("orderId")}
+
+		var err error
+		if req.NewPrice, err = decimalParam(q, "newPrice"); err != nil {
+			return nil, err
+		}
+		if req.StopLoss, err = decimalParam(q, "stopLoss"); err != nil {
+			return nil, err
+		}
+		if req.TakeProfit, err = decimalParam(q, "takeProfit"); err != nil {
+			return nil, err
+		}
+		if req.ExpireTimestamp, err = int64Param(q, "expireTimestamp"); err != nil {
+			return nil, err
+		}
+
+		return e.trader.LeverageOrdersEditWithContext(ctx, &req)
+	}},
+
+	"GET tradingPositions": {signed: true, handle: func(e *Emulator, ctx context.Context, q url.Values) (interface{}, error) {
+		return e.trader.ListOfLeverageTradesWithContext(ctx, nil)
+	}},
+
+	"POST updateTradingPosition": {signed: true, handle: func(e *Emulator, ctx context.Context, q url.Values) (interface{}, error) {
+		req := currencycom.UpdateTradingPositionRequest{PositionId: q.Get("positionId")}
+
+		var err error
+		if req.StopLoss, err = decimalParam(q, "stopLoss"); err != nil {
+			return nil, err
+		}
+		if req.TakeProfit, err = decimalParam(q, "takeProfit"); err != nil {
+			return nil, err
+		}
+
+		return e.trader.LeverageTradeEditWithContext(ctx, &req)
+	}},
+
+	"POST closeTradingPosition": {signed: true, handle: func(e *Emulator, ctx context.Context, q url.Values) (interface{}, error) {
+		return e.trader.TradingPositionCloseWithContext(ctx, &currencycom.CloseTradingPositionRequest{PositionId: q.Get("positionId")})
+	}},
+
+	"GET myTrades": {signed: true, handle: func(e *Emulator, ctx context.Context, q url.Values) (interface{}, error) {
+		symbol, err := e.symbolParam(q)
+		if err != nil {
+			return nil, err
+		}
+
+		var trades []currencycom.MyTradesResponse
+		for i, f := range e.trader.Broker().Fills() {
+			if f.Symbol != symbol {
+				continue
+			}
+
+			buyer := f.Side == currencycom.OrderSideBuy
+			trades = append(trades, currencycom.MyTradesResponse{
+				Buyer:      buyer,
+				Commission: f.Fee,
+				Id:         strconv.Itoa(i + 1),
+				IsBuyer:    buyer,
+				IsMaker:    f.Maker,
+				Maker:      f.Maker,
+				OrderId:    f.OrderId,
+				Price:      f.Price,
+				Qty:        f.Quantity,
+				QuoteQty:   f.Price.Mul(f.Quantity),
+				Symbol:     f.Symbol,
+				Time:       f.Time.UnixMilli(),
+			})
+		}
+
+		return window(trades, q, func(t currencycom.MyTradesResponse) int64 { return t.Time })
+	}},
+
+	"GET leverageSettings": {signed: true, handle: func(e *Emulator, ctx context.Context, q url.Values) (interface{}, error) {
+		if _, err := e.symbolParam(q); err != nil {
+			return nil, err
+		}
+
+		return currencycom.LeverageSettingsResponse{Value: 1, Values: []int32{1}}, nil
+	}},
+
+	"GET tradingPositionsHistory": {signed: true, handle: func(e *Emulator, ctx context.Context, q url.Values) (interface{}, error) {
+		return currencycom.TradingPositionHistoryResponse{History: []currencycom.PositionExecutionReportDto{}}, nil
+	}},
+
+	"GET currencies":   {signed: true, handle: emptyList},
+	"GET deposits":     {signed: true, handle: emptyList},
+	"GET withdrawals":  {signed: true, handle: emptyList},
+	"GET ledger":       {signed: true, handle: emptyList},
+	"GET transactions": {signed: true, handle: emptyList},
+	"GET depositAddress": {signed: true, handle: func(e *Emulator, ctx context.Context, q url.Values) (interface{}, error) {
+		return currencycom.BlockchainAddressGetResponse{}, nil
+	}},
+}
+
+func emptyList(e *Emulator, ctx context.Context, q url.Values) (interface{}, error) {
+	return []struct{}{}, nil
+}
@@ -0,0 +1,387 @@
+package currencycomtest
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	currencycom "github.com/scientistnik/currency.com"
+)
+
+var testSymbol = currencycom.ExchangeSymbolInfo{Symbol: "BTC/USD", Status: "TRADING"}
+
+func testServer(t *testing.T) (*Emulator, *httptest.Server) {
+	t.Helper()
+
+	emu := NewEmulator("key", "secret", currencycom.NewDecimalFromInt(10000), testSymbol)
+	emu.SetPrice("BTC/USD", currencycom.NewDecimalFromInt(100))
+
+	server := httptest.NewServer(emu)
+	t.Cleanup(server.Close)
+
+	return emu, server
+}
+
+func TestEmulatorRoundTrip(t *testing.T) {
+	emu, server := testServer(t)
+	api := currencycom.NewRestAPI("key", "secret", server.URL, currencycom.WithRetryPolicy(currencycom.NoRetry))
+
+	order, err := api.CreateOrder(&currencycom.CreateOrderRequest{
+		Symbol:   "BTC/USD",
+		Side:     currencycom.OrderSideBuy,
+		Type:     currencycom.OrderTypeMarket,
+		Quantity: currencycom.NewDecimalFromInt(2),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if order.OrderId == "" || !order.ExecutedQty.Equal(currencycom.NewDecimalFromInt(2)) || !order.Price.Equal(currencycom.NewDecimalFromInt(100)) {
+		t.Errorf("order %+v, want 2 filled at 100", order)
+	}
+
+	account, err := api.AccountInfo(&currencycom.AccountRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(account.Balances) != 1 || !account.Balances[0].Free.Equal(currencycom.NewDecimalFromInt(10000)) {
+		t.Errorf("balances %+v, want the cash of 10000", account.Balances)
+	}
+
+	if p, ok := emu.Trader().Broker().Position("BTC/USD"); !ok || !p.Quantity.Equal(currencycom.NewDecimalFromInt(2)) {
+		t.Errorf("position %+v, want 2 long", p)
+	}
+
+	requests := emu.Requests()
+	if len(requests) != 2 || requests[0].Path != "order" || requests[1].Path != "account" {
+		t.Errorf("requests %+v, want order and account", requests)
+	}
+}
+
+func TestEmulatorAuthorize(t *testing.T) {
+	tests := []struct {
+		name        string
+		key, secret string
+		skew        time.Duration
+		is          error
+	}{
+		{name: "bad key", key: "other", secret: "secret", is: currencycom.ErrInvalidAPIKey},
+		{name: "bad signature", key: "key", secret: "other", is: currencycom.ErrInvalidSignature},
+		{name: "stale timestamp", key: "key", secret: "secret", skew: time.Minute, is: currencycom.ErrInvalidTimestamp},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			emu, server := testServer(t)
+			emu.Now = func() time.Time { return time.Now().Add(tt.skew) }
+
+			api := currencycom.NewRestAPI(tt.key, tt.secret, server.URL, currencycom.WithRetryPolicy(currencycom.NoRetry))
+			_, err := api.AccountInfo(&currencycom.AccountRequest{})
+			if !errors.Is(err, tt.is) {
+				t.Errorf("err = %v, want %v", err, tt.is)
+			}
+		})
+	}
+}
+
+func TestEmulatorAuthorizeRawQuery(t *testing.T) {
+	_, server := testServer(t)
+
+	// recvWindow after timestamp, not in the order of url.Values.Encode
+	query := "timestamp=" + strconv.FormatInt(time.Now().UnixMilli(), 10) + "&recvWindow=5000"
+	mac := hmac.New(sha256.New, []byte("secret"))
+	mac.Write([]byte(query))
+
+	req, err := http.NewRequest(http.MethodGet, server.URL+"/api/"+currencycom.VERSION_API+"/account?"+query+"&signature="+hex.EncodeToString(mac.Sum(nil)), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("X-MBX-APIKEY", "key")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("status %d, want the query signed as sent accepted", resp.StatusCode)
+	}
+}
+
+func TestEmulatorThrottle(t *testing.T) {
+	emu, server := testServer(t)
+	emu.Throttle("account", 1, time.Second)
+
+	api := currencycom.NewRestAPI("key", "secret", server.URL, currencycom.WithRetryPolicy(currencycom.RetryPolicy{
+		MaxAttempts: 2,
+		MinBackoff:  time.Millisecond,
+		MaxBackoff:  time.Millisecond,
+	}))
+
+	begin := time.Now()
+	if _, err := api.AccountInfo(&currencycom.AccountRequest{}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Retry-After wins over the backoff
+	if elapsed := time.Since(begin); elapsed < time.Second {
+		t.Errorf("retried after %v, want Retry-After of 1s", elapsed)
+	}
+
+	if n := len(emu.Requests()); n != 2 {
+		t.Errorf("got %d requests, want 2", n)
+	}
+
+	// without retries the throttling reaches the caller
+	emu.Throttle("account", 1, time.Second)
+	noRetry := currencycom.NewRestAPI("key", "secret", server.URL, currencycom.WithRetryPolicy(currencycom.NoRetry))
+
+	_, err := noRetry.AccountInfo(&currencycom.AccountRequest{})
+	var apiErr *currencycom.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusTooManyRequests || apiErr.RetryAfter != time.Second {
+		t.Errorf("err = %v, want 429 with Retry-After 1s", err)
+	}
+	if !errors.Is(err, currencycom.ErrThrottling) {
+		t.Errorf("errors.Is(%v, ErrThrottling) = false", err)
+	}
+}
+
+func TestEmulatorMarketData(t *testing.T) {
+	emu, server := testServer(t)
+	api := currencycom.NewRestAPI("key", "secret", server.URL, currencycom.WithRetryPolicy(currencycom.NoRetry))
+
+	d := currencycom.MustDecimal
+	begin := time.Date(2021, 6, 1, 10, 0, 0, 0, time.UTC)
+
+	klines := make([]currencycom.Kline, 3)
+	for i := range klines {
+		open := begin.Add(time.Duration(i) * time.Minute)
+		klines[i] = currencycom.Kline{OpenTime: open, Open: d("100"), High: d("101.5"), Low: d("99.25"), Close: d("100.5"), Volume: currencycom.NewDecimalFromInt(int64(10 + i))}
+	}
+	emu.SetKlines("BTC/USD", currencycom.Interval1m, klines)
+
+	emu.SetAggTrades("BTC/USD", []currencycom.AggTrades{
+		{Timestamp: 1000, Aggregate: 1, Price: d("100"), Quantity: d("1")},
+		{Timestamp: 2000, Aggregate: 2, Price: d("101"), Quantity: d("2")},
+		{Timestamp: 3000, Aggregate: 3, Price: d("102"), Quantity: d("3")},
+	})
+
+	t.Run("time", func(t *testing.T) {
+		before := time.Now().UnixMilli()
+		out, err := api.ServerTime()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if after := time.Now().UnixMilli(); out.ServerTime < before || out.ServerTime > after {
+			t.Errorf("server time %d, want between %d and %d", out.ServerTime, before, after)
+		}
+	})
+
+	t.Run("exchangeInfo", func(t *testing.T) {
+		out, err := api.ExchangeInfo()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(out.Symbols) != 1 || out.Symbols[0].Symbol != "BTC/USD" || out.Timezone != "UTC" {
+			t.Errorf("exchange info %+v, want BTC/USD in UTC", out)
+		}
+	})
+
+	t.Run("depth", func(t *testing.T) {
+		// without a book the price is quoted on both sides
+		out, err := api.OrderBook(&currencycom.DepthRequest{Symbol: "BTC/USD"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(out.Asks) != 1 || !out.Asks[0][0].Equal(d("100")) || len(out.Bids) != 1 || !out.Bids[0][0].Equal(d("100")) {
+			t.Errorf("book %+v, want 100 on both sides", out)
+		}
+
+		emu.SetOrderBook("BTC/USD", currencycom.DepthResponse{
+			Asks: [][]currencycom.Decimal{{d("101"), d("1")}, {d("102"), d("2")}},
+			Bids: [][]currencycom.Decimal{{d("99"), d("3")}, {d("98"), d("4")}},
+		})
+
+		out, err = api.OrderBook(&currencycom.DepthRequest{Symbol: "BTC/USD", Limit: 1})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(out.Asks) != 1 || !out.Asks[0][0].Equal(d("101")) || len(out.Bids) != 1 || !out.Bids[0][0].Equal(d("99")) {
+			t.Errorf("book %+v, want the best level of each side", out)
+		}
+
+		if _, err := api.OrderBook(&currencycom.DepthRequest{Symbol: "ETH/USD"}); !errors.Is(err, currencycom.ErrInvalidSymbol) {
+			t.Errorf("book of an unlisted symbol: err = %v, want ErrInvalidSymbol", err)
+		}
+	})
+
+	t.Run("klines", func(t *testing.T) {
+		out, err := api.Klines(&currencycom.KLinesRequest{
+			Symbol:    "BTC/USD",
+			Interval:  currencycom.Interval1m,
+			StartTime: begin.Add(time.Minute).UnixMilli(),
+			Limit:     5,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(out) != 2 {
+			t.Fatalf("got %d klines, want the last 2", len(out))
+		}
+
+		k := out[0]
+		if !k.OpenTime.Equal(klines[1].OpenTime) || !k.High.Equal(d("101.5")) || !k.Low.Equal(d("99.25")) || !k.Close.Equal(d("100.5")) || !k.Volume.Equal(d("11")) {
+			t.Errorf("kline %+v, want %+v", k, klines[1])
+		}
+
+		if _, err := api.Klines(&currencycom.KLinesRequest{Symbol: "BTC/USD", Interval: currencycom.Interval1h}); err != nil {
+			t.Errorf("klines of an interval without data: %v", err)
+		}
+	})
+
+	t.Run("aggTrades", func(t *testing.T) {
+		out, err := api.TradesAggregated(&currencycom.AggTradesRequest{Symbol: "BTC/USD", StartTime: 1500, EndTime: 3000, Limit: 1})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(out) != 1 || out[0].Aggregate != 2 || !out[0].Price.Equal(d("101")) {
+			t.Errorf("trades %+v, want the one at 2000", out)
+		}
+	})
+
+	t.Run("ticker/24hr", func(t *testing.T) {
+		out, err := api.PriceChange(&currencycom.BySymbolRequest{Symbol: "BTC/USD"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if out.Symbol != "BTC/USD" || !out.LastPrice.Equal(d("100")) || !out.BidPrice.Equal(d("100")) {
+			t.Errorf("ticker %+v, want the price of 100", out)
+		}
+
+		emu.SetTicker(currencycom.Ticker24hr{Symbol: "BTC/USD", LastPrice: d("105"), HighPrice: d("110")})
+		out, err = api.PriceChange(&currencycom.BySymbolRequest{Symbol: "BTC/USD"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !out.LastPrice.Equal(d("105")) || !out.HighPrice.Equal(d("110")) {
+			t.Errorf("ticker %+v, want the one set", out)
+		}
+	})
+}
+
+func TestEmulatorLimitOrder(t *testing.T) {
+	emu, server := testServer(t)
+	api := currencycom.NewRestAPI("key", "secret", server.URL, currencycom.WithRetryPolicy(currencycom.NoRetry))
+
+	order, err := api.CreateOrder(&currencycom.CreateOrderRequest{
+		Symbol:   "BTC/USD",
+		Side:     currencycom.OrderSideBuy,
+		Type:     currencycom.OrderTypeLimit,
+		Quantity: currencycom.NewDecimalFromInt(1),
+		Price:    currencycom.NewDecimalFromInt(95),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !order.ExecutedQty.IsZero() {
+		t.Fatalf("order %+v, want it working under the price", order)
+	}
+
+	emu.SetPrice("BTC/USD", currencycom.NewDecimalFromInt(97))
+	if open, err := api.ListOfOpenOrder(&currencycom.PositionHistoryRequest{}); err != nil || len(open) != 1 {
+		t.Fatalf("open orders %+v, %v, want the limit still working", open, err)
+	}
+
+	emu.SetPrice("BTC/USD", currencycom.NewDecimalFromInt(94))
+	if open, err := api.ListOfOpenOrder(&currencycom.PositionHistoryRequest{}); err != nil || len(open) != 0 {
+		t.Errorf("open orders %+v, %v, want the limit filled", open, err)
+	}
+
+	fills := emu.Trader().Broker().Fills()
+	if len(fills) != 1 || fills[0].OrderId != order.OrderId || !fills[0].Price.Equal(currencycom.NewDecimalFromInt(94)) {
+		t.Errorf("fills %+v, want the limit at 94", fills)
+	}
+}
+
+func TestEmulatorDelay(t *testing.T) {
+	emu, server := testServer(t)
+	emu.Inject(Fault{Path: "time", Delay: 200 * time.Millisecond, Times: 1})
+
+	api := currencycom.NewRestAPI("key", "secret", server.URL, currencycom.WithRetryPolicy(currencycom.NoRetry))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	if _, err := api.ServerTimeWithContext(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("err = %v, want the deadline before the delay", err)
+	}
+
+	// the fault is used up, other paths are not delayed
+	begin := time.Now()
+	if _, err := api.ServerTime(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := api.ExchangeInfo(); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(begin); elapsed >= 200*time.Millisecond {
+		t.Errorf("took %v after the fault was used up", elapsed)
+	}
+
+	emu.Inject(Fault{Delay: 100 * time.Millisecond})
+	begin = time.Now()
+	if _, err := api.ServerTime(); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(begin); elapsed < 100*time.Millisecond {
+		t.Errorf("answered after %v, want the delay of 100ms", elapsed)
+	}
+}
+
+func TestEmulatorRecvWindow(t *testing.T) {
+	tests := []struct {
+		name       string
+		skew       time.Duration
+		recvWindow int64
+		code       int64
+	}{
+		{name: "default window", skew: 4 * time.Second},
+		{name: "past the default window", skew: 6 * time.Second, code: currencycom.CodeInvalidTimestamp},
+		{name: "wider window", skew: 30 * time.Second, recvWindow: 40000},
+		{name: "largest window", skew: 59 * time.Second, recvWindow: 60000},
+		{name: "past the window", skew: 30 * time.Second, recvWindow: 20000, code: currencycom.CodeInvalidTimestamp},
+		{name: "window over the maximum", recvWindow: 60001, code: codeBadParam},
+		{name: "timestamp from the future", skew: -2 * time.Second, code: currencycom.CodeInvalidTimestamp},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			emu, server := testServer(t)
+			emu.Now = func() time.Time { return time.Now().Add(tt.skew) }
+
+			api := currencycom.NewRestAPI("key", "secret", server.URL, currencycom.WithRetryPolicy(currencycom.NoRetry))
+			_, err := api.AccountInfo(&currencycom.AccountRequest{RecvWindow: tt.recvWindow})
+
+			if tt.code == 0 {
+				if err != nil {
+					t.Errorf("err = %v, want accepted", err)
+				}
+				return
+			}
+
+			var apiErr *currencycom.APIError
+			if !errors.As(err, &apiErr) || apiErr.Code != tt.code {
+				t.Errorf("err = %v, want code %d", err, tt.code)
+			}
+		})
+	}
+}
@@ -0,0 +1,189 @@
+// Package currencycomtest provides doubles of the exchange for tests of
+// code built on currencycom.
+package currencycomtest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	currencycom "github.com/scientistnik/currency.com"
+)
+
+var _ currencycom.API = (*Fake)(nil)
+
+// ErrNotScripted is returned by calls of a Fake method without a script.
+var ErrNotScripted = errors.New("currencycomtest: not scripted")
+
+// Call is a recorded call of a Fake method. Method is the name without
+// WithContext, Params the request, nil for methods without one.
+type Call struct {
+	Method string
+	Params interface{}
+}
+
+// response is a scripted result, fn when set computes it from the params.
+type response struct {
+	result interface{}
+	err    error
+	fn     func(params interface{}) (interface{}, error)
+}
+
+// Fake is an in-memory currencycom.API with scripted responses that
+// records its calls:
+//
+//	fake := currencycomtest.NewFake()
+//	fake.On("CreateOrder", &currencycom.NewOrderResponseRESULT{OrderId: "1"}, nil)
+//	fake.On("CreateOrder", nil, currencycom.ErrNotEnoughMargin)
+//
+//	bot.Run(fake) // the first order gets id 1, the next ones fail
+//
+//	calls := fake.CallsOf("CreateOrder")
+//
+// Methods with and without context share scripts and are recorded under
+// the name without WithContext. A Fake is safe for concurrent use.
+type Fake struct {
+	mu      sync.Mutex
+	scripts map[string][]response
+	calls   []Call
+}
+
+// NewFake creates a fake with no scripts.
+func NewFake() *Fake {
+	return &Fake{scripts: make(map[string][]response)}
+}
+
+// On adds a result of method, e.g. "CreateOrder". Results are returned in
+// the order they were added, the last one over and over. result must have
+// the result type of the method, nil for none.
+func (f *Fake) On(method string, result interface{}, err error) *Fake {
+	return f.script(method, response{result: result, err: err})
+}
+
+// OnFunc adds a result of method computed from the request, fn gets the
+// params of the call.
+func (f *Fake) OnFunc(method string, fn func(params interface{}) (interface{}, error)) *Fake {
+	return f.script(method, response{fn: fn})
+}
+
+func (f *Fake) script(method string, r response) *Fake {
+	if !methods[method] {
+		panic(fmt.Sprintf("currencycomtest: unknown method %q", method))
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.scripts[method] = append(f.scripts[method], r)
+
+	return f
+}
+
+// Calls returns the recorded calls, oldest first.
+func (f *Fake) Calls() []Call {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]Call(nil), f.calls...)
+}
+
+// CallsOf returns the recorded calls of method, oldest first.
+func (f *Fake) CallsOf(method string) []Call {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var out []Call
+	for _, c := range f.calls {
+		if c.Method == method {
+			out = append(out, c)
+		}
+	}
+
+	return out
+}
+
+// Reset drops the scripts and recorded calls.
+func (f *Fake) Reset() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.scripts = make(map[string][]response)
+	f.calls = nil
+}
+
+// next records a call and returns its scripted response.
+func (f *Fake) next(method string, params interface{}) (response, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.calls = append(f.calls, Call{Method: method, Params: params})
+
+	queue := f.scripts[method]
+	if len(queue) == 0 {
+		return response{}, false
+	}
+
+	if len(queue) > 1 {
+		f.scripts[method] = queue[1:]
+	}
+
+	return queue[0], true
+}
+
+func call[T any](ctx context.Context, f *Fake, method string, params interface{}) (T, error) {
+	var zero T
+
+	r, ok := f.next(method, params)
+
+	if err := ctx.Err(); err != nil {
+		return zero, err
+	}
+
+	if !ok {
+		return zero, fmt.Errorf("%w: %s", ErrNotScripted, method)
+	}
+
+	result, err := r.result, r.err
+	if r.fn != nil {
+		result, err = r.fn(params)
+	}
+
+	if result == nil {
+		return zero, err
+	}
+
+	out, ok := result.(T)
+	if !ok {
+		return zero, fmt.Errorf("currencycomtest: %s scripted with %T, want %T", method, result, zero)
+	}
+
+	return out, err
+}
+
+// methods are the names accepted by On.
+var methods = map[string]bool{
+	"ServerTime":                true,
+	"ExchangeInfo":              true,
+	"OrderBook":                 true,
+	"Klines":                    true,
+	"TradesAggregated":          true,
+	"PriceChange":               true,
+	"AccountInfo":               true,
+	"LeverageSettings":          true,
+	"ListOfTrades":              true,
+	"ListOfHistoricalPositions": true,
+	"ListOfTransactions":        true,
+	"ListOfLedgers":             true,
+	"CreateOrder":               true,
+	"CancelOrder":               true,
+	"ListOfOpenOrder":           true,
+	"LeverageOrdersEdit":        true,
+	"ListOfLeverageTrades":      true,
+	"LeverageTradeEdit":         true,
+	"TradingPositionClose":      true,
+	"ListOfCurrencies":          true,
+	"StringOfAddress":           true,
+	"ListOfDeposits":            true,
+	"ListOfWithdrawals":         true,
+}
@@ -0,0 +1,135 @@
+package currencycomtest
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	currencycom "github.com/scientistnik/currency.com"
+)
+
+func TestFakeScripts(t *testing.T) {
+	fake := NewFake().
+		On("CreateOrder", &currencycom.NewOrderResponseRESULT{OrderId: "1"}, nil).
+		On("CreateOrder", &currencycom.NewOrderResponseRESULT{OrderId: "2"}, nil).
+		On("CreateOrder", nil, currencycom.ErrNotEnoughMargin)
+
+	tests := []struct {
+		name    string
+		orderId string
+		err     error
+	}{
+		{"first", "1", nil},
+		{"second", "2", nil},
+		{"last", "", currencycom.ErrNotEnoughMargin},
+		{"last repeats", "", currencycom.ErrNotEnoughMargin},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := fake.CreateOrder(&currencycom.CreateOrderRequest{Symbol: "BTC/USD"})
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("err = %v, want %v", err, tt.err)
+			}
+			if tt.err != nil {
+				if out != nil {
+					t.Errorf("got %+v with the error, want nil", out)
+				}
+				return
+			}
+			if out.OrderId != tt.orderId {
+				t.Errorf("order id %q, want %q", out.OrderId, tt.orderId)
+			}
+		})
+	}
+}
+
+func TestFakeOnFunc(t *testing.T) {
+	fake := NewFake().OnFunc("CancelOrder", func(params interface{}) (interface{}, error) {
+		req := params.(*currencycom.CancelOrderRequest)
+		if req.OrderId == "" {
+			return nil, currencycom.ErrOrderNotFound
+		}
+
+		return &currencycom.CancelOrderResponse{OrderId: req.OrderId}, nil
+	})
+
+	out, err := fake.CancelOrder(&currencycom.CancelOrderRequest{OrderId: "42"})
+	if err != nil || out.OrderId != "42" {
+		t.Errorf("cancel 42: %+v, %v, want order 42", out, err)
+	}
+
+	if _, err := fake.CancelOrderWithContext(context.Background(), &currencycom.CancelOrderRequest{}); !errors.Is(err, currencycom.ErrOrderNotFound) {
+		t.Errorf("cancel without id: err = %v, want ErrOrderNotFound", err)
+	}
+}
+
+func TestFakeErrors(t *testing.T) {
+	fake := NewFake().On("ServerTime", &currencycom.ExchangeInfoResponse{}, nil)
+
+	if _, err := fake.ServerTime(); err == nil || errors.Is(err, ErrNotScripted) {
+		t.Errorf("result of another type: err = %v, want a type mismatch", err)
+	}
+
+	if _, err := fake.ExchangeInfo(); !errors.Is(err, ErrNotScripted) {
+		t.Errorf("no script: err = %v, want ErrNotScripted", err)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("On with an unknown method: want panic")
+		}
+	}()
+	fake.On("Withdraw", nil, nil)
+}
+
+func TestFakeCalls(t *testing.T) {
+	fake := NewFake().
+		On("ServerTime", &currencycom.ServerTimeResponse{ServerTime: 1}, nil).
+		On("CancelOrder", &currencycom.CancelOrderResponse{}, nil)
+
+	cancel := &currencycom.CancelOrderRequest{OrderId: "1"}
+
+	fake.ServerTime()
+	fake.CancelOrderWithContext(context.Background(), cancel)
+	fake.ServerTimeWithContext(context.Background())
+	fake.ExchangeInfo()
+
+	if calls := fake.Calls(); len(calls) != 4 || calls[0].Method != "ServerTime" || calls[1].Method != "CancelOrder" || calls[3].Method != "ExchangeInfo" {
+		t.Errorf("calls %+v, want all four in order", calls)
+	}
+
+	if calls := fake.CallsOf("ServerTime"); len(calls) != 2 || calls[0].Params != nil {
+		t.Errorf("calls of ServerTime %+v, want two without params", calls)
+	}
+	if calls := fake.CallsOf("CancelOrder"); len(calls) != 1 || calls[0].Params != cancel {
+		t.Errorf("calls of CancelOrder %+v, want the request", calls)
+	}
+
+	fake.Reset()
+
+	if calls := fake.Calls(); len(calls) != 0 {
+		t.Errorf("calls after Reset %+v, want none", calls)
+	}
+	if _, err := fake.ServerTime(); !errors.Is(err, ErrNotScripted) {
+		t.Errorf("after Reset: err = %v, want ErrNotScripted", err)
+	}
+}
+
+func TestFakeContext(t *testing.T) {
+	fake := NewFake().On("ServerTime", &currencycom.ServerTimeResponse{ServerTime: 1}, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := fake.ServerTimeWithContext(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("canceled context: err = %v, want context.Canceled", err)
+	}
+	if calls := fake.CallsOf("ServerTime"); len(calls) != 1 {
+		t.Errorf("calls %+v, want the canceled call recorded", calls)
+	}
+
+	if out, err := fake.ServerTime(); err != nil || out.ServerTime != 1 {
+		t.Errorf("after the canceled call: %+v, %v, want the script", out, err)
+	}
+}
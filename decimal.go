@@ -0,0 +1,151 @@
+package currencycom
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+// Decimal is an exact decimal number used for prices, quantities, fees and
+// balances. The zero value is 0. In JSON it is written as a string and read
+// from a string or a number, empty string and null read as 0.
+type Decimal struct {
+	d decimal.Decimal
+}
+
+// DivisionPrecision is the number of digits after the point kept by Div
+// when the result is not exact.
+const DivisionPrecision = 16
+
+// NewDecimal returns value * 10^exp, e.g. NewDecimal(125, -2) is 1.25.
+func NewDecimal(value int64, exp int32) Decimal {
+	return Decimal{decimal.New(value, exp)}
+}
+
+func NewDecimalFromInt(value int64) Decimal {
+	return Decimal{decimal.NewFromInt(value)}
+}
+
+// NewDecimalFromFloat converts a float using the shortest decimal
+// representation that reads back to the same float, so 0.1 becomes
+// exactly 0.1.
+func NewDecimalFromFloat(value float64) Decimal {
+	return Decimal{decimal.NewFromFloat(value)}
+}
+
+// ParseDecimal parses a number like "123.45", "-0.001" or "1e-8".
+func ParseDecimal(value string) (Decimal, error) {
+	d, err := decimal.NewFromString(value)
+	if err != nil {
+		return Decimal{}, fmt.Errorf("error in decimal %q, %w", value, err)
+	}
+
+	return Decimal{d}, nil
+}
+
+// MustDecimal is like ParseDecimal but panics on error, for constants.
+func MustDecimal(value string) Decimal {
+	d, err := ParseDecimal(value)
+	if err != nil {
+		panic(err)
+	}
+
+	return d
+}
+
+func (d Decimal) Add(d2 Decimal) Decimal { return Decimal{d.d.Add(d2.d)} }
+func (d Decimal) Sub(d2 Decimal) Decimal { return Decimal{d.d.Sub(d2.d)} }
+func (d Decimal) Mul(d2 Decimal) Decimal { return Decimal{d.d.Mul(d2.d)} }
+func (d Decimal) Neg() Decimal           { return Decimal{d.d.Neg()} }
+func (d Decimal) Abs() Decimal           { return Decimal{d.d.Abs()} }
+
+// Div returns d / d2 rounded to DivisionPrecision digits. It panics if d2
+// is zero.
+func (d Decimal) Div(d2 Decimal) Decimal {
+	return Decimal{d.d.DivRound(d2.d, DivisionPrecision)}
+}
+
+// Mod returns the remainder of d / d2, e.g. to check that a price is a
+// multiple of the tick size. It panics if d2 is zero.
+func (d Decimal) Mod(d2 Decimal) Decimal { return Decimal{d.d.Mod(d2.d)} }
+
+// Cmp returns -1 if d < d2, 0 if d == d2 and 1 if d > d2.
+func (d Decimal) Cmp(d2 Decimal) int                 { return d.d.Cmp(d2.d) }
+func (d Decimal) Equal(d2 Decimal) bool              { return d.d.Equal(d2.d) }
+func (d Decimal) LessThan(d2 Decimal) bool           { return d.d.LessThan(d2.d) }
+func (d Decimal) LessThanOrEqual(d2 Decimal) bool    { return d.d.LessThanOrEqual(d2.d) }
+func (d Decimal) GreaterThan(d2 Decimal) bool        { return d.d.GreaterThan(d2.d) }
+func (d Decimal) GreaterThanOrEqual(d2 Decimal) bool { return d.d.GreaterThanOrEqual(d2.d) }
+func (d Decimal) Sign() int                          { return d.d.Sign() }
+func (d Decimal) IsZero() bool                       { return d.d.IsZero() }
+func (d Decimal) IsPositive() bool                   { return d.d.IsPositive() }
+func (d Decimal) IsNegative() bool                   { return d.d.IsNegative() }
+
+// Round rounds half away from zero to places digits after the point.
+func (d Decimal) Round(places int32) Decimal { return Decimal{d.d.Round(places)} }
+
+// Truncate cuts the digits after places without rounding.
+func (d Decimal) Truncate(places int32) Decimal { return Decimal{d.d.Truncate(places)} }
+
+// Float64 returns the nearest float, for statistics and charts only.
+func (d Decimal) Float64() float64 { return d.d.InexactFloat64() }
+
+// IntPart returns the integer part of the number.
+func (d Decimal) IntPart() int64 { return d.d.IntPart() }
+
+// String returns the number without exponent and trailing zeros, the
+// format expected by request parameters.
+func (d Decimal) String() string { return d.d.String() }
+
+// StringFixed returns the number with exactly places digits after the point.
+func (d Decimal) StringFixed(places int32) string { return d.d.StringFixed(places) }
+
+// MinDecimal returns the smallest of the numbers.
+func MinDecimal(first Decimal, rest ...Decimal) Decimal {
+	for _, d := range rest {
+		if d.LessThan(first) {
+			first = d
+		}
+	}
+
+	return first
+}
+
+// MaxDecimal returns the largest of the numbers.
+func MaxDecimal(first Decimal, rest ...Decimal) Decimal {
+	for _, d := range rest {
+		if d.GreaterThan(first) {
+			first = d
+		}
+	}
+
+	return first
+}
+
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + d.String() + `"`), nil
+}
+
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if string(data) == "null" || string(data) == `""` {
+		*d = Decimal{}
+		return nil
+	}
+
+	return d.d.UnmarshalJSON(data)
+}
+
+func (d Decimal) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+func (d *Decimal) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		*d = Decimal{}
+		return nil
+	}
+
+	return d.d.UnmarshalText(text)
+}
@@ -0,0 +1,123 @@
+package currencycom
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParseDecimal(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+		err   bool
+	}{
+		{value: "123.45", want: "123.45"},
+		{value: "-0.001", want: "-0.001"},
+		{value: "1e-8", want: "0.00000001"},
+		{value: "1.2300", want: "1.23"},
+		{value: "0.1", want: "0.1"},
+		{value: "", err: true},
+		{value: "abc", err: true},
+		{value: "1.2.3", err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			d, err := ParseDecimal(tt.value)
+			if tt.err {
+				if err == nil {
+					t.Errorf("ParseDecimal(%q) = %s, want error", tt.value, d)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := d.String(); got != tt.want {
+				t.Errorf("ParseDecimal(%q).String() = %q, want %q", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecimalString(t *testing.T) {
+	tests := []struct {
+		d    Decimal
+		want string
+	}{
+		{Decimal{}, "0"},
+		{NewDecimal(125, -2), "1.25"},
+		{NewDecimalFromInt(-7), "-7"},
+		{NewDecimalFromFloat(0.1), "0.1"},
+		{MustDecimal("0.1").Add(MustDecimal("0.2")), "0.3"},
+		{MustDecimal("1").Div(MustDecimal("3")), "0.3333333333333333"},
+		{MustDecimal("1.005").Round(2), "1.01"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := tt.d.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecimalJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want string
+		err  bool
+	}{
+		{name: "string", data: `{"p":"0.00012"}`, want: "0.00012"},
+		{name: "number", data: `{"p":1.5}`, want: "1.5"},
+		{name: "exponent", data: `{"p":1e-8}`, want: "0.00000001"},
+		{name: "null", data: `{"p":null}`, want: "0"},
+		{name: "empty string", data: `{"p":""}`, want: "0"},
+		{name: "not a number", data: `{"p":"abc"}`, err: true},
+		{name: "bool", data: `{"p":true}`, err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var v struct {
+				P Decimal `json:"p"`
+			}
+			v.P = NewDecimalFromInt(42)
+
+			err := json.Unmarshal([]byte(tt.data), &v)
+			if tt.err {
+				if err == nil {
+					t.Errorf("decoded %s, want error", v.P)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := v.P.String(); got != tt.want {
+				t.Errorf("decoded %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecimalMarshal(t *testing.T) {
+	data, err := json.Marshal(struct {
+		P Decimal   `json:"p"`
+		Z Decimal   `json:"z"`
+		L []Decimal `json:"l"`
+	}{MustDecimal("1.50"), Decimal{}, []Decimal{MustDecimal("-2"), MustDecimal("1e-3")}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"p":"1.5","z":"0","l":["-2","0.001"]}`; string(data) != want {
+		t.Errorf("marshalled %s, want %s", data, want)
+	}
+
+	var d Decimal
+	if err := d.UnmarshalText(nil); err != nil || !d.IsZero() {
+		t.Errorf("UnmarshalText of nothing: %s, %v, want 0", d, err)
+	}
+}
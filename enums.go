@@ -0,0 +1,158 @@
+package currencycom
+
+// IsValid methods report whether the value is one of the documented ones.
+// Values coming from the server are decoded as is, so a new value added by
+// the exchange is not an error, but IsValid returns false for it.
+
+func (v RejectReasonEnum) IsValid() bool {
+	switch v {
+	case RejectReasonAccountNotFound, RejectReasonClosedMarket, RejectReasonCloseOnly, RejectReasonEngineBusy, RejectReasonHedgingModeGSL, RejectReasonInstrumentNotAvailable, RejectReasonInstrumentNotFound, RejectReasonInvalidOrder, RejectReasonInvalidOrderQty, RejectReasonInvalidPrice, RejectReasonLongOnly, RejectReasonOffMarket, RejectReasonOrderNotFound, RejectReasonOriginalGSLUpdate, RejectReasonPositionNotFound, RejectReasonRCInstrumentClientMOP, RejectReasonRCInstrumentGlobalMOP, RejectReasonRCNotEnoughMargin, RejectReasonRCNotFound, RejectReasonRCNoRates, RejectReasonRCSettlement, RejectReasonRCUnknown, RejectReasonRequiredGSL, RejectReasonRiskCheck, RejectReasonThrottling, RejectReasonUnknown:
+		return true
+	}
+
+	return false
+}
+
+func (v DtoType) IsValid() bool {
+	switch v {
+	case DtoTypeOrderCancel, DtoTypeOrderModify, DtoTypeOrderNew, DtoTypePositionModify:
+		return true
+	}
+
+	return false
+}
+
+func (v DtoState) IsValid() bool {
+	switch v {
+	case DtoStateCancelled, DtoStatePending, DtoStateProcessed:
+		return true
+	}
+
+	return false
+}
+
+func (v OrderSide) IsValid() bool {
+	switch v {
+	case OrderSideBuy, OrderSideSell:
+		return true
+	}
+
+	return false
+}
+
+func (v OrderStatus) IsValid() bool {
+	switch v {
+	case OrderStatusCanceled, OrderStatusExpired, OrderStatusFilled, OrderStatusNew, OrderStatusPartiallyFilled, OrderStatusPendingCancel, OrderStatusRejected:
+		return true
+	}
+
+	return false
+}
+
+func (v OrderTimeInForce) IsValid() bool {
+	switch v {
+	case TimeInForceFOK, TimeInForceGTC, TimeInForceIOC:
+		return true
+	}
+
+	return false
+}
+
+func (v OrderType) IsValid() bool {
+	switch v {
+	case OrderTypeLimit, OrderTypeLimitMaker, OrderTypeMarket, OrderTypeStop, OrderTypeStopLoss, OrderTypeStopLossLimit, OrderTypeTakeProfit, OrderTypeTakeProfitLimit:
+		return true
+	}
+
+	return false
+}
+
+func (v CurrencyType) IsValid() bool {
+	switch v {
+	case CurrencyTypeCrypto, CurrencyTypeExchangeToken, CurrencyTypeFiat, CurrencyTypeICO, CurrencyTypeToken, CurrencyTypeTokenisedSecurity, CurrencyTypeUtilityTokens:
+		return true
+	}
+
+	return false
+}
+
+func (v AssetType) IsValid() bool {
+	switch v {
+	case AssetTypeBond, AssetTypeCommodity, AssetTypeCredit, AssetTypeCryptocurrency, AssetTypeCurrency, AssetTypeEquity, AssetTypeICO, AssetTypeIndex, AssetTypeInterestRate, AssetTypeOtherAsset, AssetTypeRealEstate, AssetTypeUtilityTokens:
+		return true
+	}
+
+	return false
+}
+
+func (v MarketModes) IsValid() bool {
+	switch v {
+	case MarketModeClosedForCorporateAction, MarketModeCloseOnly, MarketModeHoliday, MarketModeLongOnly, MarketModeRegular, MarketModeUnknown, MarketModeViewAndRequest, MarketModeViewOnly:
+		return true
+	}
+
+	return false
+}
+
+func (v MarketType) IsValid() bool {
+	switch v {
+	case MarketTypeLeverage, MarketTypeSpot:
+		return true
+	}
+
+	return false
+}
+
+func (v ExchangeStatus) IsValid() bool {
+	switch v {
+	case ExchangeStatusAuctionMatch, ExchangeStatusBreak, ExchangeStatusEndOfDay, ExchangeStatusHalt, ExchangeStatusPostTrading, ExchangeStatusPreTrading, ExchangeStatusTrading:
+		return true
+	}
+
+	return false
+}
+
+func (v PositionDtoState) IsValid() bool {
+	switch v {
+	case PositionDtoStateActive, PositionDtoStateInactive, PositionDtoStateInvalid:
+		return true
+	}
+
+	return false
+}
+
+func (v PositionDtoType) IsValid() bool {
+	switch v {
+	case PositionDtoTypeHedge, PositionDtoTypeNet:
+		return true
+	}
+
+	return false
+}
+
+func (v ExchangeType) IsValid() bool {
+	switch v {
+	case ExchangeTypeGTC, ExchangeTypeIOC:
+		return true
+	}
+
+	return false
+}
+
+func (v ReportSource) IsValid() bool {
+	switch v {
+	case ReportSourceCloseOut, ReportSourceDealer, ReportSourceSL, ReportSourceSystem, ReportSourceTP, ReportSourceUser:
+		return true
+	}
+
+	return false
+}
+
+func (v ReportStatus) IsValid() bool {
+	switch v {
+	case ReportStatusClosed, ReportStatusDividend, ReportStatusModified, ReportStatusModifyReject, ReportStatusOpened, ReportStatusSwap:
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,121 @@
+package currencycom
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func TestEnumsIsValid(t *testing.T) {
+	tests := []struct {
+		name  string
+		value interface{ IsValid() bool }
+		want  bool
+	}{
+		{"reject reason", RejectReasonRCNotEnoughMargin, true},
+		{"unknown reject reason", RejectReasonEnum("NOT_ENOUGH_COFFEE"), false},
+		{"dto type", DtoTypeOrderNew, true},
+		{"unknown dto type", DtoType("ORDER_DELETE"), false},
+		{"dto state", DtoStateProcessed, true},
+		{"lowercase dto state", DtoState("processed"), false},
+		{"order side", OrderSideSell, true},
+		{"empty order side", OrderSide(""), false},
+		{"lowercase order side", OrderSide("buy"), false},
+		{"order status", OrderStatusPartiallyFilled, true},
+		{"unknown order status", OrderStatus("DONE"), false},
+		{"time in force", TimeInForceIOC, true},
+		{"unknown time in force", OrderTimeInForce("GTD"), false},
+		{"order type", OrderTypeTakeProfitLimit, true},
+		{"empty order type", OrderType(""), false},
+		{"unknown order type", OrderType("TRAILING_STOP"), false},
+		{"currency type", CurrencyTypeFiat, true},
+		{"unknown currency type", CurrencyType("STABLECOIN"), false},
+		{"asset type", AssetTypeEquity, true},
+		{"unknown asset type", AssetType("ART"), false},
+		{"market mode", MarketModeLongOnly, true},
+		{"unknown market mode", MarketModes("SHORT_ONLY"), false},
+		{"market type", MarketTypeLeverage, true},
+		{"unknown market type", MarketType("FUTURES"), false},
+		{"exchange status", ExchangeStatusTrading, true},
+		{"unknown exchange status", ExchangeStatus("OPEN"), false},
+		{"position state", PositionDtoStateActive, true},
+		{"unknown position state", PositionDtoState("CLOSED"), false},
+		{"position type", PositionDtoTypeNet, true},
+		{"unknown position type", PositionDtoType("GROSS"), false},
+		{"exchange type", ExchangeTypeGTC, true},
+		{"unknown exchange type", ExchangeType("FOK"), false},
+		{"report source", ReportSourceSL, true},
+		{"unknown report source", ReportSource("API"), false},
+		{"report status", ReportStatusSwap, true},
+		{"unknown report status", ReportStatus("PENDING"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.value.IsValid(); got != tt.want {
+				t.Errorf("%v.IsValid() = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+// A value added by the exchange decodes as is, IsValid tells it apart.
+func TestEnumsDecodeUnknown(t *testing.T) {
+	var order QueryOrderResponse
+	if err := json.Unmarshal([]byte(`{"side":"BUY","status":"ARCHIVED","type":"MARKET"}`), &order); err != nil {
+		t.Fatal(err)
+	}
+
+	if order.Status != "ARCHIVED" || order.Status.IsValid() {
+		t.Errorf("status %q valid %v, want ARCHIVED kept and not valid", order.Status, order.Status.IsValid())
+	}
+	if !order.Side.IsValid() || !order.Type.IsValid() {
+		t.Errorf("side %q and type %q, want both valid", order.Side, order.Type)
+	}
+}
+
+func TestCreateOrderEnums(t *testing.T) {
+	var hits int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		_, _ = w.Write([]byte(`{"orderId":"1","symbol":"BTC/USD","status":"FILLED"}`))
+	}))
+	defer srv.Close()
+
+	api := NewRestAPI("key", "secret", srv.URL, WithRetryPolicy(NoRetry))
+
+	tests := []struct {
+		name      string
+		side      OrderSide
+		orderType OrderType
+		sent      int32 // rejected orders never reach the exchange
+	}{
+		{"valid", OrderSideBuy, OrderTypeMarket, 1},
+		{"no side", "", OrderTypeMarket, 0},
+		{"lowercase side", "sell", OrderTypeMarket, 0},
+		{"no type", OrderSideBuy, "", 0},
+		{"unknown type", OrderSideBuy, "TRAILING_STOP", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := atomic.LoadInt32(&hits)
+
+			_, err := api.CreateOrderWithContext(context.Background(), &CreateOrderRequest{
+				Symbol:   "BTC/USD",
+				Side:     tt.side,
+				Type:     tt.orderType,
+				Quantity: NewDecimalFromInt(1),
+			})
+			if (err == nil) != (tt.sent == 1) {
+				t.Errorf("err = %v, want an error for a rejected order", err)
+			}
+			if sent := atomic.LoadInt32(&hits) - before; sent != tt.sent {
+				t.Errorf("sent %d requests, want %d", sent, tt.sent)
+			}
+		})
+	}
+}
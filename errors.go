@@ -0,0 +1,125 @@
+package currencycom
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Error codes returned by the exchange in the "code" field of an error body.
+const (
+	CodeTooManyRequests  int64 = -1003
+	CodeInvalidTimestamp int64 = -1021
+	CodeInvalidSignature int64 = -1022
+	CodeInvalidSymbol    int64 = -1121
+	CodeNewOrderRejected int64 = -2010
+	CodeCancelRejected   int64 = -2011
+	CodeNoSuchOrder      int64 = -2013
+	CodeBadAPIKeyFormat  int64 = -2014
+	CodeRejectedAPIKey   int64 = -2015
+)
+
+// Sentinel errors to test an *APIError against with errors.Is.
+var (
+	ErrThrottling         = errors.New("currencycom: throttling")
+	ErrInvalidTimestamp   = errors.New("currencycom: invalid timestamp")
+	ErrInvalidSignature   = errors.New("currencycom: invalid signature")
+	ErrInvalidAPIKey      = errors.New("currencycom: invalid api key")
+	ErrInvalidSymbol      = errors.New("currencycom: invalid symbol")
+	ErrNotEnoughMargin    = errors.New("currencycom: not enough margin")
+	ErrMarketClosed       = errors.New("currencycom: market closed")
+	ErrOrderNotFound      = errors.New("currencycom: order not found")
+	ErrPositionNotFound   = errors.New("currencycom: position not found")
+	ErrInvalidOrder       = errors.New("currencycom: invalid order")
+	ErrInvalidQuantity    = errors.New("currencycom: invalid order quantity")
+	ErrInvalidPrice       = errors.New("currencycom: invalid price")
+	ErrInstrumentNotFound = errors.New("currencycom: instrument not found")
+)
+
+// APIError is returned for every response with HTTP status >= 400.
+type APIError struct {
+	StatusCode int           // HTTP status code
+	Status     string        // HTTP status line, e.g. "400 Bad Request"
+	Code       int64         `json:"code"` // exchange error code, 0 if the body has none
+	Message    string        `json:"msg"`
+	Body       []byte        // raw response body
+	RetryAfter time.Duration // value of the Retry-After header, 0 if absent
+}
+
+func newAPIError(resp *http.Response, body []byte) *APIError {
+	apiErr := &APIError{
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+		Body:       body,
+		RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+	}
+
+	// body is not always a JSON, keep only the status in that case
+	_ = json.Unmarshal(body, apiErr)
+
+	return apiErr
+}
+
+func (e *APIError) Error() string {
+	if e.Code == 0 && e.Message == "" {
+		return "bad response from server, " + e.Status + ": " + string(e.Body)
+	}
+
+	return fmt.Sprintf("bad response from server, %s: code %d, %s", e.Status, e.Code, e.Message)
+}
+
+// Reason returns the reject reason mentioned in the error message,
+// or empty string if there is none.
+func (e *APIError) Reason() RejectReasonEnum {
+	words := strings.FieldsFunc(e.Message, func(r rune) bool {
+		return !(r >= 'A' && r <= 'Z' || r == '_')
+	})
+
+	for _, word := range words {
+		if reason := RejectReasonEnum(word); reason.IsValid() {
+			return reason
+		}
+	}
+
+	return ""
+}
+
+// Is reports whether the error matches one of the sentinel errors.
+func (e *APIError) Is(target error) bool {
+	reason := e.Reason()
+
+	switch target {
+	case ErrThrottling:
+		return e.StatusCode == http.StatusTooManyRequests || e.StatusCode == http.StatusTeapot ||
+			e.Code == CodeTooManyRequests || reason == RejectReasonThrottling
+	case ErrInvalidTimestamp:
+		return e.Code == CodeInvalidTimestamp
+	case ErrInvalidSignature:
+		return e.Code == CodeInvalidSignature
+	case ErrInvalidAPIKey:
+		return e.Code == CodeBadAPIKeyFormat || e.Code == CodeRejectedAPIKey
+	case ErrInvalidSymbol:
+		return e.Code == CodeInvalidSymbol
+	case ErrNotEnoughMargin:
+		return reason == RejectReasonRCNotEnoughMargin
+	case ErrMarketClosed:
+		return reason == RejectReasonClosedMarket || reason == RejectReasonOffMarket
+	case ErrOrderNotFound:
+		return e.Code == CodeNoSuchOrder || reason == RejectReasonOrderNotFound
+	case ErrPositionNotFound:
+		return reason == RejectReasonPositionNotFound
+	case ErrInvalidOrder:
+		return e.Code == CodeNewOrderRejected || reason == RejectReasonInvalidOrder
+	case ErrInvalidQuantity:
+		return reason == RejectReasonInvalidOrderQty
+	case ErrInvalidPrice:
+		return reason == RejectReasonInvalidPrice
+	case ErrInstrumentNotFound:
+		return reason == RejectReasonInstrumentNotFound || reason == RejectReasonInstrumentNotAvailable
+	}
+
+	return false
+}
@@ -0,0 +1,164 @@
+package currencycom
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+var sentinels = map[string]error{
+	"ErrThrottling":         ErrThrottling,
+	"ErrInvalidTimestamp":   ErrInvalidTimestamp,
+	"ErrInvalidSignature":   ErrInvalidSignature,
+	"ErrInvalidAPIKey":      ErrInvalidAPIKey,
+	"ErrInvalidSymbol":      ErrInvalidSymbol,
+	"ErrNotEnoughMargin":    ErrNotEnoughMargin,
+	"ErrMarketClosed":       ErrMarketClosed,
+	"ErrOrderNotFound":      ErrOrderNotFound,
+	"ErrPositionNotFound":   ErrPositionNotFound,
+	"ErrInvalidOrder":       ErrInvalidOrder,
+	"ErrInvalidQuantity":    ErrInvalidQuantity,
+	"ErrInvalidPrice":       ErrInvalidPrice,
+	"ErrInstrumentNotFound": ErrInstrumentNotFound,
+}
+
+func TestAPIErrorIs(t *testing.T) {
+	tests := []struct {
+		name string
+		err  *APIError
+		is   []string
+	}{
+		{"status 429", &APIError{StatusCode: http.StatusTooManyRequests}, []string{"ErrThrottling"}},
+		{"status 418", &APIError{StatusCode: http.StatusTeapot}, []string{"ErrThrottling"}},
+		{"code -1003", &APIError{StatusCode: 400, Code: CodeTooManyRequests}, []string{"ErrThrottling"}},
+		{"reason THROTTLING", &APIError{StatusCode: 400, Message: "Request rejected: THROTTLING"}, []string{"ErrThrottling"}},
+		{"code -1021", &APIError{StatusCode: 400, Code: CodeInvalidTimestamp}, []string{"ErrInvalidTimestamp"}},
+		{"code -1022", &APIError{StatusCode: 400, Code: CodeInvalidSignature}, []string{"ErrInvalidSignature"}},
+		{"code -1121", &APIError{StatusCode: 400, Code: CodeInvalidSymbol}, []string{"ErrInvalidSymbol"}},
+		{"code -2010", &APIError{StatusCode: 400, Code: CodeNewOrderRejected}, []string{"ErrInvalidOrder"}},
+		{"code -2011", &APIError{StatusCode: 400, Code: CodeCancelRejected}, nil},
+		{"code -2013", &APIError{StatusCode: 400, Code: CodeNoSuchOrder}, []string{"ErrOrderNotFound"}},
+		{"code -2014", &APIError{StatusCode: 401, Code: CodeBadAPIKeyFormat}, []string{"ErrInvalidAPIKey"}},
+		{"code -2015", &APIError{StatusCode: 401, Code: CodeRejectedAPIKey}, []string{"ErrInvalidAPIKey"}},
+		{"reason RC_NOT_ENOUGH_MARGIN", &APIError{StatusCode: 400, Code: CodeNewOrderRejected, Message: "New order rejected: RC_NOT_ENOUGH_MARGIN"}, []string{"ErrInvalidOrder", "ErrNotEnoughMargin"}},
+		{"reason CLOSED_MARKET", &APIError{StatusCode: 400, Message: "CLOSED_MARKET"}, []string{"ErrMarketClosed"}},
+		{"reason OFF_MARKET", &APIError{StatusCode: 400, Message: "OFF_MARKET"}, []string{"ErrMarketClosed"}},
+		{"reason ORDER_NOT_FOUND", &APIError{StatusCode: 400, Message: "ORDER_NOT_FOUND"}, []string{"ErrOrderNotFound"}},
+		{"reason POSITION_NOT_FOUND", &APIError{StatusCode: 400, Message: "POSITION_NOT_FOUND"}, []string{"ErrPositionNotFound"}},
+		{"reason INVALID_ORDER", &APIError{StatusCode: 400, Message: "INVALID_ORDER"}, []string{"ErrInvalidOrder"}},
+		{"reason INVALID_ORDER_QTY", &APIError{StatusCode: 400, Message: "INVALID_ORDER_QTY"}, []string{"ErrInvalidQuantity"}},
+		{"reason INVALID_PRICE", &APIError{StatusCode: 400, Message: "INVALID_PRICE"}, []string{"ErrInvalidPrice"}},
+		{"reason INSTRUMENT_NOT_FOUND", &APIError{StatusCode: 400, Message: "INSTRUMENT_NOT_FOUND"}, []string{"ErrInstrumentNotFound"}},
+		{"reason INSTRUMENT_NOT_AVAILABLE", &APIError{StatusCode: 400, Message: "INSTRUMENT_NOT_AVAILABLE"}, []string{"ErrInstrumentNotFound"}},
+		{"unknown code", &APIError{StatusCode: 400, Code: -1000, Message: "An unknown error occurred"}, nil},
+		{"server error", &APIError{StatusCode: 503}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := map[string]bool{}
+			for _, name := range tt.is {
+				want[name] = true
+			}
+
+			// wrapped like the callers of the library do
+			err := fmt.Errorf("error in order, %w", tt.err)
+
+			for name, sentinel := range sentinels {
+				if got := errors.Is(err, sentinel); got != want[name] {
+					t.Errorf("errors.Is(%v, %s) = %v, want %v", err, name, got, want[name])
+				}
+			}
+		})
+	}
+}
+
+func TestAPIErrorReason(t *testing.T) {
+	tests := []struct {
+		message string
+		want    RejectReasonEnum
+	}{
+		{"New order rejected: RC_NOT_ENOUGH_MARGIN", RejectReasonRCNotEnoughMargin},
+		{"INVALID_ORDER_QTY, min 0.001", RejectReasonInvalidOrderQty},
+		{"Order rejected (ORDER_NOT_FOUND).", RejectReasonOrderNotFound},
+		{"NOT_A_REASON", ""},
+		{"invalid_price", ""},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.message, func(t *testing.T) {
+			if got := (&APIError{Message: tt.message}).Reason(); got != tt.want {
+				t.Errorf("Reason() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewAPIError(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     int
+		header     http.Header
+		body       string
+		code       int64
+		message    string
+		retryAfter time.Duration
+		text       string
+	}{
+		{
+			name:    "json body",
+			status:  http.StatusBadRequest,
+			body:    `{"code":-1121,"msg":"Invalid symbol."}`,
+			code:    CodeInvalidSymbol,
+			message: "Invalid symbol.",
+			text:    "bad response from server, 400 Bad Request: code -1121, Invalid symbol.",
+		},
+		{
+			name:       "retry after",
+			status:     http.StatusTooManyRequests,
+			header:     http.Header{"Retry-After": {"3"}},
+			body:       `{"code":-1003,"msg":"Too many requests."}`,
+			code:       CodeTooManyRequests,
+			message:    "Too many requests.",
+			retryAfter: 3 * time.Second,
+			text:       "bad response from server, 429 Too Many Requests: code -1003, Too many requests.",
+		},
+		{
+			name:   "html body",
+			status: http.StatusBadGateway,
+			body:   `<html>bad gateway</html>`,
+			text:   "bad response from server, 502 Bad Gateway: <html>bad gateway</html>",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				for k, v := range tt.header {
+					w.Header()[k] = v
+				}
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer srv.Close()
+
+			_, err := NewClient(srv.URL, WithRetryPolicy(NoRetry)).ServerTime()
+
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("err = %v, want *APIError", err)
+			}
+			if apiErr.StatusCode != tt.status || apiErr.Code != tt.code || apiErr.Message != tt.message ||
+				apiErr.RetryAfter != tt.retryAfter || string(apiErr.Body) != tt.body {
+				t.Errorf("got %+v", apiErr)
+			}
+			if apiErr.Error() != tt.text {
+				t.Errorf("Error() = %q, want %q", apiErr.Error(), tt.text)
+			}
+		})
+	}
+}
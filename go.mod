@@ -1,3 +1,8 @@
 module github.com/scientistnik/currency.com
 
 go 1.18
+
+require (
+	github.com/gorilla/websocket v1.5.3
+	github.com/shopspring/decimal v1.4.0
+)
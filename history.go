@@ -0,0 +1,287 @@
+package currencycom
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// DEFAULT_PAGE_LIMIT is the page size of history iterators when the
+// request has no Limit.
+const DEFAULT_PAGE_LIMIT int32 = 500
+
+// ErrPageOverflow is returned by Err of history iterators when a whole
+// page of records shares one millisecond, so the rest of that millisecond
+// can't be reached by time. Retry with a larger Limit.
+var ErrPageOverflow = errors.New("currencycom: page overflow")
+
+// pager walks [start, end] in windows of Window. A full page moves the
+// window start to the time of its last record, records of that
+// millisecond seen on the previous page are skipped by id.
+// A full page of one millisecond stops the walk with ErrPageOverflow.
+type pager[T any] struct {
+	// Window is the longest time range asked in one request.
+	Window time.Duration
+	// Pause is the least delay between requests, on top of the rate
+	// limiter and retry policy of the client.
+	Pause time.Duration
+
+	ctx   context.Context
+	fetch func(ctx context.Context, start, end int64, limit int32) ([]T, error)
+	key   func(T) (id string, ts int64)
+
+	cursor, end int64
+	limit       int32
+	seen        map[string]struct{}
+	page        []T
+	current     T
+	requested   time.Time
+	err         error
+}
+
+func newPager[T any](ctx context.Context, start, end int64, limit int32, window time.Duration,
+	fetch func(context.Context, int64, int64, int32) ([]T, error), key func(T) (string, int64)) *pager[T] {
+	if end == 0 {
+		end = time.Now().UnixMilli()
+	}
+
+	if limit <= 0 {
+		limit = DEFAULT_PAGE_LIMIT
+	}
+
+	var err error
+	if start <= 0 {
+		err = fmt.Errorf("error params: StartTime need to set")
+	}
+
+	return &pager[T]{
+		err:    err,
+		Window: window,
+		ctx:    ctx,
+		fetch:  fetch,
+		key:    key,
+		cursor: start,
+		end:    end,
+		limit:  limit,
+	}
+}
+
+// Next advances to the next record, fetching pages as needed. It returns
+// false at the end of the range, on error or when the context is done,
+// check Err to tell them apart.
+func (p *pager[T]) Next() bool {
+	for len(p.page) == 0 {
+		if p.err != nil || p.cursor > p.end {
+			return false
+		}
+
+		if err := p.fetchPage(); err != nil {
+			p.err = err
+			return false
+		}
+	}
+
+	p.current, p.page = p.page[0], p.page[1:]
+
+	return true
+}
+
+// Err returns the error that stopped the iteration, nil at the end of the
+// range. ErrPageOverflow comes after the records of the overflowing page.
+func (p *pager[T]) Err() error {
+	return p.err
+}
+
+func (p *pager[T]) fetchPage() error {
+	if err := p.ctx.Err(); err != nil {
+		return err
+	}
+
+	if p.Pause > 0 && !p.requested.IsZero() {
+		if err := sleep(p.ctx, p.Pause-time.Since(p.requested)); err != nil {
+			return err
+		}
+	}
+
+	windowEnd := p.end
+	if p.Window > 0 && p.cursor+p.Window.Milliseconds()-1 < windowEnd {
+		windowEnd = p.cursor + p.Window.Milliseconds() - 1
+	}
+
+	p.requested = time.Now()
+
+	records, err := p.fetch(p.ctx, p.cursor, windowEnd, p.limit)
+	if err != nil {
+		return err
+	}
+
+	sort.SliceStable(records, func(i, j int) bool {
+		_, ti := p.key(records[i])
+		_, tj := p.key(records[j])
+		return ti < tj
+	})
+
+	next := windowEnd + 1
+	overflow := false
+	if len(records) >= int(p.limit) {
+		// the window has more records, continue from the last one
+		_, last := p.key(records[len(records)-1])
+		next = last
+		// a whole page in one millisecond, the rest of it can't be reached
+		overflow = last <= p.cursor
+	}
+
+	seen := make(map[string]struct{})
+	page := records[:0]
+	for _, record := range records {
+		id, ts := p.key(record)
+		if _, dup := p.seen[id]; dup {
+			continue
+		}
+
+		if ts >= next {
+			seen[id] = struct{}{}
+		}
+		page = append(page, record)
+	}
+
+	if overflow {
+		// hand out the page, then stop instead of skipping the rest
+		p.err = fmt.Errorf("error in page at %d, more than %d records in one millisecond, %w", p.cursor, p.limit, ErrPageOverflow)
+		p.page, p.cursor = page, p.end+1
+
+		return nil
+	}
+
+	p.page, p.seen, p.cursor = page, seen, next
+
+	return nil
+}
+
+// TransactionIterator walks transactions, deposits, withdrawals or
+// ledger records of a time range:
+//
+//	it := api.ListOfLedgersIterator(ctx, &currencycom.TransactionsRequest{StartTime: from})
+//	for it.Next() {
+//		record := it.Transaction()
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type TransactionIterator struct {
+	*pager[TransactionDTOResponse]
+}
+
+// Transaction returns the current record.
+func (it *TransactionIterator) Transaction() TransactionDTOResponse {
+	return it.current
+}
+
+// TradeIterator walks the account trades of a time range.
+type TradeIterator struct {
+	*pager[MyTradesResponse]
+}
+
+// Trade returns the current trade.
+func (it *TradeIterator) Trade() MyTradesResponse {
+	return it.current
+}
+
+// AggTradeIterator walks the aggregated trades of a time range.
+type AggTradeIterator struct {
+	*pager[AggTrades]
+}
+
+// AggTrade returns the current trade.
+func (it *AggTradeIterator) AggTrade() AggTrades {
+	return it.current
+}
+
+// ListOfTransactionsIterator walks transactions from params.StartTime to
+// params.EndTime (0 means now) in windows of 30 days.
+func (r RestAPI) ListOfTransactionsIterator(ctx context.Context, params *TransactionsRequest) *TransactionIterator {
+	return r.transactionIterator(ctx, params, r.ListOfTransactionsWithContext)
+}
+
+// ListOfDepositsIterator walks deposits like ListOfTransactionsIterator.
+func (r RestAPI) ListOfDepositsIterator(ctx context.Context, params *TransactionsRequest) *TransactionIterator {
+	return r.transactionIterator(ctx, params, r.ListOfDepositsWithContext)
+}
+
+// ListOfWithdrawalsIterator walks withdrawals like ListOfTransactionsIterator.
+func (r RestAPI) ListOfWithdrawalsIterator(ctx context.Context, params *TransactionsRequest) *TransactionIterator {
+	return r.transactionIterator(ctx, params, r.ListOfWithdrawalsWithContext)
+}
+
+// ListOfLedgersIterator walks ledger records like ListOfTransactionsIterator.
+func (r RestAPI) ListOfLedgersIterator(ctx context.Context, params *TransactionsRequest) *TransactionIterator {
+	return r.transactionIterator(ctx, params, r.ListOfLedgersWithContext)
+}
+
+func (r RestAPI) transactionIterator(ctx context.Context, params *TransactionsRequest,
+	list func(context.Context, *TransactionsRequest) ([]TransactionDTOResponse, error)) *TransactionIterator {
+	if params == nil {
+		params = &TransactionsRequest{}
+	}
+	base := *params
+
+	fetch := func(ctx context.Context, start, end int64, limit int32) ([]TransactionDTOResponse, error) {
+		req := base
+		req.StartTime, req.EndTime, req.Limit = start, end, limit
+
+		return list(ctx, &req)
+	}
+
+	key := func(t TransactionDTOResponse) (string, int64) {
+		return fmt.Sprint(t.Id), t.Timestamp
+	}
+
+	return &TransactionIterator{newPager(ctx, base.StartTime, base.EndTime, base.Limit, 30*24*time.Hour, fetch, key)}
+}
+
+// ListOfTradesIterator walks the account trades of params.Symbol from
+// params.StartTime to params.EndTime (0 means now) in windows of a day.
+func (r RestAPI) ListOfTradesIterator(ctx context.Context, params *AllMyTradesRequest) *TradeIterator {
+	if params == nil {
+		params = &AllMyTradesRequest{}
+	}
+	base := *params
+
+	fetch := func(ctx context.Context, start, end int64, limit int32) ([]MyTradesResponse, error) {
+		req := base
+		req.StartTime, req.EndTime, req.Limit = start, end, limit
+
+		return r.ListOfTradesWithContext(ctx, &req)
+	}
+
+	key := func(t MyTradesResponse) (string, int64) {
+		return t.Id, t.Time
+	}
+
+	return &TradeIterator{newPager(ctx, base.StartTime, base.EndTime, base.Limit, 24*time.Hour, fetch, key)}
+}
+
+// TradesAggregatedIterator walks the aggregated trades of params.Symbol
+// from params.StartTime to params.EndTime (0 means now) in windows of an
+// hour.
+func (c Client) TradesAggregatedIterator(ctx context.Context, params *AggTradesRequest) *AggTradeIterator {
+	if params == nil {
+		params = &AggTradesRequest{}
+	}
+	base := *params
+
+	fetch := func(ctx context.Context, start, end int64, limit int32) ([]AggTrades, error) {
+		req := base
+		req.StartTime, req.EndTime, req.Limit = start, end, limit
+
+		return c.TradesAggregatedWithContext(ctx, &req)
+	}
+
+	key := func(t AggTrades) (string, int64) {
+		return fmt.Sprint(t.Aggregate), t.Timestamp
+	}
+
+	return &AggTradeIterator{newPager(ctx, base.StartTime, base.EndTime, base.Limit, time.Hour, fetch, key)}
+}
@@ -0,0 +1,95 @@
+package currencycom
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+type testRecord struct {
+	id string
+	ts int64
+}
+
+func TestPagerPageBoundary(t *testing.T) {
+	// c and d share the millisecond where the first page ends, so c comes
+	// back on the second page too
+	records := []testRecord{{"a", 1}, {"b", 2}, {"c", 3}, {"d", 3}, {"e", 4}, {"f", 7}, {"g", 9}}
+
+	var requests [][2]int64
+	fetch := fetchRecords(records, &requests)
+	key := func(r testRecord) (string, int64) { return r.id, r.ts }
+
+	p := newPager(context.Background(), 1, 10, 3, 0, fetch, key)
+
+	var got []string
+	for p.Next() {
+		got = append(got, p.current.id)
+	}
+	if err := p.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"a", "b", "c", "d", "e", "f", "g"}
+	if !equalStrings(got, want) {
+		t.Errorf("records %v, want %v", got, want)
+	}
+
+	// every full page continues from its last millisecond, g is fetched
+	// twice and returned once
+	wantRequests := [][2]int64{{1, 10}, {3, 10}, {4, 10}, {9, 10}}
+	if len(requests) != len(wantRequests) {
+		t.Fatalf("requests %v, want %v", requests, wantRequests)
+	}
+	for i := range wantRequests {
+		if requests[i] != wantRequests[i] {
+			t.Errorf("requests %v, want %v", requests, wantRequests)
+			break
+		}
+	}
+}
+
+func TestPagerOverflow(t *testing.T) {
+	// b, c and d share a millisecond and don't fit in a page of 2
+	records := []testRecord{{"a", 1}, {"b", 2}, {"c", 2}, {"d", 2}, {"e", 3}}
+
+	var requests [][2]int64
+	key := func(r testRecord) (string, int64) { return r.id, r.ts }
+	p := newPager(context.Background(), 1, 10, 2, 0, fetchRecords(records, &requests), key)
+
+	var got []string
+	for p.Next() {
+		got = append(got, p.current.id)
+	}
+
+	if err := p.Err(); !errors.Is(err, ErrPageOverflow) {
+		t.Fatalf("Err() = %v, want ErrPageOverflow", err)
+	}
+
+	// the overflowing page is handed out, nothing after it is skipped
+	// silently
+	if want := []string{"a", "b", "c"}; !equalStrings(got, want) {
+		t.Errorf("records %v, want %v", got, want)
+	}
+
+	if p.Next() {
+		t.Error("Next after the overflow: want false")
+	}
+}
+
+// fetchRecords returns a fetch of the pager serving records of the asked
+// range, recording the ranges in requests.
+func fetchRecords(records []testRecord, requests *[][2]int64) func(context.Context, int64, int64, int32) ([]testRecord, error) {
+	return func(ctx context.Context, start, end int64, limit int32) ([]testRecord, error) {
+		*requests = append(*requests, [2]int64{start, end})
+
+		var out []testRecord
+		for _, r := range records {
+			if r.ts >= start && r.ts <= end && len(out) < int(limit) {
+				out = append(out, r)
+			}
+		}
+
+		return out, nil
+	}
+}
@@ -0,0 +1,398 @@
+package currencycom
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const week = 7 * 24 * time.Hour
+
+// TradingSession is one trading interval of a week day in local time of
+// the schedule. Open and Close are offsets from midnight, Close may be
+// 24h. A Close before Open continues on the next day.
+type TradingSession struct {
+	Day   time.Weekday
+	Open  time.Duration
+	Close time.Duration
+}
+
+// TradingHours is a weekly trading schedule parsed from
+// ExchangeSymbolInfo.TradingHours. A schedule without sessions is always
+// open.
+type TradingHours struct {
+	Location *time.Location
+	Sessions []TradingSession
+}
+
+// span is a session placed in time.
+type span struct {
+	open, close time.Time
+}
+
+// ParseTradingHours parses a schedule like
+//
+//	UTC; Mon 13:30 - 20:00; Tue - 22:00, 22:05 -
+//
+// A missing open means the start of the day, a missing close the end of
+// the day. The leading timezone is optional, timezone (usually
+// ExchangeInfoResponse.Timezone) is used without it, UTC if both are
+// empty.
+func ParseTradingHours(hours, timezone string) (*TradingHours, error) {
+	parts := strings.Split(hours, ";")
+
+	if first := strings.TrimSpace(parts[0]); first != "" {
+		if _, _, err := parseWeekday(first); err != nil {
+			timezone = first
+			parts = parts[1:]
+		}
+	}
+
+	loc, err := loadTimezone(timezone)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &TradingHours{Location: loc}
+
+	for _, part := range parts {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		day, rest, err := parseWeekday(part)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, r := range strings.Split(rest, ",") {
+			session, err := parseSession(day, r)
+			if err != nil {
+				return nil, fmt.Errorf("error in trading hours %q, %w", part, err)
+			}
+			out.Sessions = append(out.Sessions, session)
+		}
+	}
+
+	return out, nil
+}
+
+// IsOpen reports whether at falls into a session.
+func (h *TradingHours) IsOpen(at time.Time) bool {
+	if len(h.Sessions) == 0 {
+		return true
+	}
+
+	for _, s := range h.spans(at) {
+		if !at.Before(s.open) && at.Before(s.close) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// NextOpen returns the next time after at the market opens. It returns
+// false if the schedule has no sessions or never closes.
+func (h *TradingHours) NextOpen(at time.Time) (time.Time, bool) {
+	if h.alwaysOpen(at) {
+		return time.Time{}, false
+	}
+
+	for _, s := range h.spans(at) {
+		if s.open.After(at) {
+			return s.open, true
+		}
+	}
+
+	return time.Time{}, false
+}
+
+// NextClose returns the end of the current session, or of the next one if
+// the market is closed at at. It returns false if the market never closes.
+func (h *TradingHours) NextClose(at time.Time) (time.Time, bool) {
+	if h.alwaysOpen(at) {
+		return time.Time{}, false
+	}
+
+	for _, s := range h.spans(at) {
+		if s.close.After(at) {
+			return s.close, true
+		}
+	}
+
+	return time.Time{}, false
+}
+
+func (h *TradingHours) alwaysOpen(at time.Time) bool {
+	if len(h.Sessions) == 0 {
+		return true
+	}
+
+	// a week of spans merged into one covers the whole week
+	spans := h.spans(at)
+	for _, s := range spans {
+		if s.close.Sub(s.open) >= 2*week {
+			return true
+		}
+	}
+
+	return false
+}
+
+// spans places the sessions from a week before to two weeks after at and
+// merges the adjoining ones, e.g. Mon 22:05 - 24:00 and Tue 00:00 - 22:00.
+func (h *TradingHours) spans(at time.Time) []span {
+	local := at.In(h.Location)
+	y, m, d := local.Date()
+	weekStart := d - int(local.Weekday())
+
+	var spans []span
+	for w := -1; w <= 2; w++ {
+		for _, s := range h.Sessions {
+			day := weekStart + 7*w + int(s.Day)
+			closeAt := s.Close
+			if closeAt <= s.Open {
+				closeAt += 24 * time.Hour
+			}
+
+			// time.Date normalizes the minutes into a wall clock time of
+			// the day, so sessions keep their local hours over DST changes
+			spans = append(spans, span{
+				open:  time.Date(y, m, day, 0, int(s.Open/time.Minute), 0, 0, h.Location),
+				close: time.Date(y, m, day, 0, int(closeAt/time.Minute), 0, 0, h.Location),
+			})
+		}
+	}
+
+	sort.Slice(spans, func(i, j int) bool { return spans[i].open.Before(spans[j].open) })
+
+	merged := spans[:0]
+	for _, s := range spans {
+		if n := len(merged); n > 0 && !s.open.After(merged[n-1].close) {
+			if s.close.After(merged[n-1].close) {
+				merged[n-1].close = s.close
+			}
+			continue
+		}
+		merged = append(merged, s)
+	}
+
+	return merged
+}
+
+// MarketSchedule tells when a symbol can be traded: its trading hours
+// combined with its exchange status and market modes. Status and modes
+// are the ones of the ExchangeInfo the schedule was made from.
+type MarketSchedule struct {
+	Symbol      string
+	Hours       *TradingHours
+	Status      ExchangeStatus
+	MarketModes []MarketModes
+}
+
+// NewMarketSchedule parses the trading hours of the symbol, timezone is
+// ExchangeInfoResponse.Timezone.
+func NewMarketSchedule(info *ExchangeSymbolInfo, timezone string) (*MarketSchedule, error) {
+	hours, err := ParseTradingHours(info.TradingHours, timezone)
+	if err != nil {
+		return nil, fmt.Errorf("error in %s, %w", info.Symbol, err)
+	}
+
+	return &MarketSchedule{
+		Symbol:      info.Symbol,
+		Hours:       hours,
+		Status:      info.Status,
+		MarketModes: info.MarketModes,
+	}, nil
+}
+
+// Tradeable reports whether the status and the market modes allow opening
+// positions, regardless of the time.
+func (s *MarketSchedule) Tradeable() bool {
+	if s.Status != "" && s.Status != ExchangeStatusTrading {
+		return false
+	}
+
+	if len(s.MarketModes) == 0 {
+		return true
+	}
+
+	for _, mode := range s.MarketModes {
+		if mode == MarketModeRegular || mode == MarketModeLongOnly {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsOpen reports whether the symbol is tradeable and in session at at.
+func (s *MarketSchedule) IsOpen(at time.Time) bool {
+	return s.Tradeable() && s.Hours.IsOpen(at)
+}
+
+// NextOpen returns the next session start after at. It returns false if
+// the symbol is not tradeable, as the schedule can't tell when that ends.
+func (s *MarketSchedule) NextOpen(at time.Time) (time.Time, bool) {
+	if !s.Tradeable() {
+		return time.Time{}, false
+	}
+
+	return s.Hours.NextOpen(at)
+}
+
+// NextClose returns the end of the current or the next session. It
+// returns false if the symbol is not tradeable.
+func (s *MarketSchedule) NextClose(at time.Time) (time.Time, bool) {
+	if !s.Tradeable() {
+		return time.Time{}, false
+	}
+
+	return s.Hours.NextClose(at)
+}
+
+// Schedule returns the trading schedule of the symbol.
+func (s *SymbolRegistry) Schedule(name string) (*MarketSchedule, error) {
+	s.mu.RLock()
+	symbol, ok := s.byName[name]
+	var timezone string
+	if s.info != nil {
+		timezone = s.info.Timezone
+	}
+	s.mu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("error params: unknown symbol %q", name)
+	}
+
+	return NewMarketSchedule(symbol, timezone)
+}
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// parseWeekday splits "Mon 13:30 - 20:00" into the day and the rest.
+func parseWeekday(s string) (time.Weekday, string, error) {
+	end := strings.IndexFunc(s, func(r rune) bool { return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z') })
+	if end < 0 {
+		end = len(s)
+	}
+
+	name := strings.ToLower(s[:end])
+	if len(name) >= 3 {
+		if day, ok := weekdays[name[:3]]; ok && strings.HasPrefix(strings.ToLower(day.String()), name) {
+			return day, s[end:], nil
+		}
+	}
+
+	return 0, "", fmt.Errorf("error in trading hours %q, need a week day", s)
+}
+
+// parseSession parses "13:30 - 20:00", either side may be empty.
+func parseSession(day time.Weekday, s string) (TradingSession, error) {
+	bounds := strings.Split(s, "-")
+	if len(bounds) != 2 {
+		return TradingSession{}, fmt.Errorf("need open - close, got %q", strings.TrimSpace(s))
+	}
+
+	session := TradingSession{Day: day, Close: 24 * time.Hour}
+
+	if open := strings.TrimSpace(bounds[0]); open != "" {
+		d, err := parseClock(open)
+		if err != nil {
+			return TradingSession{}, err
+		}
+		session.Open = d
+	}
+
+	if closeAt := strings.TrimSpace(bounds[1]); closeAt != "" {
+		d, err := parseClock(closeAt)
+		if err != nil {
+			return TradingSession{}, err
+		}
+		session.Close = d
+	}
+
+	return session, nil
+}
+
+// parseClock parses "HH:MM" up to "24:00".
+func parseClock(s string) (time.Duration, error) {
+	parts := strings.Split(s, ":")
+	if len(parts) != 2 {
+		return 0, fmt.Errorf("need HH:MM, got %q", s)
+	}
+
+	hour, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, fmt.Errorf("need HH:MM, got %q", s)
+	}
+
+	minute, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return 0, fmt.Errorf("need HH:MM, got %q", s)
+	}
+
+	d := time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute
+	if hour < 0 || minute < 0 || minute >= 60 || d > 24*time.Hour {
+		return 0, fmt.Errorf("time %q is out of range", s)
+	}
+
+	return d, nil
+}
+
+// loadTimezone accepts UTC, GMT, offsets like UTC+3 or GMT-05:30 and IANA
+// names like Europe/London.
+func loadTimezone(name string) (*time.Location, error) {
+	name = strings.TrimSpace(name)
+
+	upper := strings.ToUpper(name)
+	if upper == "" || upper == "UTC" || upper == "GMT" || upper == "Z" {
+		return time.UTC, nil
+	}
+
+	if strings.HasPrefix(upper, "UTC") || strings.HasPrefix(upper, "GMT") {
+		offset := upper[3:]
+		sign := 1
+		switch offset[0] {
+		case '+':
+		case '-':
+			sign = -1
+		default:
+			return nil, fmt.Errorf("error in timezone %q", name)
+		}
+
+		hm := strings.SplitN(offset[1:], ":", 2)
+		hours, err := strconv.Atoi(hm[0])
+		if err != nil {
+			return nil, fmt.Errorf("error in timezone %q", name)
+		}
+
+		minutes := 0
+		if len(hm) == 2 {
+			if minutes, err = strconv.Atoi(hm[1]); err != nil {
+				return nil, fmt.Errorf("error in timezone %q", name)
+			}
+		}
+
+		return time.FixedZone(name, sign*(hours*3600+minutes*60)), nil
+	}
+
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("error in timezone %q, %w", name, err)
+	}
+
+	return loc, nil
+}
@@ -0,0 +1,190 @@
+package currencycom
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTradingHoursNext(t *testing.T) {
+	// Wednesday
+	at := time.Date(2021, 6, 2, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name              string
+		hours             string
+		open, close       time.Time
+		hasOpen, hasClose bool
+	}{
+		{
+			name:     "in session",
+			hours:    "UTC; Mon 09:00 - 17:00; Tue 09:00 - 17:00; Wed 09:00 - 17:00; Thu 09:00 - 17:00; Fri 09:00 - 17:00",
+			open:     time.Date(2021, 6, 3, 9, 0, 0, 0, time.UTC),
+			close:    time.Date(2021, 6, 2, 17, 0, 0, 0, time.UTC),
+			hasOpen:  true,
+			hasClose: true,
+		},
+		{
+			name:     "closed until next week",
+			hours:    "UTC; Mon 09:00 - 17:00",
+			open:     time.Date(2021, 6, 7, 9, 0, 0, 0, time.UTC),
+			close:    time.Date(2021, 6, 7, 17, 0, 0, 0, time.UTC),
+			hasOpen:  true,
+			hasClose: true,
+		},
+		{
+			name:  "always open",
+			hours: "UTC; Sun -; Mon -; Tue -; Wed -; Thu -; Fri -; Sat -",
+		},
+		{
+			name:  "no sessions",
+			hours: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, err := ParseTradingHours(tt.hours, "")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			open, ok := h.NextOpen(at)
+			if ok != tt.hasOpen || !open.Equal(tt.open) {
+				t.Errorf("NextOpen = %v %v, want %v %v", open, ok, tt.open, tt.hasOpen)
+			}
+
+			closeAt, ok := h.NextClose(at)
+			if ok != tt.hasClose || !closeAt.Equal(tt.close) {
+				t.Errorf("NextClose = %v %v, want %v %v", closeAt, ok, tt.close, tt.hasClose)
+			}
+		})
+	}
+}
+
+func TestTradingHoursIsOpen(t *testing.T) {
+	tests := []struct {
+		name  string
+		hours string
+		at    time.Time
+		open  bool
+	}{
+		{"in session", "UTC; Wed 09:00 - 17:00", time.Date(2021, 6, 2, 9, 0, 0, 0, time.UTC), true},
+		{"at the close", "UTC; Wed 09:00 - 17:00", time.Date(2021, 6, 2, 17, 0, 0, 0, time.UTC), false},
+		{"other day", "UTC; Wed 09:00 - 17:00", time.Date(2021, 6, 3, 12, 0, 0, 0, time.UTC), false},
+		{"second session of the day", "UTC; Wed 09:00 - 12:00, 13:00 - 17:00", time.Date(2021, 6, 2, 13, 30, 0, 0, time.UTC), true},
+		{"between sessions", "UTC; Wed 09:00 - 12:00, 13:00 - 17:00", time.Date(2021, 6, 2, 12, 30, 0, 0, time.UTC), false},
+		{"overnight before midnight", "UTC; Fri 22:00 - 02:00", time.Date(2021, 6, 4, 23, 0, 0, 0, time.UTC), true},
+		{"overnight after midnight", "UTC; Fri 22:00 - 02:00", time.Date(2021, 6, 5, 1, 59, 0, 0, time.UTC), true},
+		{"overnight over", "UTC; Fri 22:00 - 02:00", time.Date(2021, 6, 5, 2, 0, 0, 0, time.UTC), false},
+		{"overnight into the next week", "UTC; Sat 23:00 - 01:00", time.Date(2021, 6, 6, 0, 30, 0, 0, time.UTC), true},
+		{"open ended days", "UTC; Tue 22:05 -; Wed - 22:00", time.Date(2021, 6, 2, 0, 0, 0, 0, time.UTC), true},
+		{"local time of the schedule", "UTC+3; Wed 10:00 - 12:00", time.Date(2021, 6, 2, 7, 30, 0, 0, time.UTC), true},
+		{"utc time of a shifted schedule", "UTC+3; Wed 10:00 - 12:00", time.Date(2021, 6, 2, 10, 30, 0, 0, time.UTC), false},
+		{"no sessions", "", time.Date(2021, 6, 2, 3, 0, 0, 0, time.UTC), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, err := ParseTradingHours(tt.hours, "")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := h.IsOpen(tt.at); got != tt.open {
+				t.Errorf("IsOpen(%v) = %v, want %v", tt.at, got, tt.open)
+			}
+		})
+	}
+}
+
+func TestLoadTimezone(t *testing.T) {
+	winter := time.Date(2021, 1, 15, 12, 0, 0, 0, time.UTC)
+	summer := time.Date(2021, 7, 15, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		winter, summer time.Duration
+		err            bool
+	}{
+		{name: "", winter: 0, summer: 0},
+		{name: "UTC", winter: 0, summer: 0},
+		{name: "gmt", winter: 0, summer: 0},
+		{name: "UTC+3", winter: 3 * time.Hour, summer: 3 * time.Hour},
+		{name: "GMT-05:30", winter: -5*time.Hour - 30*time.Minute, summer: -5*time.Hour - 30*time.Minute},
+		{name: "utc+02:00", winter: 2 * time.Hour, summer: 2 * time.Hour},
+		{name: "Europe/London", winter: 0, summer: time.Hour},
+		{name: "America/New_York", winter: -5 * time.Hour, summer: -4 * time.Hour},
+		{name: "UTC*3", err: true},
+		{name: "GMT+x", err: true},
+		{name: "GMT+3:xx", err: true},
+		{name: "Mars/Olympus_Mons", err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loc, err := loadTimezone(tt.name)
+			if tt.err {
+				if err == nil {
+					t.Errorf("loadTimezone(%q) = %v, want error", tt.name, loc)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for _, c := range []struct {
+				at   time.Time
+				want time.Duration
+			}{{winter, tt.winter}, {summer, tt.summer}} {
+				if _, offset := c.at.In(loc).Zone(); time.Duration(offset)*time.Second != c.want {
+					t.Errorf("offset at %v = %v, want %v", c.at, time.Duration(offset)*time.Second, c.want)
+				}
+			}
+		})
+	}
+}
+
+// Sessions are wall clock times of the schedule, so a session over a DST
+// change lasts an hour less or more.
+func TestTradingHoursDST(t *testing.T) {
+	tests := []struct {
+		name   string
+		hours  string
+		at     time.Time
+		open   bool
+		next   time.Time // NextOpen if closed, NextClose if open
+		nextOk bool
+	}{
+		// clocks go forward at 01:00 UTC on Sunday 28 March 2021
+		{"spring, open before the change", "Europe/London; Sat 22:00 - 04:00", time.Date(2021, 3, 27, 23, 0, 0, 0, time.UTC), true, time.Date(2021, 3, 28, 3, 0, 0, 0, time.UTC), true},
+		{"spring, open after the change", "Europe/London; Sat 22:00 - 04:00", time.Date(2021, 3, 28, 2, 30, 0, 0, time.UTC), true, time.Date(2021, 3, 28, 3, 0, 0, 0, time.UTC), true},
+		{"spring, closed at 04:00 BST", "Europe/London; Sat 22:00 - 04:00", time.Date(2021, 3, 28, 3, 30, 0, 0, time.UTC), false, time.Date(2021, 4, 3, 21, 0, 0, 0, time.UTC), true},
+		// clocks go back at 01:00 UTC on Sunday 31 October 2021
+		{"autumn, open before the change", "Europe/London; Sat 22:00 - 04:00", time.Date(2021, 10, 30, 21, 30, 0, 0, time.UTC), true, time.Date(2021, 10, 31, 4, 0, 0, 0, time.UTC), true},
+		{"autumn, open at 03:30 GMT", "Europe/London; Sat 22:00 - 04:00", time.Date(2021, 10, 31, 3, 30, 0, 0, time.UTC), true, time.Date(2021, 10, 31, 4, 0, 0, 0, time.UTC), true},
+		{"next open over the change", "America/New_York; Mon 09:30 - 16:00", time.Date(2021, 3, 12, 21, 0, 0, 0, time.UTC), false, time.Date(2021, 3, 15, 13, 30, 0, 0, time.UTC), true},
+		{"next open before the change", "America/New_York; Mon 09:30 - 16:00", time.Date(2021, 3, 5, 21, 0, 0, 0, time.UTC), false, time.Date(2021, 3, 8, 14, 30, 0, 0, time.UTC), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, err := ParseTradingHours(tt.hours, "")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := h.IsOpen(tt.at); got != tt.open {
+				t.Errorf("IsOpen(%v) = %v, want %v", tt.at, got, tt.open)
+			}
+
+			next, ok := h.NextOpen(tt.at)
+			if tt.open {
+				next, ok = h.NextClose(tt.at)
+			}
+			if ok != tt.nextOk || !next.Equal(tt.next) {
+				t.Errorf("next = %v %v, want %v %v", next.UTC(), ok, tt.next, tt.nextOk)
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+// Package indicators computes technical indicators over currencycom klines.
+//
+// Every indicator comes in two forms: a streaming one updated bar by bar,
+// e.g. NewEMA(20).Update(price), and a batch function over a whole series,
+// e.g. EMASeries(closes, 20). Batch results have the length of the input
+// with math.NaN() for the bars before the indicator is ready.
+//
+// Values are float64: indicators are statistics, exact decimals are kept
+// for prices and quantities sent to the exchange.
+package indicators
+
+import (
+	"math"
+
+	currencycom "github.com/scientistnik/currency.com"
+)
+
+// Closes returns the close prices of klines.
+func Closes(klines []currencycom.Kline) []float64 {
+	out := make([]float64, len(klines))
+	for i, k := range klines {
+		out[i] = k.Close.Float64()
+	}
+
+	return out
+}
+
+// TypicalPrice returns (high + low + close) / 3 of the bar.
+func TypicalPrice(k currencycom.Kline) float64 {
+	return (k.High.Float64() + k.Low.Float64() + k.Close.Float64()) / 3
+}
+
+// nanSeries returns a series of n NaN values.
+func nanSeries(n int) []float64 {
+	out := make([]float64, n)
+	for i := range out {
+		out[i] = math.NaN()
+	}
+
+	return out
+}
+
+// series runs a streaming indicator over values.
+func series(values []float64, update func(float64) (float64, bool)) []float64 {
+	out := nanSeries(len(values))
+	for i, v := range values {
+		if value, ok := update(v); ok {
+			out[i] = value
+		}
+	}
+
+	return out
+}
+
+func atLeastOne(period int) int {
+	if period < 1 {
+		return 1
+	}
+
+	return period
+}
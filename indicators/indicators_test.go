@@ -0,0 +1,251 @@
+package indicators
+
+import (
+	"math"
+	"testing"
+
+	currencycom "github.com/scientistnik/currency.com"
+)
+
+// closes of the EMA example of StockCharts ChartSchool, "Moving Averages
+// - Simple and Exponential".
+var stockChartsCloses = []float64{
+	22.27, 22.19, 22.08, 22.17, 22.18, 22.13, 22.23, 22.43, 22.24, 22.29,
+	22.15, 22.39, 22.38, 22.61, 23.36, 24.05, 23.75, 23.83, 23.95, 23.63,
+	23.82, 23.87, 23.65, 23.19, 23.10, 23.33, 22.68, 23.10, 22.40, 22.17,
+}
+
+// closes of Wilder's RSI example as published by StockCharts ChartSchool,
+// "Relative Strength Index (RSI)".
+var wilderCloses = []float64{
+	44.34, 44.09, 44.15, 43.61, 44.33, 44.83, 45.10, 45.42, 45.84, 46.08,
+	45.89, 46.03, 45.61, 46.28, 46.28, 46.00, 46.03, 46.41, 46.22, 45.64,
+	46.21, 46.25, 45.71, 46.45, 45.78, 45.35, 44.03, 44.18, 44.22, 44.57,
+	43.42, 42.66, 43.13,
+}
+
+var nan = math.NaN()
+
+func TestMovingAverages(t *testing.T) {
+	tests := []struct {
+		name   string
+		series func([]float64, int) []float64
+		update func(period int) func(float64) (float64, bool)
+		want   []float64
+	}{
+		{
+			name:   "SMA",
+			series: SMASeries,
+			update: func(period int) func(float64) (float64, bool) { return NewSMA(period).Update },
+			want: []float64{
+				nan, nan, nan, nan, nan, nan, nan, nan, nan,
+				22.22, 22.21, 22.23, 22.26, 22.30, 22.42, 22.61, 22.77, 22.91, 23.08, 23.21,
+				23.38, 23.52, 23.65, 23.71, 23.68, 23.61, 23.51, 23.43, 23.28, 23.13,
+			},
+		},
+		{
+			name:   "EMA",
+			series: EMASeries,
+			update: func(period int) func(float64) (float64, bool) { return NewEMA(period).Update },
+			want: []float64{
+				nan, nan, nan, nan, nan, nan, nan, nan, nan,
+				22.22, 22.21, 22.24, 22.27, 22.33, 22.52, 22.80, 22.97, 23.13, 23.28, 23.34,
+				23.43, 23.51, 23.53, 23.47, 23.40, 23.39, 23.26, 23.23, 23.08, 22.92,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.series(stockChartsCloses, 10)
+			// the published values are rounded to cents
+			assertSeries(t, got, tt.want, 0.006)
+			assertStreaming(t, got, stockChartsCloses, tt.update(10))
+		})
+	}
+}
+
+func TestRSI(t *testing.T) {
+	want := append(nanSeries(14),
+		70.53, 66.32, 66.55, 69.41, 66.36, 57.97, 62.93, 63.26, 56.06, 62.38,
+		54.71, 50.42, 39.99, 41.46, 41.87, 45.46, 37.30, 33.08, 37.77,
+	)
+
+	got := RSISeries(wilderCloses, 14)
+	// StockCharts rounds the average gain and loss to cents at every step,
+	// which moves the published values by up to 0.07
+	assertSeries(t, got, want, 0.08)
+	assertStreaming(t, got, wilderCloses, NewRSI(14).Update)
+
+	flat := RSISeries([]float64{1, 1, 1, 1}, 2)
+	assertSeries(t, flat, []float64{nan, nan, 50, 50}, 0)
+
+	rising := RSISeries([]float64{1, 2, 3, 4}, 2)
+	assertSeries(t, rising, []float64{nan, nan, 100, 100}, 0)
+}
+
+func TestMACD(t *testing.T) {
+	prices := []float64{1, 2, 3, 5, 8, 13, 21, 34}
+
+	// EMA(2) - EMA(3), both seeded with an SMA, and its EMA(2)
+	want := []MACDValue{
+		{nan, nan, nan},
+		{nan, nan, nan},
+		{nan, nan, nan},
+		{0.666667, 0.583333, 0.083333},
+		{0.972222, 0.842593, 0.129630},
+		{1.532407, 1.302469, 0.229938},
+		{2.448302, 2.066358, 0.381944},
+		{3.951517, 3.323131, 0.628386},
+	}
+
+	got := MACDSeries(prices, 2, 3, 2)
+	m := NewMACD(2, 3, 2)
+
+	for i := range want {
+		if !approx(got[i].MACD, want[i].MACD, 1e-6) || !approx(got[i].Signal, want[i].Signal, 1e-6) || !approx(got[i].Histogram, want[i].Histogram, 1e-6) {
+			t.Errorf("MACD[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+
+		v, ok := m.Update(prices[i])
+		if ok == math.IsNaN(got[i].MACD) || ok && v != got[i] {
+			t.Errorf("streaming MACD[%d] = %+v %v, batch %+v", i, v, ok, got[i])
+		}
+	}
+}
+
+func TestBollinger(t *testing.T) {
+	prices := []float64{1, 2, 3, 4, 5, 6, 6, 6, 6, 6}
+	dev := 2 * math.Sqrt(2)
+
+	want := []Band{
+		{nan, nan, nan}, {nan, nan, nan}, {nan, nan, nan}, {nan, nan, nan},
+		{3 + dev, 3, 3 - dev},
+		{4 + dev, 4, 4 - dev},
+		// 3 4 5 6 6
+		{4.8 + 2*math.Sqrt(1.36), 4.8, 4.8 - 2*math.Sqrt(1.36)},
+		// 4 5 6 6 6
+		{5.4 + 2*math.Sqrt(0.64), 5.4, 5.4 - 2*math.Sqrt(0.64)},
+		// 5 6 6 6 6
+		{5.8 + 2*math.Sqrt(0.16), 5.8, 5.8 - 2*math.Sqrt(0.16)},
+		// flat
+		{6, 6, 6},
+	}
+
+	got := BollingerSeries(prices, 5, 2)
+	b := NewBollinger(5, 2)
+
+	for i := range want {
+		if !approx(got[i].Upper, want[i].Upper, 1e-9) || !approx(got[i].Middle, want[i].Middle, 1e-9) || !approx(got[i].Lower, want[i].Lower, 1e-9) {
+			t.Errorf("Bollinger[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+
+		v, ok := b.Update(prices[i])
+		if ok == math.IsNaN(got[i].Middle) || ok && v != got[i] {
+			t.Errorf("streaming Bollinger[%d] = %+v %v, batch %+v", i, v, ok, got[i])
+		}
+	}
+}
+
+func TestATR(t *testing.T) {
+	klines := []currencycom.Kline{
+		bar(10, 8, 9, 0),
+		bar(11, 9, 10.5, 0),
+		bar(12, 10, 11, 0),   // true ranges 2, 2, 2
+		bar(15, 11, 14, 0),   // 4
+		bar(14, 13, 13.5, 0), // |13 - 14| = 1
+		bar(20, 18, 19, 0),   // gap, |20 - 13.5| = 6.5
+	}
+
+	want := []float64{nan, nan, 2, (2*2 + 4) / 3.0, 0, 0}
+	want[4] = (want[3]*2 + 1) / 3
+	want[5] = (want[4]*2 + 6.5) / 3
+
+	got := ATRSeries(klines, 3)
+	assertSeries(t, got, want, 1e-9)
+
+	assertStreaming(t, got, klines, NewATR(3).Update)
+}
+
+func TestVWAP(t *testing.T) {
+	klines := []currencycom.Kline{
+		bar(10, 10, 10, 0),   // no volume yet
+		bar(11, 9, 10, 100),  // typical price 10
+		bar(12, 10, 11, 300), // typical price 11
+		bar(13, 11, 12, 0),
+	}
+
+	want := []float64{nan, 10, (10*100 + 11*300) / 400.0, (10*100 + 11*300) / 400.0}
+
+	got := VWAPSeries(klines)
+	assertSeries(t, got, want, 1e-9)
+	assertStreaming(t, got, klines, NewVWAP().Update)
+
+	v := NewVWAP()
+	v.Update(klines[1])
+	v.Reset()
+	if value, ok := v.Update(klines[2]); !ok || value != 11 {
+		t.Errorf("VWAP after Reset = %v %v, want 11", value, ok)
+	}
+}
+
+func TestPivots(t *testing.T) {
+	want := Pivots{Pivot: 100, R1: 110, R2: 120, S1: 90, S2: 80}
+
+	if got := PivotsOf(110, 90, 100); got != want {
+		t.Errorf("PivotsOf = %+v, want %+v", got, want)
+	}
+
+	ticker := currencycom.Ticker24hr{
+		HighPrice: currencycom.NewDecimalFromInt(110),
+		LowPrice:  currencycom.NewDecimalFromInt(90),
+		LastPrice: currencycom.NewDecimalFromInt(100),
+	}
+	if got := TickerPivots(ticker); got != want {
+		t.Errorf("TickerPivots = %+v, want %+v", got, want)
+	}
+}
+
+func bar(high, low, closePrice, volume float64) currencycom.Kline {
+	return currencycom.Kline{
+		High:   currencycom.NewDecimalFromFloat(high),
+		Low:    currencycom.NewDecimalFromFloat(low),
+		Close:  currencycom.NewDecimalFromFloat(closePrice),
+		Volume: currencycom.NewDecimalFromFloat(volume),
+	}
+}
+
+func approx(got, want, tolerance float64) bool {
+	if math.IsNaN(want) || math.IsNaN(got) {
+		return math.IsNaN(want) && math.IsNaN(got)
+	}
+
+	return math.Abs(got-want) <= tolerance
+}
+
+func assertSeries(t *testing.T, got, want []float64, tolerance float64) {
+	t.Helper()
+
+	if len(got) != len(want) {
+		t.Fatalf("got %d values, want %d", len(got), len(want))
+	}
+
+	for i := range want {
+		if !approx(got[i], want[i], tolerance) {
+			t.Errorf("[%d] = %v, want %v", i, got[i], want[i])
+		}
+	}
+}
+
+// assertStreaming feeds inputs to a fresh streaming indicator and checks
+// it agrees with the batch result bar by bar.
+func assertStreaming[T any](t *testing.T, batch []float64, inputs []T, update func(T) (float64, bool)) {
+	t.Helper()
+
+	for i, input := range inputs {
+		value, ok := update(input)
+		if ok == math.IsNaN(batch[i]) || ok && value != batch[i] {
+			t.Errorf("streaming [%d] = %v %v, batch %v", i, value, ok, batch[i])
+		}
+	}
+}
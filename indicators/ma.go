@@ -0,0 +1,75 @@
+package indicators
+
+// SMA is a simple moving average of the last Period values.
+type SMA struct {
+	period int
+	window []float64
+	next   int
+	sum    float64
+}
+
+// NewSMA creates an SMA, periods below 1 mean 1.
+func NewSMA(period int) *SMA {
+	period = atLeastOne(period)
+
+	return &SMA{period: period, window: make([]float64, 0, period)}
+}
+
+// Update adds a value and returns the average, ok is false until Period
+// values were added.
+func (s *SMA) Update(value float64) (float64, bool) {
+	if len(s.window) < s.period {
+		s.window = append(s.window, value)
+	} else {
+		s.sum -= s.window[s.next]
+		s.window[s.next] = value
+		s.next = (s.next + 1) % s.period
+	}
+	s.sum += value
+
+	if len(s.window) < s.period {
+		return 0, false
+	}
+
+	return s.sum / float64(s.period), true
+}
+
+// SMASeries returns the SMA of every value.
+func SMASeries(values []float64, period int) []float64 {
+	return series(values, NewSMA(period).Update)
+}
+
+// EMA is an exponential moving average with the smoothing factor
+// 2 / (Period + 1), seeded with the SMA of the first Period values.
+type EMA struct {
+	period int
+	alpha  float64
+	seed   *SMA
+	value  float64
+	ready  bool
+}
+
+// NewEMA creates an EMA, periods below 1 mean 1.
+func NewEMA(period int) *EMA {
+	period = atLeastOne(period)
+
+	return &EMA{period: period, alpha: 2 / float64(period+1), seed: NewSMA(period)}
+}
+
+// Update adds a value and returns the average, ok is false until Period
+// values were added.
+func (e *EMA) Update(value float64) (float64, bool) {
+	if !e.ready {
+		e.value, e.ready = e.seed.Update(value)
+		return e.value, e.ready
+	}
+
+	e.value += e.alpha * (value - e.value)
+
+	return e.value, true
+}
+
+// EMASeries returns the EMA of every value.
+func EMASeries(values []float64, period int) []float64 {
+	return series(values, NewEMA(period).Update)
+}
@@ -0,0 +1,112 @@
+package indicators
+
+import "math"
+
+// RSI is the relative strength index with Wilder's smoothing.
+type RSI struct {
+	period           int
+	prev             float64
+	count            int
+	gain, loss       float64
+	avgGain, avgLoss float64
+}
+
+// NewRSI creates an RSI, periods below 1 mean 1. The usual period is 14.
+func NewRSI(period int) *RSI {
+	return &RSI{period: atLeastOne(period)}
+}
+
+// Update adds a price and returns the RSI from 0 to 100, ok is false until
+// Period changes were seen.
+func (r *RSI) Update(price float64) (float64, bool) {
+	r.count++
+	if r.count == 1 {
+		r.prev = price
+		return 0, false
+	}
+
+	change := price - r.prev
+	r.prev = price
+
+	gain, loss := math.Max(change, 0), math.Max(-change, 0)
+	n := float64(r.period)
+
+	switch {
+	case r.count <= r.period:
+		r.gain += gain
+		r.loss += loss
+		return 0, false
+	case r.count == r.period+1:
+		r.avgGain = (r.gain + gain) / n
+		r.avgLoss = (r.loss + loss) / n
+	default:
+		r.avgGain = (r.avgGain*(n-1) + gain) / n
+		r.avgLoss = (r.avgLoss*(n-1) + loss) / n
+	}
+
+	if r.avgLoss == 0 {
+		if r.avgGain == 0 {
+			return 50, true
+		}
+		return 100, true
+	}
+
+	return 100 - 100/(1+r.avgGain/r.avgLoss), true
+}
+
+// RSISeries returns the RSI of every price.
+func RSISeries(prices []float64, period int) []float64 {
+	return series(prices, NewRSI(period).Update)
+}
+
+// MACDValue is one value of MACD.
+type MACDValue struct {
+	MACD      float64 // fast EMA - slow EMA
+	Signal    float64 // EMA of MACD
+	Histogram float64 // MACD - Signal
+}
+
+// MACD is the moving average convergence divergence.
+type MACD struct {
+	fast, slow, signal *EMA
+}
+
+// NewMACD creates a MACD, the usual periods are 12, 26 and 9.
+func NewMACD(fast, slow, signal int) *MACD {
+	return &MACD{fast: NewEMA(fast), slow: NewEMA(slow), signal: NewEMA(signal)}
+}
+
+// Update adds a price, ok is false until the signal line is ready.
+func (m *MACD) Update(price float64) (MACDValue, bool) {
+	fast, fastOk := m.fast.Update(price)
+	slow, slowOk := m.slow.Update(price)
+	if !fastOk || !slowOk {
+		return MACDValue{}, false
+	}
+
+	macd := fast - slow
+	signal, ok := m.signal.Update(macd)
+	if !ok {
+		return MACDValue{}, false
+	}
+
+	return MACDValue{MACD: macd, Signal: signal, Histogram: macd - signal}, true
+}
+
+// MACDSeries returns the MACD of every price, values before the signal
+// line is ready are NaN.
+func MACDSeries(prices []float64, fast, slow, signal int) []MACDValue {
+	m := NewMACD(fast, slow, signal)
+	nan := math.NaN()
+
+	out := make([]MACDValue, len(prices))
+	for i, p := range prices {
+		if v, ok := m.Update(p); ok {
+			out[i] = v
+		} else {
+			out[i] = MACDValue{MACD: nan, Signal: nan, Histogram: nan}
+		}
+	}
+
+	return out
+}
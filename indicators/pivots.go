@@ -0,0 +1,31 @@
+package indicators
+
+import (
+	currencycom "github.com/scientistnik/currency.com"
+)
+
+// Pivots are classic floor pivot points.
+type Pivots struct {
+	Pivot  float64
+	R1, R2 float64 // resistances
+	S1, S2 float64 // supports
+}
+
+// PivotsOf computes pivot points from the high, low and close of a bar,
+// usually the previous day.
+func PivotsOf(high, low, closePrice float64) Pivots {
+	p := (high + low + closePrice) / 3
+
+	return Pivots{
+		Pivot: p,
+		R1:    2*p - low,
+		R2:    p + high - low,
+		S1:    2*p - high,
+		S2:    p - high + low,
+	}
+}
+
+// TickerPivots computes pivot points from the 24h range of a ticker.
+func TickerPivots(t currencycom.Ticker24hr) Pivots {
+	return PivotsOf(t.HighPrice.Float64(), t.LowPrice.Float64(), t.LastPrice.Float64())
+}
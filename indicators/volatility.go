@@ -0,0 +1,124 @@
+package indicators
+
+import (
+	"math"
+
+	currencycom "github.com/scientistnik/currency.com"
+)
+
+// Band is one value of Bollinger Bands.
+type Band struct {
+	Upper  float64
+	Middle float64 // SMA
+	Lower  float64
+}
+
+// Bollinger is Bollinger Bands: the SMA of Period prices plus and minus K
+// population standard deviations.
+type Bollinger struct {
+	k      float64
+	sma    *SMA
+	sumSq  float64
+	period int
+}
+
+// NewBollinger creates the bands, the usual parameters are 20 and 2.
+func NewBollinger(period int, k float64) *Bollinger {
+	period = atLeastOne(period)
+
+	return &Bollinger{k: k, sma: NewSMA(period), period: period}
+}
+
+// Update adds a price, ok is false until Period prices were added.
+func (b *Bollinger) Update(price float64) (Band, bool) {
+	if len(b.sma.window) == b.period {
+		old := b.sma.window[b.sma.next]
+		b.sumSq -= old * old
+	}
+	b.sumSq += price * price
+
+	mean, ok := b.sma.Update(price)
+	if !ok {
+		return Band{}, false
+	}
+
+	variance := b.sumSq/float64(b.period) - mean*mean
+	if variance < 0 {
+		variance = 0 // rounding of a flat series
+	}
+	dev := b.k * math.Sqrt(variance)
+
+	return Band{Upper: mean + dev, Middle: mean, Lower: mean - dev}, true
+}
+
+// BollingerSeries returns the bands of every price, values before the
+// bands are ready are NaN.
+func BollingerSeries(prices []float64, period int, k float64) []Band {
+	b := NewBollinger(period, k)
+	nan := math.NaN()
+
+	out := make([]Band, len(prices))
+	for i, p := range prices {
+		if v, ok := b.Update(p); ok {
+			out[i] = v
+		} else {
+			out[i] = Band{Upper: nan, Middle: nan, Lower: nan}
+		}
+	}
+
+	return out
+}
+
+// ATR is the average true range with Wilder's smoothing.
+type ATR struct {
+	period int
+	count  int
+	prev   float64 // previous close
+	sum    float64
+	value  float64
+}
+
+// NewATR creates an ATR, periods below 1 mean 1. The usual period is 14.
+func NewATR(period int) *ATR {
+	return &ATR{period: atLeastOne(period)}
+}
+
+// Update adds a bar, ok is false until Period bars were added.
+func (a *ATR) Update(k currencycom.Kline) (float64, bool) {
+	high, low, closePrice := k.High.Float64(), k.Low.Float64(), k.Close.Float64()
+
+	tr := high - low
+	if a.count > 0 {
+		tr = math.Max(tr, math.Max(math.Abs(high-a.prev), math.Abs(low-a.prev)))
+	}
+	a.prev = closePrice
+	a.count++
+
+	n := float64(a.period)
+
+	switch {
+	case a.count < a.period:
+		a.sum += tr
+		return 0, false
+	case a.count == a.period:
+		a.value = (a.sum + tr) / n
+	default:
+		a.value = (a.value*(n-1) + tr) / n
+	}
+
+	return a.value, true
+}
+
+// ATRSeries returns the ATR of every bar.
+func ATRSeries(klines []currencycom.Kline, period int) []float64 {
+	a := NewATR(period)
+
+	out := nanSeries(len(klines))
+	for i, k := range klines {
+		if v, ok := a.Update(k); ok {
+			out[i] = v
+		}
+	}
+
+	return out
+}
@@ -0,0 +1,47 @@
+package indicators
+
+import (
+	currencycom "github.com/scientistnik/currency.com"
+)
+
+// VWAP is the volume weighted average of the typical price since the
+// start or the last Reset, e.g. at the session open.
+type VWAP struct {
+	value, volume float64
+}
+
+func NewVWAP() *VWAP {
+	return &VWAP{}
+}
+
+// Update adds a bar, ok is false while no volume was traded.
+func (v *VWAP) Update(k currencycom.Kline) (float64, bool) {
+	volume := k.Volume.Float64()
+	v.value += TypicalPrice(k) * volume
+	v.volume += volume
+
+	if v.volume == 0 {
+		return 0, false
+	}
+
+	return v.value / v.volume, true
+}
+
+// Reset starts a new averaging period.
+func (v *VWAP) Reset() {
+	v.value, v.volume = 0, 0
+}
+
+// VWAPSeries returns the running VWAP of every bar from the first one.
+func VWAPSeries(klines []currencycom.Kline) []float64 {
+	v := NewVWAP()
+
+	out := nanSeries(len(klines))
+	for i, k := range klines {
+		if value, ok := v.Update(k); ok {
+			out[i] = value
+		}
+	}
+
+	return out
+}
@@ -0,0 +1,83 @@
+package currencycom
+
+import "context"
+
+// The interfaces below cover the REST methods by area, so code can take
+// the smallest one it needs and tests can pass a fake instead of RestAPI,
+// e.g. currencycomtest.Fake. RestAPI implements all of them, Client
+// implements MarketData.
+
+// MarketData is the public market data of the exchange.
+type MarketData interface {
+	ServerTime() (*ServerTimeResponse, error)
+	ServerTimeWithContext(ctx context.Context) (*ServerTimeResponse, error)
+	ExchangeInfo() (*ExchangeInfoResponse, error)
+	ExchangeInfoWithContext(ctx context.Context) (*ExchangeInfoResponse, error)
+	OrderBook(params *DepthRequest) (*DepthResponse, error)
+	OrderBookWithContext(ctx context.Context, params *DepthRequest) (*DepthResponse, error)
+	Klines(params *KLinesRequest) ([]Kline, error)
+	KlinesWithContext(ctx context.Context, params *KLinesRequest) ([]Kline, error)
+	TradesAggregated(params *AggTradesRequest) ([]AggTrades, error)
+	TradesAggregatedWithContext(ctx context.Context, params *AggTradesRequest) ([]AggTrades, error)
+	PriceChange(params *BySymbolRequest) (*Ticker24hr, error)
+	PriceChangeWithContext(ctx context.Context, params *BySymbolRequest) (*Ticker24hr, error)
+}
+
+// Account is the account state and history.
+type Account interface {
+	AccountInfo(params *AccountRequest) (*AccountResponse, error)
+	AccountInfoWithContext(ctx context.Context, params *AccountRequest) (*AccountResponse, error)
+	LeverageSettings(params *LeverageSettingsRequest) (*LeverageSettingsResponse, error)
+	LeverageSettingsWithContext(ctx context.Context, params *LeverageSettingsRequest) (*LeverageSettingsResponse, error)
+	ListOfTrades(params *AllMyTradesRequest) ([]MyTradesResponse, error)
+	ListOfTradesWithContext(ctx context.Context, params *AllMyTradesRequest) ([]MyTradesResponse, error)
+	ListOfHistoricalPositions(params *PositionHistoryRequest) (*TradingPositionHistoryResponse, error)
+	ListOfHistoricalPositionsWithContext(ctx context.Context, params *PositionHistoryRequest) (*TradingPositionHistoryResponse, error)
+	ListOfTransactions(params *TransactionsRequest) ([]TransactionDTOResponse, error)
+	ListOfTransactionsWithContext(ctx context.Context, params *TransactionsRequest) ([]TransactionDTOResponse, error)
+	ListOfLedgers(params *TransactionsRequest) ([]TransactionDTOResponse, error)
+	ListOfLedgersWithContext(ctx context.Context, params *TransactionsRequest) ([]TransactionDTOResponse, error)
+}
+
+// Trading places and manages orders and leverage positions.
+type Trading interface {
+	CreateOrder(params *CreateOrderRequest) (*NewOrderResponseRESULT, error)
+	CreateOrderWithContext(ctx context.Context, params *CreateOrderRequest) (*NewOrderResponseRESULT, error)
+	CancelOrder(params *CancelOrderRequest) (*CancelOrderResponse, error)
+	CancelOrderWithContext(ctx context.Context, params *CancelOrderRequest) (*CancelOrderResponse, error)
+	ListOfOpenOrder(params *PositionHistoryRequest) ([]QueryOrderResponse, error)
+	ListOfOpenOrderWithContext(ctx context.Context, params *PositionHistoryRequest) ([]QueryOrderResponse, error)
+	LeverageOrdersEdit(params *UpdateTradingOrderRequest) (*TradingOrderUpdateResponse, error)
+	LeverageOrdersEditWithContext(ctx context.Context, params *UpdateTradingOrderRequest) (*TradingOrderUpdateResponse, error)
+	ListOfLeverageTrades(params *SignedRequest) (*TradingPositionListResponse, error)
+	ListOfLeverageTradesWithContext(ctx context.Context, params *SignedRequest) (*TradingPositionListResponse, error)
+	LeverageTradeEdit(params *UpdateTradingPositionRequest) (*TradingPositionUpdateResponse, error)
+	LeverageTradeEditWithContext(ctx context.Context, params *UpdateTradingPositionRequest) (*TradingPositionUpdateResponse, error)
+	TradingPositionClose(params *CloseTradingPositionRequest) (*TradingPositionCloseAllResponse, error)
+	TradingPositionCloseWithContext(ctx context.Context, params *CloseTradingPositionRequest) (*TradingPositionCloseAllResponse, error)
+}
+
+// Wallet is deposits and withdrawals.
+type Wallet interface {
+	ListOfCurrencies(params *SignedRequest) ([]CurrencyDtoResponse, error)
+	ListOfCurrenciesWithContext(ctx context.Context, params *SignedRequest) ([]CurrencyDtoResponse, error)
+	StringOfAddress(params *BlockchainAddressRequest) (*BlockchainAddressGetResponse, error)
+	StringOfAddressWithContext(ctx context.Context, params *BlockchainAddressRequest) (*BlockchainAddressGetResponse, error)
+	ListOfDeposits(params *TransactionsRequest) ([]TransactionDTOResponse, error)
+	ListOfDepositsWithContext(ctx context.Context, params *TransactionsRequest) ([]TransactionDTOResponse, error)
+	ListOfWithdrawals(params *TransactionsRequest) ([]TransactionDTOResponse, error)
+	ListOfWithdrawalsWithContext(ctx context.Context, params *TransactionsRequest) ([]TransactionDTOResponse, error)
+}
+
+// API is the whole REST API.
+type API interface {
+	MarketData
+	Account
+	Trading
+	Wallet
+}
+
+var (
+	_ MarketData = Client{}
+	_ API        = RestAPI{}
+)
@@ -0,0 +1,103 @@
+package currencycom
+
+import (
+	"strconv"
+	"time"
+)
+
+// Interval is a kline interval like "15m", "4h" or "1w".
+type Interval string
+
+// Intervals served by Klines and OHLCMarketData.subscribe.
+const (
+	Interval1m  Interval = "1m"
+	Interval5m  Interval = "5m"
+	Interval15m Interval = "15m"
+	Interval30m Interval = "30m"
+	Interval1h  Interval = "1h"
+	Interval4h  Interval = "4h"
+	Interval1d  Interval = "1d"
+	Interval1w  Interval = "1w"
+)
+
+// IsValid reports whether the exchange serves the interval. Other
+// intervals can be built locally with CandleBuilder.
+func (v Interval) IsValid() bool {
+	switch v {
+	case Interval1m, Interval5m, Interval15m, Interval30m, Interval1h, Interval4h, Interval1d, Interval1w:
+		return true
+	}
+
+	return false
+}
+
+// CandleType is the kind of bars of KLinesRequest.Type and
+// OHLCSubscribeRequest.Type, empty means CandleTypeClassic.
+type CandleType string
+
+// Candle types served by Klines and OHLCMarketData.subscribe.
+const (
+	CandleTypeClassic    CandleType = "classic"
+	CandleTypeHeikinAshi CandleType = "heikin-ashi"
+)
+
+// IsValid reports whether the exchange serves the candle type.
+func (v CandleType) IsValid() bool {
+	switch v {
+	case CandleTypeClassic, CandleTypeHeikinAshi:
+		return true
+	}
+
+	return false
+}
+
+// Duration returns the length of a bar, also for intervals the exchange
+// doesn't serve like "2h". It returns 0 for malformed intervals.
+func (v Interval) Duration() time.Duration {
+	d, _ := intervalDuration(string(v))
+
+	return d
+}
+
+// Truncate returns the open time of the bar containing t. Bars are
+// aligned to the Unix epoch, weekly ones to the first Monday after it.
+func (v Interval) Truncate(t time.Time) time.Time {
+	d := v.Duration()
+	if d <= 0 {
+		return t
+	}
+
+	openAt, _ := (&CandleBuilder{Interval: d}).bounds(t)
+
+	return openAt
+}
+
+// CloseTime returns the last millisecond of the bar containing t, the way
+// Kline.CloseTime is set.
+func (v Interval) CloseTime(t time.Time) time.Time {
+	return v.Truncate(t).Add(v.Duration() - time.Millisecond)
+}
+
+// intervalDuration converts kline intervals like "15m", "4h", "1d", "1w".
+func intervalDuration(interval string) (time.Duration, bool) {
+	if len(interval) < 2 {
+		return 0, false
+	}
+
+	n, err := strconv.Atoi(interval[:len(interval)-1])
+	if err != nil || n <= 0 {
+		return 0, false
+	}
+
+	unit := map[byte]time.Duration{
+		'm': time.Minute,
+		'h': time.Hour,
+		'd': 24 * time.Hour,
+		'w': 7 * 24 * time.Hour,
+	}[interval[len(interval)-1]]
+	if unit == 0 {
+		return 0, false
+	}
+
+	return time.Duration(n) * unit, true
+}
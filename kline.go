@@ -0,0 +1,75 @@
+package currencycom
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Kline is one candlestick returned by Klines.
+type Kline struct {
+	OpenTime  time.Time
+	CloseTime time.Time // last millisecond of the bar, zero if the interval is unknown
+	Open      Decimal
+	High      Decimal
+	Low       Decimal
+	Close     Decimal
+	Volume    Decimal
+}
+
+// UnmarshalJSON decodes a kline row [openTime, open, high, low, close,
+// volume]. Every field may be sent as a number or as a string.
+func (k *Kline) UnmarshalJSON(data []byte) error {
+	var row []json.RawMessage
+	if err := json.Unmarshal(data, &row); err != nil {
+		return fmt.Errorf("error in kline %s, %w", data, err)
+	}
+
+	if len(row) < 6 {
+		return fmt.Errorf("error in kline %s, need 6 fields, got %d", data, len(row))
+	}
+
+	openTime, err := parseJSONNumber(row[0])
+	if err != nil {
+		return fmt.Errorf("error in kline %s, field 0: %w", data, err)
+	}
+
+	var values [5]Decimal
+	for i := range values {
+		if err := values[i].UnmarshalJSON(row[i+1]); err != nil {
+			return fmt.Errorf("error in kline %s, field %d: %w", data, i+1, err)
+		}
+	}
+
+	*k = Kline{
+		OpenTime: time.UnixMilli(int64(openTime)),
+		Open:     values[0],
+		High:     values[1],
+		Low:      values[2],
+		Close:    values[3],
+		Volume:   values[4],
+	}
+
+	return nil
+}
+
+// MarshalJSON encodes the kline as a row in the format of the exchange.
+func (k Kline) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]interface{}{k.OpenTime.UnixMilli(), k.Open, k.High, k.Low, k.Close, k.Volume})
+}
+
+// parseJSONNumber reads a JSON number or a string with a number in it.
+func parseJSONNumber(raw json.RawMessage) (float64, error) {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) > 0 && raw[0] == '"' {
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return 0, err
+		}
+		raw = []byte(s)
+	}
+
+	return strconv.ParseFloat(string(raw), 64)
+}
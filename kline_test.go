@@ -0,0 +1,126 @@
+package currencycom
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestKlineUnmarshalJSON(t *testing.T) {
+	openTime := time.UnixMilli(1622541600000)
+
+	tests := []struct {
+		name string
+		data string
+		want Kline
+		err  bool
+	}{
+		{
+			name: "numbers",
+			data: `[1622541600000,100.5,101,99.25,100,12.5]`,
+			want: Kline{OpenTime: openTime, Open: MustDecimal("100.5"), High: MustDecimal("101"), Low: MustDecimal("99.25"), Close: MustDecimal("100"), Volume: MustDecimal("12.5")},
+		},
+		{
+			name: "strings",
+			data: `["1622541600000","100.5","101","99.25","100","12.5"]`,
+			want: Kline{OpenTime: openTime, Open: MustDecimal("100.5"), High: MustDecimal("101"), Low: MustDecimal("99.25"), Close: MustDecimal("100"), Volume: MustDecimal("12.5")},
+		},
+		{
+			name: "mixed with extra fields",
+			data: `[1622541600000, "0.00001234", 0.00001240, "0.00001230", 0.0000124, 1e3, 42]`,
+			want: Kline{OpenTime: openTime, Open: MustDecimal("0.00001234"), High: MustDecimal("0.0000124"), Low: MustDecimal("0.0000123"), Close: MustDecimal("0.0000124"), Volume: MustDecimal("1000")},
+		},
+		{name: "short row", data: `[1622541600000,100.5,101,99.25,100]`, err: true},
+		{name: "empty row", data: `[]`, err: true},
+		{name: "not a row", data: `{"t":1622541600000}`, err: true},
+		{name: "malformed time", data: `["yesterday",100.5,101,99.25,100,12.5]`, err: true},
+		{name: "malformed price", data: `[1622541600000,"abc",101,99.25,100,12.5]`, err: true},
+		{name: "bool volume", data: `[1622541600000,100.5,101,99.25,100,true]`, err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var k Kline
+			err := json.Unmarshal([]byte(tt.data), &k)
+			if tt.err {
+				if err == nil {
+					t.Errorf("decoded %+v, want error", k)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !k.OpenTime.Equal(tt.want.OpenTime) || !k.Open.Equal(tt.want.Open) || !k.High.Equal(tt.want.High) ||
+				!k.Low.Equal(tt.want.Low) || !k.Close.Equal(tt.want.Close) || !k.Volume.Equal(tt.want.Volume) {
+				t.Errorf("decoded %+v, want %+v", k, tt.want)
+			}
+		})
+	}
+}
+
+func TestKlineMarshalJSON(t *testing.T) {
+	k := Kline{
+		OpenTime:  time.UnixMilli(1622541600000),
+		CloseTime: time.UnixMilli(1622541659999),
+		Open:      MustDecimal("100.5"),
+		High:      MustDecimal("101"),
+		Low:       MustDecimal("99.25"),
+		Close:     MustDecimal("100"),
+		Volume:    MustDecimal("12.5"),
+	}
+
+	data, err := json.Marshal(k)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `[1622541600000,"100.5","101","99.25","100","12.5"]`; string(data) != want {
+		t.Errorf("marshalled %s, want %s", data, want)
+	}
+
+	var back Kline
+	if err := json.Unmarshal(data, &back); err != nil {
+		t.Fatal(err)
+	}
+	if !back.OpenTime.Equal(k.OpenTime) || !back.Close.Equal(k.Close) || !back.Volume.Equal(k.Volume) {
+		t.Errorf("round trip %+v, want %+v", back, k)
+	}
+}
+
+func TestParseJSONNumber(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want float64
+		err  bool
+	}{
+		{raw: `42`, want: 42},
+		{raw: ` 1.5 `, want: 1.5},
+		{raw: `"1622541600000"`, want: 1622541600000},
+		{raw: `"-0.25"`, want: -0.25},
+		{raw: `1e3`, want: 1000},
+		{raw: `""`, err: true},
+		{raw: `"abc"`, err: true},
+		{raw: `"unterminated`, err: true},
+		{raw: `null`, err: true},
+		{raw: ``, err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			got, err := parseJSONNumber(json.RawMessage(tt.raw))
+			if tt.err {
+				if err == nil {
+					t.Errorf("parseJSONNumber(%s) = %v, want error", tt.raw, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("parseJSONNumber(%s) = %v, want %v", tt.raw, got, tt.want)
+			}
+		})
+	}
+}
@@ -5,93 +5,252 @@ type RejectReasonEnum string
 //Enum:
 //[ ACCOUNT_NOT_FOUND, CLOSED_MARKET, CLOSE_ONLY, ENGINE_BUSY, HEDGING_MODE_GSL, INSTRUMENT_NOT_AVAILABLE, INSTRUMENT_NOT_FOUND, INVALID_ORDER, INVALID_ORDER_QTY, INVALID_PRICE, LONG_ONLY, OFF_MARKET, ORDER_NOT_FOUND, ORIGINAL_GSL_UPDATE, POSITION_NOT_FOUND, RC_INSTRUMENT_CLIENT_MOP, RC_INSTRUMENT_GLOBAL_MOP, RC_NOT_ENOUGH_MARGIN, RC_NOT_FOUND, RC_NO_RATES, RC_SETTLEMENT, RC_UNKNOWN, REQUIRED_GSL, RISK_CHECK, THROTTLING, UNKNOWN ]
 
+const (
+	RejectReasonAccountNotFound        RejectReasonEnum = "ACCOUNT_NOT_FOUND"
+	RejectReasonClosedMarket           RejectReasonEnum = "CLOSED_MARKET"
+	RejectReasonCloseOnly              RejectReasonEnum = "CLOSE_ONLY"
+	RejectReasonEngineBusy             RejectReasonEnum = "ENGINE_BUSY"
+	RejectReasonHedgingModeGSL         RejectReasonEnum = "HEDGING_MODE_GSL"
+	RejectReasonInstrumentNotAvailable RejectReasonEnum = "INSTRUMENT_NOT_AVAILABLE"
+	RejectReasonInstrumentNotFound     RejectReasonEnum = "INSTRUMENT_NOT_FOUND"
+	RejectReasonInvalidOrder           RejectReasonEnum = "INVALID_ORDER"
+	RejectReasonInvalidOrderQty        RejectReasonEnum = "INVALID_ORDER_QTY"
+	RejectReasonInvalidPrice           RejectReasonEnum = "INVALID_PRICE"
+	RejectReasonLongOnly               RejectReasonEnum = "LONG_ONLY"
+	RejectReasonOffMarket              RejectReasonEnum = "OFF_MARKET"
+	RejectReasonOrderNotFound          RejectReasonEnum = "ORDER_NOT_FOUND"
+	RejectReasonOriginalGSLUpdate      RejectReasonEnum = "ORIGINAL_GSL_UPDATE"
+	RejectReasonPositionNotFound       RejectReasonEnum = "POSITION_NOT_FOUND"
+	RejectReasonRCInstrumentClientMOP  RejectReasonEnum = "RC_INSTRUMENT_CLIENT_MOP"
+	RejectReasonRCInstrumentGlobalMOP  RejectReasonEnum = "RC_INSTRUMENT_GLOBAL_MOP"
+	RejectReasonRCNotEnoughMargin      RejectReasonEnum = "RC_NOT_ENOUGH_MARGIN"
+	RejectReasonRCNotFound             RejectReasonEnum = "RC_NOT_FOUND"
+	RejectReasonRCNoRates              RejectReasonEnum = "RC_NO_RATES"
+	RejectReasonRCSettlement           RejectReasonEnum = "RC_SETTLEMENT"
+	RejectReasonRCUnknown              RejectReasonEnum = "RC_UNKNOWN"
+	RejectReasonRequiredGSL            RejectReasonEnum = "REQUIRED_GSL"
+	RejectReasonRiskCheck              RejectReasonEnum = "RISK_CHECK"
+	RejectReasonThrottling             RejectReasonEnum = "THROTTLING"
+	RejectReasonUnknown                RejectReasonEnum = "UNKNOWN"
+)
+
 type DtoType string
 
 //Enum:
 //[ ORDER_CANCEL, ORDER_MODIFY, ORDER_NEW, POSITION_MODIFY ]
 
+const (
+	DtoTypeOrderCancel    DtoType = "ORDER_CANCEL"
+	DtoTypeOrderModify    DtoType = "ORDER_MODIFY"
+	DtoTypeOrderNew       DtoType = "ORDER_NEW"
+	DtoTypePositionModify DtoType = "POSITION_MODIFY"
+)
+
 type DtoState string
 
 //Enum:
 //[ CANCELLED, PENDING, PROCESSED ]
 
+const (
+	DtoStateCancelled DtoState = "CANCELLED"
+	DtoStatePending   DtoState = "PENDING"
+	DtoStateProcessed DtoState = "PROCESSED"
+)
+
 type OrderSide string
 
 // Enum:
 // [ BUY, SELL ]
 
+const (
+	OrderSideBuy  OrderSide = "BUY"
+	OrderSideSell OrderSide = "SELL"
+)
+
 type OrderStatus string
 
 // Enum:
 // [ CANCELED, EXPIRED, FILLED, NEW, PARTIALLY_FILLED, PENDING_CANCEL, REJECTED ]
 
+const (
+	OrderStatusCanceled        OrderStatus = "CANCELED"
+	OrderStatusExpired         OrderStatus = "EXPIRED"
+	OrderStatusFilled          OrderStatus = "FILLED"
+	OrderStatusNew             OrderStatus = "NEW"
+	OrderStatusPartiallyFilled OrderStatus = "PARTIALLY_FILLED"
+	OrderStatusPendingCancel   OrderStatus = "PENDING_CANCEL"
+	OrderStatusRejected        OrderStatus = "REJECTED"
+)
+
 type OrderTimeInForce string
 
 //Enum:
 //[ FOK, GTC, IOC ]
 
+const (
+	TimeInForceFOK OrderTimeInForce = "FOK"
+	TimeInForceGTC OrderTimeInForce = "GTC"
+	TimeInForceIOC OrderTimeInForce = "IOC"
+)
+
 type OrderType string
 
 //Enum:
 //[ LIMIT, LIMIT_MAKER, MARKET, STOP, STOP_LOSS, STOP_LOSS_LIMIT, TAKE_PROFIT, TAKE_PROFIT_LIMIT ]
 
+const (
+	OrderTypeLimit           OrderType = "LIMIT"
+	OrderTypeLimitMaker      OrderType = "LIMIT_MAKER"
+	OrderTypeMarket          OrderType = "MARKET"
+	OrderTypeStop            OrderType = "STOP"
+	OrderTypeStopLoss        OrderType = "STOP_LOSS"
+	OrderTypeStopLossLimit   OrderType = "STOP_LOSS_LIMIT"
+	OrderTypeTakeProfit      OrderType = "TAKE_PROFIT"
+	OrderTypeTakeProfitLimit OrderType = "TAKE_PROFIT_LIMIT"
+)
+
 type CurrencyType string
 
 //_Enum:
 //[ CRYPTO, EXCHANGE_TOKEN, FIAT, ICO, TOKEN, TOKENISED_SECURITY, UTILITY_TOKENS ]
 
+const (
+	CurrencyTypeCrypto            CurrencyType = "CRYPTO"
+	CurrencyTypeExchangeToken     CurrencyType = "EXCHANGE_TOKEN"
+	CurrencyTypeFiat              CurrencyType = "FIAT"
+	CurrencyTypeICO               CurrencyType = "ICO"
+	CurrencyTypeToken             CurrencyType = "TOKEN"
+	CurrencyTypeTokenisedSecurity CurrencyType = "TOKENISED_SECURITY"
+	CurrencyTypeUtilityTokens     CurrencyType = "UTILITY_TOKENS"
+)
+
 type AssetType string
 
 //Enum:
 //[ BOND, COMMODITY, CREDIT, CRYPTOCURRENCY, CURRENCY, EQUITY, ICO, INDEX, INTEREST_RATE, OTHER_ASSET, REAL_ESTATE, UTILITY_TOKENS ]
 
+const (
+	AssetTypeBond           AssetType = "BOND"
+	AssetTypeCommodity      AssetType = "COMMODITY"
+	AssetTypeCredit         AssetType = "CREDIT"
+	AssetTypeCryptocurrency AssetType = "CRYPTOCURRENCY"
+	AssetTypeCurrency       AssetType = "CURRENCY"
+	AssetTypeEquity         AssetType = "EQUITY"
+	AssetTypeICO            AssetType = "ICO"
+	AssetTypeIndex          AssetType = "INDEX"
+	AssetTypeInterestRate   AssetType = "INTEREST_RATE"
+	AssetTypeOtherAsset     AssetType = "OTHER_ASSET"
+	AssetTypeRealEstate     AssetType = "REAL_ESTATE"
+	AssetTypeUtilityTokens  AssetType = "UTILITY_TOKENS"
+)
+
 type MarketModes string
 
 //Enum:
 //[ CLOSED_FOR_CORPORATE_ACTION, CLOSE_ONLY, HOLIDAY, LONG_ONLY, REGULAR, UNKNOWN, VIEW_AND_REQUEST, VIEW_ONLY ]
 
+const (
+	MarketModeClosedForCorporateAction MarketModes = "CLOSED_FOR_CORPORATE_ACTION"
+	MarketModeCloseOnly                MarketModes = "CLOSE_ONLY"
+	MarketModeHoliday                  MarketModes = "HOLIDAY"
+	MarketModeLongOnly                 MarketModes = "LONG_ONLY"
+	MarketModeRegular                  MarketModes = "REGULAR"
+	MarketModeUnknown                  MarketModes = "UNKNOWN"
+	MarketModeViewAndRequest           MarketModes = "VIEW_AND_REQUEST"
+	MarketModeViewOnly                 MarketModes = "VIEW_ONLY"
+)
+
 type MarketType string
 
 //Enum:
 //[ LEVERAGE, SPOT ]
 
+const (
+	MarketTypeLeverage MarketType = "LEVERAGE"
+	MarketTypeSpot     MarketType = "SPOT"
+)
+
 type ExchangeStatus string
 
 //Enum:
 //[ AUCTION_MATCH, BREAK, END_OF_DAY, HALT, POST_TRADING, PRE_TRADING, TRADING ]
 
+const (
+	ExchangeStatusAuctionMatch ExchangeStatus = "AUCTION_MATCH"
+	ExchangeStatusBreak        ExchangeStatus = "BREAK"
+	ExchangeStatusEndOfDay     ExchangeStatus = "END_OF_DAY"
+	ExchangeStatusHalt         ExchangeStatus = "HALT"
+	ExchangeStatusPostTrading  ExchangeStatus = "POST_TRADING"
+	ExchangeStatusPreTrading   ExchangeStatus = "PRE_TRADING"
+	ExchangeStatusTrading      ExchangeStatus = "TRADING"
+)
+
 type PositionDtoState string
 
 //Enum:
 //[ ACTIVE, INACTIVE, INVALID ]
 
+const (
+	PositionDtoStateActive   PositionDtoState = "ACTIVE"
+	PositionDtoStateInactive PositionDtoState = "INACTIVE"
+	PositionDtoStateInvalid  PositionDtoState = "INVALID"
+)
+
 type PositionDtoType string
 
 //Enum:
 //[ HEDGE, NET ]
 
+const (
+	PositionDtoTypeHedge PositionDtoType = "HEDGE"
+	PositionDtoTypeNet   PositionDtoType = "NET"
+)
+
 type ExchangeType string
 
 //Enum:
 //[ GTC, IOC ]
 
+const (
+	ExchangeTypeGTC ExchangeType = "GTC"
+	ExchangeTypeIOC ExchangeType = "IOC"
+)
+
 type ReportSource string
 
 //Enum:
 //[ CLOSE_OUT, DEALER, SL, SYSTEM, TP, USER ]
 
+const (
+	ReportSourceCloseOut ReportSource = "CLOSE_OUT"
+	ReportSourceDealer   ReportSource = "DEALER"
+	ReportSourceSL       ReportSource = "SL"
+	ReportSourceSystem   ReportSource = "SYSTEM"
+	ReportSourceTP       ReportSource = "TP"
+	ReportSourceUser     ReportSource = "USER"
+)
+
 type ReportStatus string
 
 //Enum:
 //[ CLOSED, DIVIDEND, MODIFIED, MODIFY_REJECT, OPENED, SWAP ]
 
+const (
+	ReportStatusClosed       ReportStatus = "CLOSED"
+	ReportStatusDividend     ReportStatus = "DIVIDEND"
+	ReportStatusModified     ReportStatus = "MODIFIED"
+	ReportStatusModifyReject ReportStatus = "MODIFY_REJECT"
+	ReportStatusOpened       ReportStatus = "OPENED"
+	ReportStatusSwap         ReportStatus = "SWAP"
+)
+
 type AccountBalance struct {
 	AccountId          string  `json:"accountId"`
 	Asset              string  `json:"asset"`
 	CollateralCurrency bool    `json:"collateralCurrency"`
 	Default            bool    `json:"default"`
-	Free               float64 `json:"free"`
-	Locked             float64 `json:"locked"`
+	Free               Decimal `json:"free"`
+	Locked             Decimal `json:"locked"`
 }
 
 type AccountRequest struct {
@@ -102,30 +261,30 @@ type AccountRequest struct {
 type AccountResponse struct {
 	AffiliateId      string           `json:"affiliateId"`
 	Balances         []AccountBalance `json:"balances"`
-	BuyerCommission  float64          `json:"buyerCommission"`
+	BuyerCommission  Decimal          `json:"buyerCommission"`
 	CanDeposit       bool             `json:"canDeposit"`
 	CanTrade         bool             `json:"canTrade"`
 	CanWithdraw      bool             `json:"canWithdraw"`
-	MakerCommission  float64          `json:"makerCommission"`
-	SellerCommission float64          `json:"sellerCommission"`
-	TakerCommission  float64          `json:"takerCommission"`
+	MakerCommission  Decimal          `json:"makerCommission"`
+	SellerCommission Decimal          `json:"sellerCommission"`
+	TakerCommission  Decimal          `json:"takerCommission"`
 	UpdateTime       int64            `json:"updateTime"`
 	UserId           int64            `json:"userId"`
 }
 
 type AggTrades struct {
-	Timestamp int64  `json:"T"`
-	Aggregate int64  `json:"a"`
-	IsMaker   bool   `json:"m"`
-	Price     string `json:"p"`
-	Quantity  string `json:"q"`
+	Timestamp int64   `json:"T"`
+	Aggregate int64   `json:"a"`
+	IsMaker   bool    `json:"m"`
+	Price     Decimal `json:"p"`
+	Quantity  Decimal `json:"q"`
 }
 
 type AggTradesRequest struct {
 	EndTime   int64
 	Limit     int32
 	StartTime int64
-	Symbol    string //*
+	Symbol    Symbol //*
 }
 
 type AggTradesResponse struct {
@@ -137,7 +296,7 @@ type AllMyTradesRequest struct {
 	Limit      int32
 	RecvWindow int64 //maximum: 60000, exclusiveMaximum: false
 	StartTime  int64
-	Symbol     string //*
+	Symbol     Symbol //*
 }
 
 type AllMyTradesResponse struct {
@@ -156,25 +315,25 @@ type BlockchainAddressRequest struct {
 }
 
 type BySymbolRequest struct {
-	Symbol string
+	Symbol Symbol
 }
 
 type CancelOrderRequest struct {
 	OrderId    string //*
 	RecvWindow int64  //maximum: 60000, exclusiveMaximum: false
-	Symbol     string //*
+	Symbol     Symbol //*
 }
 
 type CancelOrderResponse struct {
-	ExecutedQty string `json:"executedQty"`
-	OrderId     string `json:"orderId"`
-	OrigQty     string `json:"origQty"`
-	Price       string `json:"price"`
-	Side        string `json:"side"`   //OrderSide
-	Status      string `json:"status"` //OrderStatus
-	Symbol      string `json:"symbol"`
-	TimeInForce string `json:"timeInForce"` //OrderTimeInForce
-	Type        string `json:"type"`        //OrderType
+	ExecutedQty Decimal          `json:"executedQty"`
+	OrderId     string           `json:"orderId"`
+	OrigQty     Decimal          `json:"origQty"`
+	Price       Decimal          `json:"price"`
+	Side        OrderSide        `json:"side"`
+	Status      OrderStatus      `json:"status"`
+	Symbol      string           `json:"symbol"`
+	TimeInForce OrderTimeInForce `json:"timeInForce"`
+	Type        OrderType        `json:"type"`
 }
 
 type CloseTradingPositionRequest struct {
@@ -188,27 +347,27 @@ type CreateOrderRequest struct {
 	GuaranteedStopLoss bool
 	Leverage           int32
 	NewOrderRespType   string
-	Price              float64
-	Quantity           float64 //*
-	RecvWindow         int64   //maximum: 60000, exclusiveMaximum: false
-	Side               string  //*
-	StopLoss           float64
-	Symbol             string //*
-	TakeProfit         float64
-	Type               string //*
+	Price              Decimal
+	Quantity           Decimal   //*
+	RecvWindow         int64     //maximum: 60000, exclusiveMaximum: false
+	Side               OrderSide //*
+	StopLoss           Decimal
+	Symbol             Symbol //*
+	TakeProfit         Decimal
+	Type               OrderType //*
 }
 
 type CurrencyDtoResponse struct {
-	CommissionFixed   float64 `json:"commissionFixed"`
-	CommissionMin     float64 `json:"commissionMin"`
-	CommissionPercent float64 `json:"commissionPercent"`
-	DisplaySymbol     string  `json:"displaySymbol"`
-	MaxWithdrawal     float64 `json:"maxWithdrawal"`
-	MinDeposit        float64 `json:"minDeposit"`
-	MinWithdrawal     float64 `json:"minWithdrawal"`
-	Name              string  `json:"name"`
-	Precision         int32   `json:"precision"`
-	Type              string  `json:"type"` //CurrencyType
+	CommissionFixed   Decimal      `json:"commissionFixed"`
+	CommissionMin     Decimal      `json:"commissionMin"`
+	CommissionPercent Decimal      `json:"commissionPercent"`
+	DisplaySymbol     string       `json:"displaySymbol"`
+	MaxWithdrawal     Decimal      `json:"maxWithdrawal"`
+	MinDeposit        Decimal      `json:"minDeposit"`
+	MinWithdrawal     Decimal      `json:"minWithdrawal"`
+	Name              string       `json:"name"`
+	Precision         int32        `json:"precision"`
+	Type              CurrencyType `json:"type"`
 }
 
 type CurrencyResponse struct {
@@ -217,12 +376,12 @@ type CurrencyResponse struct {
 
 type DepthRequest struct {
 	Limit  int32
-	Symbol string //*
+	Symbol Symbol //*
 }
 
 type DepthResponse struct {
-	Asks         [][]float64 `json:"asks"`
-	Bids         [][]float64 `json:"bids"`
+	Asks         [][]Decimal `json:"asks"` // [price, quantity]
+	Bids         [][]Decimal `json:"bids"` // [price, quantity]
 	LastUpdateId int64       `json:"lastUpdateId"`
 }
 
@@ -238,72 +397,67 @@ type ExchangeInfoResponse struct {
 }
 
 type ExchangeSymbolInfo struct {
-	AssetType          string         `json:"assetType"` // AssetType
+	AssetType          AssetType      `json:"assetType"`
 	BaseAsset          string         `json:"baseAsset"`
 	BaseAssetPrecision int32          `json:"baseAssetPrecision"`
 	Country            string         `json:"country"`
-	ExchangeFee        float64        `json:"exchangeFee"`
+	ExchangeFee        Decimal        `json:"exchangeFee"`
 	Filters            []SymbolFilter `json:"filters"`
 	Industry           string         `json:"industry"`
-	LongRate           float64        `json:"longRate"`
-	MakerFee           float64        `json:"makerFee"`
-	MarketModes        []string       `json:"marketModes"` //MarketModes
-	MarketType         string         `json:"marketType"`  //MarketType
-	MaxSLGap           float64        `json:"maxSLGap"`
-	MaxTPGap           float64        `json:"maxTPGap"`
-	MinSLGap           float64        `json:"minSLGap"`
-	MinTPGap           float64        `json:"minTPGap"`
+	LongRate           Decimal        `json:"longRate"`
+	MakerFee           Decimal        `json:"makerFee"`
+	MarketModes        []MarketModes  `json:"marketModes"`
+	MarketType         MarketType     `json:"marketType"`
+	MaxSLGap           Decimal        `json:"maxSLGap"`
+	MaxTPGap           Decimal        `json:"maxTPGap"`
+	MinSLGap           Decimal        `json:"minSLGap"`
+	MinTPGap           Decimal        `json:"minTPGap"`
 	Name               string         `json:"name"`
-	OrderTypes         []string       `json:"orderTypes"` //OrderType
+	OrderTypes         []OrderType    `json:"orderTypes"`
 	QuoteAsset         string         `json:"quoteAsset"`
 	QuoteAssetId       string         `json:"quoteAssetId"`
 	QuotePrecision     int32          `json:"quotePrecision"`
 	Sector             string         `json:"sector"`
-	ShortRate          float64        `json:"shortRate"`
-	Status             string         `json:"status"` //ExchangeStatus
+	ShortRate          Decimal        `json:"shortRate"`
+	Status             ExchangeStatus `json:"status"`
 	SwapChargeInterval int64          `json:"swapChargeInterval"`
 	Symbol             string         `json:"symbol"`
-	TakerFee           float64        `json:"takerFee"`
-	TickSize           float64        `json:"tickSize"`
-	TickValue          float64        `json:"tickValue"`
-	TradingFee         float64        `json:"tradingFee"`
+	TakerFee           Decimal        `json:"takerFee"`
+	TickSize           Decimal        `json:"tickSize"`
+	TickValue          Decimal        `json:"tickValue"`
+	TradingFee         Decimal        `json:"tradingFee"`
 	TradingHours       string         `json:"tradingHours"`
+
+	// precisions present in the decoded JSON, 0 is a valid precision
+	baseAssetPrecisionSet bool
+	quotePrecisionSet     bool
 }
 
 type InternalQuote struct {
-	bid        float64
-	bidQty     float64
-	ofr        float64
-	ofrQty     float64
-	symbolName string
-	timestamp  int64
+	Bid        Decimal `json:"bid"`
+	BidQty     Decimal `json:"bidQty"`
+	Ofr        Decimal `json:"ofr"`
+	OfrQty     Decimal `json:"ofrQty"`
+	SymbolName string  `json:"symbolName"`
+	Timestamp  int64   `json:"timestamp"`
 }
 
 type KLinesRequest struct {
 	EndTime   int64
-	Interval  string //*
+	Interval  Interval //*
 	Limit     int32
 	StartTime int64
-	Symbol    string //*
-	Type      string
+	Symbol    Symbol //*
+	Type      CandleType
 }
 
 type KLinesResponse struct {
-	Lines [][6]interface{}
-}
-
-type KLinesResponseStruct struct {
-	OpenTime float64
-	Open     string
-	High     string
-	Low      string
-	Close    string
-	Volume   float64
+	Lines []Kline
 }
 
 type LeverageSettingsRequest struct {
 	RecvWindow int64  //maximum: 60000, exclusiveMaximum: false
-	Symbol     string //*
+	Symbol     Symbol //*
 }
 
 type LeverageSettingsResponse struct {
@@ -312,65 +466,65 @@ type LeverageSettingsResponse struct {
 }
 
 type MarketDepthData struct {
-	bid map[string]float64
-	ofr map[string]float64
-	ts  int64
+	Bid map[string]Decimal `json:"bid"` // price: quantity
+	Ofr map[string]Decimal `json:"ofr"` // price: quantity
+	Ts  int64              `json:"ts"`
 }
 
 type MarketDepthEvent struct {
-	data   MarketDepthData
-	symbol string
+	Data   MarketDepthData `json:"data"`
+	Symbol string          `json:"symbol"`
 }
 
 type MyTradesResponse struct {
-	Buyer           bool   `json:"buyer"`
-	Commission      string `json:"commission"`
-	CommissionAsset string `json:"commissionAsset"`
-	Id              string `json:"id"`
-	IsBuyer         bool   `json:"isBuyer"`
-	IsMaker         bool   `json:"isMaker"`
-	Maker           bool   `json:"maker"`
-	OrderId         string `json:"orderId"`
-	Price           string `json:"price"`
-	Qty             string `json:"qty"`
-	QuoteQty        string `json:"quoteQty"`
-	Symbol          string `json:"symbol"`
-	Time            int64  `json:"time"`
+	Buyer           bool    `json:"buyer"`
+	Commission      Decimal `json:"commission"`
+	CommissionAsset string  `json:"commissionAsset"`
+	Id              string  `json:"id"`
+	IsBuyer         bool    `json:"isBuyer"`
+	IsMaker         bool    `json:"isMaker"`
+	Maker           bool    `json:"maker"`
+	OrderId         string  `json:"orderId"`
+	Price           Decimal `json:"price"`
+	Qty             Decimal `json:"qty"`
+	QuoteQty        Decimal `json:"quoteQty"`
+	Symbol          string  `json:"symbol"`
+	Time            int64   `json:"time"`
 }
 
 type NewOrderResponseRESULT struct {
-	ExecutedQty        string  `json:"executedQty"`
-	ExpireTimestamp    int64   `json:"expireTimestamp"`
-	GuaranteedStopLoss bool    `json:"guaranteedStopLoss"`
-	Margin             float64 `json:"margin"`
-	OrderId            string  `json:"orderId"`
-	OrigQty            string  `json:"origQty"`
-	Price              string  `json:"price"`
-	RejectMessage      string  `json:"rejectMessage"`
-	Side               string  `json:"side"` //OrderSide
-	StopLoss           float64 `json:"stopLoss"`
-	Symbol             string  `json:"symbol"`
-	TakeProfit         float64 `json:"takeProfit"`
-	TimeInForce        string  `json:"timeInForce"` //OrderTimeInForce
-	TransactTime       int64   `json:"transactTime"`
-	Type               string  `json:"type"` //OrderType
+	ExecutedQty        Decimal          `json:"executedQty"`
+	ExpireTimestamp    int64            `json:"expireTimestamp"`
+	GuaranteedStopLoss bool             `json:"guaranteedStopLoss"`
+	Margin             Decimal          `json:"margin"`
+	OrderId            string           `json:"orderId"`
+	OrigQty            Decimal          `json:"origQty"`
+	Price              Decimal          `json:"price"`
+	RejectMessage      string           `json:"rejectMessage"`
+	Side               OrderSide        `json:"side"`
+	StopLoss           Decimal          `json:"stopLoss"`
+	Symbol             string           `json:"symbol"`
+	TakeProfit         Decimal          `json:"takeProfit"`
+	TimeInForce        OrderTimeInForce `json:"timeInForce"`
+	TransactTime       int64            `json:"transactTime"`
+	Type               OrderType        `json:"type"`
 }
 
 type OHLCBar struct {
-	c        float64
-	h        float64
-	interval string
-	l        float64
-	o        float64
-	symbol   string
-	t        int64
-	_type    string
+	C        Decimal `json:"c"`
+	H        Decimal `json:"h"`
+	Interval string  `json:"interval"`
+	L        Decimal `json:"l"`
+	O        Decimal `json:"o"`
+	Symbol   string  `json:"symbol"`
+	T        int64   `json:"t"`
+	Type     string  `json:"type"`
 }
 
 type OHLCSubscribeRequest struct {
-	intervals []string //Identifies intervals for subscription. Available: 1m, 5m, 15m, 30m, 1h, 4h, 1d, 1w. Default: 1m.
-	symbols   []string //Identifies symbols for subscription.
-	_type     string   //Type of candlestick. Available: classic, heikin-ashi.
+	Intervals []Interval `json:"intervals,omitempty"` //Identifies intervals for subscription. Available: 1m, 5m, 15m, 30m, 1h, 4h, 1d, 1w. Default: 1m.
+	Symbols   []Symbol   `json:"symbols"`             //Identifies symbols for subscription.
+	Type      CandleType `json:"type,omitempty"`      //Type of candlestick. Available: classic, heikin-ashi.
 }
 
 type OpenOrdersReponse struct {
@@ -381,34 +535,34 @@ type PingRequest struct{}
 type PingResponse struct{}
 
 type PositionDto struct {
-	AccountId          string  `json:"accountId"`     //*
-	ClosePrice         float64 `json:"closePrice"`    //*
-	CloseQuantity      float64 `json:"closeQuantity"` //*
-	CloseTimestamp     int64   `json:"closeTimestamp"`
-	Cost               float64 `json:"cost"`
-	CreatedTimestamp   int64   `json:"createdTimestamp"` //*
-	Currency           string  `json:"currency"`         //*
-	Dividend           float64 `json:"dividend"`
-	Fee                float64 `json:"fee"`
-	GuaranteedStopLoss bool    `json:"guaranteedStopLoss"`
-	Id                 string  `json:"id"`            //* uuid
-	InstrumentId       int64   `json:"instrumentId"`  //*
-	Margin             float64 `json:"margin"`        //*
-	OpenPrice          float64 `json:"openPrice"`     //*
-	OpenQuantity       float64 `json:"openQuantity"`  //*
-	OpenTimestamp      int64   `json:"openTimestamp"` //*
-	OrderId            string  `json:"orderId"`       //* uuid
-	Rpl                float64 `json:"rpl"`
-	RplConverted       float64 `json:"rplConverted"`
-	State              string  `json:"state"` //* PositionDtoState
-	StopLoss           float64 `json:"stopLoss"`
-	Swap               float64 `json:"swap"`
-	SwapConverted      float64 `json:"swapConverted"`
-	Symbol             string  `json:"symbol"`
-	TakeProfit         float64 `json:"takeProfit"`
-	Type               string  `json:"type"` //PositionDtoType
-	Upl                float64 `json:"upl"`
-	UplConverted       float64 `json:"uplConverted"`
+	AccountId          string           `json:"accountId"`     //*
+	ClosePrice         Decimal          `json:"closePrice"`    //*
+	CloseQuantity      Decimal          `json:"closeQuantity"` //*
+	CloseTimestamp     int64            `json:"closeTimestamp"`
+	Cost               Decimal          `json:"cost"`
+	CreatedTimestamp   int64            `json:"createdTimestamp"` //*
+	Currency           string           `json:"currency"`         //*
+	Dividend           Decimal          `json:"dividend"`
+	Fee                Decimal          `json:"fee"`
+	GuaranteedStopLoss bool             `json:"guaranteedStopLoss"`
+	Id                 string           `json:"id"`            //* uuid
+	InstrumentId       int64            `json:"instrumentId"`  //*
+	Margin             Decimal          `json:"margin"`        //*
+	OpenPrice          Decimal          `json:"openPrice"`     //*
+	OpenQuantity       Decimal          `json:"openQuantity"`  //*
+	OpenTimestamp      int64            `json:"openTimestamp"` //*
+	OrderId            string           `json:"orderId"`       //* uuid
+	Rpl                Decimal          `json:"rpl"`
+	RplConverted       Decimal          `json:"rplConverted"`
+	State              PositionDtoState `json:"state"` //*
+	StopLoss           Decimal          `json:"stopLoss"`
+	Swap               Decimal          `json:"swap"`
+	SwapConverted      Decimal          `json:"swapConverted"`
+	Symbol             string           `json:"symbol"`
+	TakeProfit         Decimal          `json:"takeProfit"`
+	Type               PositionDtoType  `json:"type"`
+	Upl                Decimal          `json:"upl"`
+	UplConverted       Decimal          `json:"uplConverted"`
 }
 
 type PositionExecutionReportDto struct {
@@ -417,54 +571,54 @@ type PositionExecutionReportDto struct {
 	CreatedTimestamp int64              `json:"createdTimestamp"` //*
 	Currency         string             `json:"currency"`         //*
 	ExecTimestamp    int64              `json:"execTimestamp"`    //*
-	ExecutionType    string             `json:"executionType"`    //ExchangeType
-	Fee              float64            `json:"fee"`
-	FeeDetails       map[string]float64 `json:"feeDetails"`
-	FxRate           float64            `json:"fxRate"`
+	ExecutionType    ExchangeType       `json:"executionType"`
+	Fee              Decimal            `json:"fee"`
+	FeeDetails       map[string]Decimal `json:"feeDetails"`
+	FxRate           Decimal            `json:"fxRate"`
 	GSL              bool               `json:"gSL"`
 	InstrumentId     int64              `json:"instrumentId"` //*
 	PositionId       string             `json:"positionId"`   //*
-	Price            float64            `json:"price"`
-	Quantity         float64            `json:"quantity"`
-	RejectReason     string             `json:"rejectReason"` //RejectReasonEnum
-	Rpl              float64            `json:"rpl"`
-	RplConverted     float64            `json:"rplConverted"`
-	Source           string             `json:"source"` //* ReportSource
-	Status           string             `json:"status"` //* ReportStatus
-	StopLoss         float64            `json:"stopLoss"`
-	Swap             float64            `json:"swap"`
-	SwapConverted    float64            `json:"swapConverted"`
+	Price            Decimal            `json:"price"`
+	Quantity         Decimal            `json:"quantity"`
+	RejectReason     RejectReasonEnum   `json:"rejectReason"`
+	Rpl              Decimal            `json:"rpl"`
+	RplConverted     Decimal            `json:"rplConverted"`
+	Source           ReportSource       `json:"source"` //*
+	Status           ReportStatus       `json:"status"` //*
+	StopLoss         Decimal            `json:"stopLoss"`
+	Swap             Decimal            `json:"swap"`
+	SwapConverted    Decimal            `json:"swapConverted"`
 	Symbol           string             `json:"symbol"`
-	TakeProfit       float64            `json:"takeProfit"`
+	TakeProfit       Decimal            `json:"takeProfit"`
 }
 
 type PositionHistoryRequest struct {
 	Limit      int32
 	RecvWindow int64 //maximum: 60000, exclusiveMaximum: false
-	Symbol     string
+	Symbol     Symbol
 }
 
 type QueryOrderResponse struct {
-	AccountId          string  `json:"accountId"`
-	ExecutedQty        string  `json:"executedQty"`
-	ExpireTimestamp    int64   `json:"expireTimestamp"`
-	GuaranteedStopLoss bool    `json:"guaranteedStopLoss"`
-	IcebergQty         string  `json:"icebergQty"`
-	Leverage           bool    `json:"leverage"`
-	Margin             float64 `json:"margin"`
-	OrderId            string  `json:"orderId"`
-	OrigQty            string  `json:"origQty"`
-	Price              string  `json:"price"`
-	Side               string  `json:"side"`   //OrderSide
-	Status             string  `json:"status"` //OrderStatus
-	StopLoss           float64 `json:"stopLoss"`
-	Symbol             string  `json:"symbol"`
-	TakeProfit         float64 `json:"takeProfit"`
-	Time               int64   `json:"time"`
-	TimeInForce        string  `json:"timeInForce"` //OrderTimeInForce
-	Type               string  `json:"type"`        //OrderType
-	UpdateTime         int64   `json:"updateTime"`
-	Working            bool    `json:"working"`
+	AccountId          string           `json:"accountId"`
+	ExecutedQty        Decimal          `json:"executedQty"`
+	ExpireTimestamp    int64            `json:"expireTimestamp"`
+	GuaranteedStopLoss bool             `json:"guaranteedStopLoss"`
+	IcebergQty         Decimal          `json:"icebergQty"`
+	Leverage           bool             `json:"leverage"`
+	Margin             Decimal          `json:"margin"`
+	OrderId            string           `json:"orderId"`
+	OrigQty            Decimal          `json:"origQty"`
+	Price              Decimal          `json:"price"`
+	Side               OrderSide        `json:"side"`
+	Status             OrderStatus      `json:"status"`
+	StopLoss           Decimal          `json:"stopLoss"`
+	Symbol             string           `json:"symbol"`
+	TakeProfit         Decimal          `json:"takeProfit"`
+	Time               int64            `json:"time"`
+	TimeInForce        OrderTimeInForce `json:"timeInForce"`
+	Type               OrderType        `json:"type"`
+	UpdateTime         int64            `json:"updateTime"`
+	Working            bool             `json:"working"`
 }
 
 type RateLimits struct {
@@ -475,16 +629,16 @@ type RateLimits struct {
 }
 
 type RequestDto struct {
-	AccountId        string `json:"accountId"`        //*
-	CreatedTimestamp int64  `json:"createdTimestamp"` //*
-	Id               int64  `json:"id"`               //*
-	InstrumentId     int64  `json:"instrumentId"`     //*
-	OrderId          string `json:"orderId"`
-	PositionId       string `json:"positionId"`
-	RejectReason     string `json:"rejectReason"` //RejectReasonEnum
-	RqBody           string `json:"rqBody"`       //*
-	RqType           string `json:"rqType"`       //*
-	State            string `json:"state"`        //*
+	AccountId        string           `json:"accountId"`        //*
+	CreatedTimestamp int64            `json:"createdTimestamp"` //*
+	Id               int64            `json:"id"`               //*
+	InstrumentId     int64            `json:"instrumentId"`     //*
+	OrderId          string           `json:"orderId"`
+	PositionId       string           `json:"positionId"`
+	RejectReason     RejectReasonEnum `json:"rejectReason"`
+	RqBody           string           `json:"rqBody"` //*
+	RqType           DtoType          `json:"rqType"` //*
+	State            DtoState         `json:"state"`  //*
 }
 
 type ServerTimeResponse struct {
@@ -507,15 +661,22 @@ type SignedRequest struct {
 }
 
 type SubscribeRequest struct {
-	symbols []string //Identifies symbols for subscription.
+	Symbols []Symbol `json:"symbols"` //Identifies symbols for subscription.
 }
 
 type SubscribeResponse struct {
-	subscriptions map[string]string
+	Subscriptions map[string]string `json:"subscriptions"` // symbol: status
 }
 
 type SymbolFilter struct {
-	FilterType string `json:"filterType"`
+	FilterType  string  `json:"filterType"`
+	MinPrice    Decimal `json:"minPrice"`    // PRICE_FILTER
+	MaxPrice    Decimal `json:"maxPrice"`    // PRICE_FILTER
+	TickSize    Decimal `json:"tickSize"`    // PRICE_FILTER
+	MinQty      Decimal `json:"minQty"`      // LOT_SIZE
+	MaxQty      Decimal `json:"maxQty"`      // LOT_SIZE
+	StepSize    Decimal `json:"stepSize"`    // LOT_SIZE
+	MinNotional Decimal `json:"minNotional"` // MIN_NOTIONAL
 }
 
 type Ticker24HResponse struct {
@@ -523,46 +684,46 @@ type Ticker24HResponse struct {
 }
 
 type Ticker24hr struct {
-	AskPrice           string `json:"askPrice"`
-	BidPrice           string `json:"bidPrice"`
-	CloseTime          int64  `json:"closeTime"`
-	HighPrice          string `json:"highPrice"`
-	LastPrice          string `json:"lastPrice"`
-	LastQty            string `json:"lastQty"`
-	LowPrice           string `json:"lowPrice"`
-	OpenPrice          string `json:"openPrice"`
-	OpenTime           int64  `json:"openTime"`
-	PrevClosePrice     string `json:"prevClosePrice"`
-	PriceChange        string `json:"priceChange"`
-	PriceChangePercent string `json:"priceChangePercent"`
-	QuoteVolume        string `json:"quoteVolume"`
-	Symbol             string `json:"symbol"`
-	Volume             string `json:"volume"`
-	WeightedAvgPrice   string `json:"weightedAvgPrice"`
+	AskPrice           Decimal `json:"askPrice"`
+	BidPrice           Decimal `json:"bidPrice"`
+	CloseTime          int64   `json:"closeTime"`
+	HighPrice          Decimal `json:"highPrice"`
+	LastPrice          Decimal `json:"lastPrice"`
+	LastQty            Decimal `json:"lastQty"`
+	LowPrice           Decimal `json:"lowPrice"`
+	OpenPrice          Decimal `json:"openPrice"`
+	OpenTime           int64   `json:"openTime"`
+	PrevClosePrice     Decimal `json:"prevClosePrice"`
+	PriceChange        Decimal `json:"priceChange"`
+	PriceChangePercent Decimal `json:"priceChangePercent"`
+	QuoteVolume        Decimal `json:"quoteVolume"`
+	Symbol             string  `json:"symbol"`
+	Volume             Decimal `json:"volume"`
+	WeightedAvgPrice   Decimal `json:"weightedAvgPrice"`
 }
 
 type TradeEventReq struct {
-	id      int32
-	orderId string
-	price   float64
-	size    float64
-	symbol  string
-	ts      int64
+	Id      int64   `json:"id"`
+	OrderId string  `json:"orderId"`
+	Price   Decimal `json:"price"`
+	Size    Decimal `json:"size"`
+	Symbol  string  `json:"symbol"`
+	Ts      int64   `json:"ts"`
 }
 
 type TradeEventRes struct {
-	buyer   bool
-	id      int32
-	orderId string
-	price   float64
-	size    float64
-	symbol  string
-	ts      int64
+	Buyer   bool    `json:"buyer"`
+	Id      int64   `json:"id"`
+	OrderId string  `json:"orderId"`
+	Price   Decimal `json:"price"`
+	Size    Decimal `json:"size"`
+	Symbol  string  `json:"symbol"`
+	Ts      int64   `json:"ts"`
 }
 
 type TradingOrderUpdateResponse struct {
-	RequestId int64  `json:"requestId"` //*
-	State     string `json:"state"`     //* DtoState
+	RequestId int64    `json:"requestId"` //*
+	State     DtoState `json:"state"`     //*
 }
 
 type TradingPositionCloseAllResponse struct {
@@ -578,15 +739,15 @@ type TradingPositionListResponse struct {
 }
 
 type TradingPositionUpdateResponse struct {
-	RequestId int64  `json:"requestId"` //*
-	State     string `json:"state"`     //* DtoState
+	RequestId int64    `json:"requestId"` //*
+	State     DtoState `json:"state"`     //*
 }
 
 type TransactionDTOResponse struct {
-	Amount                    float64 `json:"amount"`
-	Balance                   float64 `json:"balance"`
+	Amount                    Decimal `json:"amount"`
+	Balance                   Decimal `json:"balance"`
 	BlockchainTransactionHash string  `json:"blockchainTransactionHash"`
-	Commission                float64 `json:"commission"`
+	Commission                Decimal `json:"commission"`
 	Currency                  string  `json:"currency"`
 	Id                        int64   `json:"id"`
 	PaymentMethod             string  `json:"paymentMethod"`
@@ -609,17 +770,17 @@ type TransactionsResponse struct {
 type UpdateTradingOrderRequest struct {
 	ExpireTimestamp    int64
 	GuaranteedStopLoss bool
-	NewPrice           float64
+	NewPrice           Decimal
 	OrderId            string //*
 	RecvWindow         int64  //maximum: 60000, exclusiveMaximum: false
-	StopLoss           float64
-	TakeProfit         float64
+	StopLoss           Decimal
+	TakeProfit         Decimal
 }
 
 type UpdateTradingPositionRequest struct {
 	GuaranteedStopLoss bool
 	PositionId         string //*
 	RecvWindow         int64  //maximum: 60000, exclusiveMaximum: false
-	StopLoss           float64
-	TakeProfit         float64
+	StopLoss           Decimal
+	TakeProfit         Decimal
 }
@@ -0,0 +1,40 @@
+package currencycom
+
+import (
+	"net/http"
+	"time"
+)
+
+// Option configures a RestAPI created by NewRestAPI or a Client created by
+// NewClient. Options that need credentials are ignored by Client.
+type Option func(*RestAPI)
+
+// WithHTTPClient makes the client send all requests through the given one.
+// The client is used as is, so its Timeout and Transport are respected.
+func WithHTTPClient(client *http.Client) Option {
+	return func(r *RestAPI) {
+		if client != nil {
+			r.httpClient = client
+		}
+	}
+}
+
+// WithTransport sets the RoundTripper used for requests, e.g. to route
+// through a proxy, customize TLS or plug in a test transport.
+func WithTransport(transport http.RoundTripper) Option {
+	return func(r *RestAPI) {
+		client := *r.httpClient
+		client.Transport = transport
+		r.httpClient = &client
+	}
+}
+
+// WithTimeout sets the overall timeout of a single HTTP request.
+// Zero means no timeout.
+func WithTimeout(timeout time.Duration) Option {
+	return func(r *RestAPI) {
+		client := *r.httpClient
+		client.Timeout = timeout
+		r.httpClient = &client
+	}
+}
@@ -0,0 +1,86 @@
+package currencycom
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestOptions(t *testing.T) {
+	custom := &http.Client{Timeout: time.Minute}
+	transport := &http.Transport{}
+
+	tests := []struct {
+		name      string
+		opts      []Option
+		same      bool // the client is custom itself
+		timeout   time.Duration
+		transport http.RoundTripper
+	}{
+		{name: "defaults", timeout: DEFAULT_TIMEOUT},
+		{name: "http client", opts: []Option{WithHTTPClient(custom)}, same: true, timeout: time.Minute},
+		{name: "nil http client", opts: []Option{WithHTTPClient(nil)}, timeout: DEFAULT_TIMEOUT},
+		{name: "timeout", opts: []Option{WithTimeout(time.Second)}, timeout: time.Second},
+		{name: "no timeout", opts: []Option{WithTimeout(0)}, timeout: 0},
+		{name: "transport", opts: []Option{WithTransport(transport)}, timeout: DEFAULT_TIMEOUT, transport: transport},
+		{name: "timeout of a custom client", opts: []Option{WithHTTPClient(custom), WithTimeout(time.Second)}, timeout: time.Second},
+		{name: "transport of a custom client", opts: []Option{WithHTTPClient(custom), WithTransport(transport)}, timeout: time.Minute, transport: transport},
+		{name: "custom client last wins", opts: []Option{WithTimeout(time.Second), WithHTTPClient(custom)}, same: true, timeout: time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewClient("", tt.opts...)
+
+			if c.Endpoint != DEFAULT_ENDPOINT {
+				t.Errorf("Endpoint = %q, want %q", c.Endpoint, DEFAULT_ENDPOINT)
+			}
+			if (c.httpClient == custom) != tt.same {
+				t.Errorf("client is the custom one: %v, want %v", c.httpClient == custom, tt.same)
+			}
+			if c.httpClient.Timeout != tt.timeout {
+				t.Errorf("Timeout = %v, want %v", c.httpClient.Timeout, tt.timeout)
+			}
+			if c.httpClient.Transport != tt.transport {
+				t.Errorf("Transport = %v, want %v", c.httpClient.Transport, tt.transport)
+			}
+
+			// options change a copy, never the client of the caller
+			if custom.Timeout != time.Minute || custom.Transport != nil {
+				t.Errorf("custom client changed to %+v", custom)
+			}
+		})
+	}
+}
+
+func TestOptionsRequests(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("symbol") == "SLOW" {
+			time.Sleep(200 * time.Millisecond)
+		}
+		_, _ = w.Write([]byte(`{"serverTime":1600000000000}`))
+	}))
+	defer srv.Close()
+
+	target, err := url.Parse(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the endpoint doesn't resolve, the transport reaches the server anyway
+	c := NewClient("http://exchange.invalid", WithTransport(redirectTransport{target: target}), WithRetryPolicy(NoRetry))
+	if out, err := c.ServerTime(); err != nil || out.ServerTime != 1600000000000 {
+		t.Errorf("through the transport: %+v, %v", out, err)
+	}
+
+	c = NewClient(srv.URL, WithTimeout(50*time.Millisecond), WithRetryPolicy(NoRetry))
+	_, err = c.PriceChange(&BySymbolRequest{Symbol: "SLOW"})
+	var netErr net.Error
+	if !errors.As(err, &netErr) || !netErr.Timeout() {
+		t.Errorf("slow response: err = %v, want a timeout", err)
+	}
+}
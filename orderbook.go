@@ -0,0 +1,449 @@
+package currencycom
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ErrBookNotReady is returned by LocalOrderBook queries before the first
+// snapshot is loaded.
+var ErrBookNotReady = errors.New("currencycom: order book is not synced")
+
+// ErrNotEnoughLiquidity is returned by VWAP when the book side is thinner
+// than the requested quantity.
+var ErrNotEnoughLiquidity = errors.New("currencycom: not enough liquidity")
+
+// ErrBookGap is returned by ApplyUpdate when even a fresh snapshot is
+// older than the update, so the updates in between are missing.
+var ErrBookGap = errors.New("currencycom: order book update gap")
+
+type BookSide int
+
+const (
+	BidSide BookSide = iota
+	AskSide
+)
+
+type PriceLevel struct {
+	Price    Decimal
+	Quantity Decimal
+}
+
+// DepthUpdate is an incremental change of the order book. Levels with zero
+// quantity are removed. Updates are applied in the order of update ids: an
+// update older than the book is skipped, and an update whose
+// FirstUpdateId doesn't follow the book means some updates were missed.
+// FirstUpdateId = 0 disables the gap check for the update.
+type DepthUpdate struct {
+	FirstUpdateId int64
+	LastUpdateId  int64
+	Bids          []PriceLevel
+	Asks          []PriceLevel
+}
+
+// LocalOrderBook is an order book of one symbol kept in memory. It starts
+// from a REST snapshot and is kept current either by polling (Run), by
+// applying depth updates (ApplyUpdate) or WebSocket depth snapshots
+// (ApplyDepthEvent).
+type LocalOrderBook struct {
+	Symbol Symbol
+	Limit  int32 // depth of REST snapshots, 0 means server default
+
+	client *Client
+
+	mu           sync.RWMutex
+	bids         []PriceLevel // best (highest) first
+	asks         []PriceLevel // best (lowest) first
+	lastUpdateId int64
+	lastEventTs  int64 // time of the last depth event applied
+	synced       bool
+}
+
+// NewLocalOrderBook creates an empty book, call Snapshot or Run to load it.
+func NewLocalOrderBook(client *Client, symbol Symbol) *LocalOrderBook {
+	if client == nil {
+		client = defaultClient()
+	}
+
+	return &LocalOrderBook{Symbol: symbol, client: client}
+}
+
+// Snapshot replaces the book with the current REST order book.
+func (b *LocalOrderBook) Snapshot(ctx context.Context) error {
+	depth, err := b.client.OrderBookWithContext(ctx, &DepthRequest{Symbol: b.Symbol, Limit: b.Limit})
+	if err != nil {
+		return err
+	}
+
+	bids, err := depthLevels(depth.Bids)
+	if err != nil {
+		return err
+	}
+
+	asks, err := depthLevels(depth.Asks)
+	if err != nil {
+		return err
+	}
+
+	sortLevels(bids, BidSide)
+	sortLevels(asks, AskSide)
+
+	b.mu.Lock()
+	b.bids, b.asks = bids, asks
+	b.lastUpdateId = depth.LastUpdateId
+	b.lastEventTs = 0
+	b.synced = true
+	b.mu.Unlock()
+
+	return nil
+}
+
+// Run polls the REST snapshot every interval until ctx is done. Failed
+// polls are retried on the next tick.
+func (b *LocalOrderBook) Run(ctx context.Context, interval time.Duration) error {
+	if interval <= 0 {
+		return fmt.Errorf("error params: interval need to be positive")
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		_ = b.Snapshot(ctx)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// ApplyUpdate applies an incremental update. On a gap the book loads a new
+// snapshot and applies the update on top of it if it is still relevant.
+// If the snapshot is older than the update the gap remains: the update is
+// not applied, the book stays out of sync so the next update loads a
+// snapshot again, and ErrBookGap is returned.
+func (b *LocalOrderBook) ApplyUpdate(ctx context.Context, update *DepthUpdate) error {
+	b.mu.Lock()
+	gap := !b.synced || b.missed(update)
+	b.mu.Unlock()
+
+	if gap {
+		if err := b.Snapshot(ctx); err != nil {
+			b.mu.Lock()
+			b.synced = false
+			b.mu.Unlock()
+
+			return err
+		}
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if update.LastUpdateId <= b.lastUpdateId {
+		return nil
+	}
+
+	if b.missed(update) {
+		b.synced = false
+
+		return fmt.Errorf("error in update %d-%d of %s, snapshot is at %d, %w", update.FirstUpdateId, update.LastUpdateId, b.Symbol, b.lastUpdateId, ErrBookGap)
+	}
+
+	for _, level := range update.Bids {
+		b.bids = setLevel(b.bids, level, BidSide)
+	}
+
+	for _, level := range update.Asks {
+		b.asks = setLevel(b.asks, level, AskSide)
+	}
+
+	b.lastUpdateId = update.LastUpdateId
+
+	return nil
+}
+
+// missed reports whether updates are missing between the book and the
+// update.
+func (b *LocalOrderBook) missed(update *DepthUpdate) bool {
+	return update.FirstUpdateId != 0 && update.FirstUpdateId > b.lastUpdateId+1
+}
+
+// ApplyDepthEvent applies a WebSocket depth event. Every event carries the
+// whole top of the book, so it replaces both sides and levels missing from
+// the event are gone. Events are ordered by their timestamps: an event not
+// newer than the last event applied is skipped. The lastUpdateId of REST
+// snapshots is not compared with the event times, so the first event after
+// a Snapshot always replaces the book.
+func (b *LocalOrderBook) ApplyDepthEvent(event *MarketDepthEvent) error {
+	if event.Symbol != "" && Symbol(event.Symbol) != b.Symbol {
+		return fmt.Errorf("error params: event for %s applied to %s book", event.Symbol, b.Symbol)
+	}
+
+	bids, err := eventLevels(event.Data.Bid)
+	if err != nil {
+		return err
+	}
+
+	asks, err := eventLevels(event.Data.Ofr)
+	if err != nil {
+		return err
+	}
+
+	sortLevels(bids, BidSide)
+	sortLevels(asks, AskSide)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if event.Data.Ts <= b.lastEventTs {
+		return nil
+	}
+
+	b.bids, b.asks = bids, asks
+	b.lastUpdateId = event.Data.Ts
+	b.lastEventTs = event.Data.Ts
+	b.synced = true
+
+	return nil
+}
+
+// Invalidate marks the book as out of sync, e.g. after a StreamGap, so the
+// next update loads a fresh snapshot.
+func (b *LocalOrderBook) Invalidate() {
+	b.mu.Lock()
+	b.synced = false
+	b.mu.Unlock()
+}
+
+// LastUpdateId returns the id of the last snapshot or update applied.
+func (b *LocalOrderBook) LastUpdateId() int64 {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	return b.lastUpdateId
+}
+
+// Levels returns up to n best levels of the side, n <= 0 means all.
+func (b *LocalOrderBook) Levels(side BookSide, n int) []PriceLevel {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	levels := b.side(side)
+	if n <= 0 || n > len(levels) {
+		n = len(levels)
+	}
+
+	out := make([]PriceLevel, n)
+	copy(out, levels)
+
+	return out
+}
+
+func (b *LocalOrderBook) BestBid() (PriceLevel, error) {
+	return b.best(BidSide)
+}
+
+func (b *LocalOrderBook) BestAsk() (PriceLevel, error) {
+	return b.best(AskSide)
+}
+
+// Spread returns best ask minus best bid.
+func (b *LocalOrderBook) Spread() (Decimal, error) {
+	bid, ask, err := b.top()
+	if err != nil {
+		return Decimal{}, err
+	}
+
+	return ask.Price.Sub(bid.Price), nil
+}
+
+// Mid returns the middle between best bid and best ask.
+func (b *LocalOrderBook) Mid() (Decimal, error) {
+	bid, ask, err := b.top()
+	if err != nil {
+		return Decimal{}, err
+	}
+
+	return ask.Price.Add(bid.Price).Div(NewDecimalFromInt(2)), nil
+}
+
+// DepthAt returns the quantity at exactly the price, 0 if there is no such
+// level.
+func (b *LocalOrderBook) DepthAt(side BookSide, price Decimal) Decimal {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	levels := b.side(side)
+	i := searchLevel(levels, price, side)
+	if i < len(levels) && levels[i].Price.Equal(price) {
+		return levels[i].Quantity
+	}
+
+	return Decimal{}
+}
+
+// CumulativeVolume returns the total quantity of the side from the best
+// level up to and including the price.
+func (b *LocalOrderBook) CumulativeVolume(side BookSide, price Decimal) Decimal {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	var total Decimal
+	for _, level := range b.side(side) {
+		if side == BidSide && level.Price.LessThan(price) || side == AskSide && level.Price.GreaterThan(price) {
+			break
+		}
+		total = total.Add(level.Quantity)
+	}
+
+	return total
+}
+
+// VWAP returns the average price of filling quantity against the side,
+// e.g. AskSide for a buy. If the side is too thin it returns the average
+// of what is available together with ErrNotEnoughLiquidity.
+func (b *LocalOrderBook) VWAP(side BookSide, quantity Decimal) (Decimal, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if !b.synced {
+		return Decimal{}, ErrBookNotReady
+	}
+
+	if !quantity.IsPositive() {
+		return Decimal{}, fmt.Errorf("error params: Quantity need to be positive")
+	}
+
+	var filled, cost Decimal
+	for _, level := range b.side(side) {
+		take := MinDecimal(level.Quantity, quantity.Sub(filled))
+
+		filled = filled.Add(take)
+		cost = cost.Add(take.Mul(level.Price))
+
+		if filled.GreaterThanOrEqual(quantity) {
+			break
+		}
+	}
+
+	if filled.IsZero() {
+		return Decimal{}, ErrNotEnoughLiquidity
+	}
+
+	if filled.LessThan(quantity) {
+		return cost.Div(filled), ErrNotEnoughLiquidity
+	}
+
+	return cost.Div(filled), nil
+}
+
+func (b *LocalOrderBook) side(side BookSide) []PriceLevel {
+	if side == BidSide {
+		return b.bids
+	}
+
+	return b.asks
+}
+
+func (b *LocalOrderBook) best(side BookSide) (PriceLevel, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	levels := b.side(side)
+	if !b.synced || len(levels) == 0 {
+		return PriceLevel{}, ErrBookNotReady
+	}
+
+	return levels[0], nil
+}
+
+func (b *LocalOrderBook) top() (PriceLevel, PriceLevel, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if !b.synced || len(b.bids) == 0 || len(b.asks) == 0 {
+		return PriceLevel{}, PriceLevel{}, ErrBookNotReady
+	}
+
+	return b.bids[0], b.asks[0], nil
+}
+
+// searchLevel returns the index of the price or the place to insert it.
+func searchLevel(levels []PriceLevel, price Decimal, side BookSide) int {
+	return sort.Search(len(levels), func(i int) bool {
+		if side == BidSide {
+			return levels[i].Price.LessThanOrEqual(price)
+		}
+
+		return levels[i].Price.GreaterThanOrEqual(price)
+	})
+}
+
+func setLevel(levels []PriceLevel, level PriceLevel, side BookSide) []PriceLevel {
+	i := searchLevel(levels, level.Price, side)
+	exists := i < len(levels) && levels[i].Price.Equal(level.Price)
+
+	switch {
+	case level.Quantity.IsZero() && exists:
+		return append(levels[:i], levels[i+1:]...)
+	case level.Quantity.IsZero():
+		return levels
+	case exists:
+		levels[i].Quantity = level.Quantity
+		return levels
+	}
+
+	levels = append(levels, PriceLevel{})
+	copy(levels[i+1:], levels[i:])
+	levels[i] = level
+
+	return levels
+}
+
+func sortLevels(levels []PriceLevel, side BookSide) {
+	sort.Slice(levels, func(i, j int) bool {
+		if side == BidSide {
+			return levels[i].Price.GreaterThan(levels[j].Price)
+		}
+
+		return levels[i].Price.LessThan(levels[j].Price)
+	})
+}
+
+func depthLevels(raw [][]Decimal) ([]PriceLevel, error) {
+	levels := make([]PriceLevel, 0, len(raw))
+	for _, row := range raw {
+		if len(row) < 2 {
+			return nil, fmt.Errorf("error in depth level %v, need price and quantity", row)
+		}
+
+		if !row[1].IsZero() {
+			levels = append(levels, PriceLevel{Price: row[0], Quantity: row[1]})
+		}
+	}
+
+	return levels, nil
+}
+
+func eventLevels(raw map[string]Decimal) ([]PriceLevel, error) {
+	levels := make([]PriceLevel, 0, len(raw))
+	for price, quantity := range raw {
+		p, err := ParseDecimal(price)
+		if err != nil {
+			return nil, fmt.Errorf("error in depth level, %w", err)
+		}
+
+		if !quantity.IsZero() {
+			levels = append(levels, PriceLevel{Price: p, Quantity: quantity})
+		}
+	}
+
+	return levels, nil
+}
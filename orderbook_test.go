@@ -0,0 +1,217 @@
+package currencycom
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+// depthServer serves a snapshot of bid 100 and ask 101 at the update id
+// stored in the returned pointer.
+func depthServer(t *testing.T, lastUpdateId int64) (*Client, *int64) {
+	t.Helper()
+
+	id := &lastUpdateId
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(DepthResponse{
+			Bids:         [][]Decimal{{MustDecimal("100"), MustDecimal("1")}},
+			Asks:         [][]Decimal{{MustDecimal("101"), MustDecimal("1")}},
+			LastUpdateId: atomic.LoadInt64(id),
+		})
+	}))
+	t.Cleanup(srv.Close)
+
+	return NewClient(srv.URL, WithRetryPolicy(NoRetry)), id
+}
+
+func depthEvent(ts int64, bid, ofr map[string]Decimal) *MarketDepthEvent {
+	return &MarketDepthEvent{Symbol: "BTC/USD", Data: MarketDepthData{Ts: ts, Bid: bid, Ofr: ofr}}
+}
+
+func TestApplyDepthEvent(t *testing.T) {
+	book := NewLocalOrderBook(nil, "BTC/USD")
+
+	first := depthEvent(1000,
+		map[string]Decimal{"100": MustDecimal("1"), "99": MustDecimal("2")},
+		map[string]Decimal{"101": MustDecimal("1"), "102": MustDecimal("3")},
+	)
+	if err := book.ApplyDepthEvent(first); err != nil {
+		t.Fatal(err)
+	}
+
+	// the next snapshot drops 100 and 102, they must not survive
+	second := depthEvent(2000,
+		map[string]Decimal{"99": MustDecimal("5"), "98": MustDecimal("1")},
+		map[string]Decimal{"101": MustDecimal("2"), "103": MustDecimal("0")},
+	)
+	if err := book.ApplyDepthEvent(second); err != nil {
+		t.Fatal(err)
+	}
+
+	// an older event is skipped
+	if err := book.ApplyDepthEvent(first); err != nil {
+		t.Fatal(err)
+	}
+
+	wantBids := []PriceLevel{{MustDecimal("99"), MustDecimal("5")}, {MustDecimal("98"), MustDecimal("1")}}
+	wantAsks := []PriceLevel{{MustDecimal("101"), MustDecimal("2")}}
+
+	assertLevels(t, "bids", book.Levels(BidSide, 0), wantBids)
+	assertLevels(t, "asks", book.Levels(AskSide, 0), wantAsks)
+
+	if id := book.LastUpdateId(); id != 2000 {
+		t.Errorf("LastUpdateId = %d, want 2000", id)
+	}
+}
+
+func TestApplyDepthEventAfterSnapshot(t *testing.T) {
+	client, _ := depthServer(t, 5000000000000)
+	book := NewLocalOrderBook(client, "BTC/USD")
+
+	if err := book.Snapshot(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	// the snapshot id is not an event time, the event still replaces the book
+	event := depthEvent(1000, map[string]Decimal{"99": MustDecimal("3")}, map[string]Decimal{"102": MustDecimal("4")})
+	if err := book.ApplyDepthEvent(event); err != nil {
+		t.Fatal(err)
+	}
+
+	assertLevels(t, "bids", book.Levels(BidSide, 0), []PriceLevel{{MustDecimal("99"), MustDecimal("3")}})
+	assertLevels(t, "asks", book.Levels(AskSide, 0), []PriceLevel{{MustDecimal("102"), MustDecimal("4")}})
+}
+
+func TestApplyUpdate(t *testing.T) {
+	client, id := depthServer(t, 10)
+	book := NewLocalOrderBook(client, "BTC/USD")
+	ctx := context.Background()
+
+	// the first update loads the snapshot at 10 and follows it
+	if err := book.ApplyUpdate(ctx, &DepthUpdate{FirstUpdateId: 11, LastUpdateId: 12, Bids: []PriceLevel{{MustDecimal("100"), MustDecimal("2")}}}); err != nil {
+		t.Fatal(err)
+	}
+	assertLevels(t, "bids", book.Levels(BidSide, 0), []PriceLevel{{MustDecimal("100"), MustDecimal("2")}})
+
+	// 13-19 are missing and the snapshot is still at 10
+	err := book.ApplyUpdate(ctx, &DepthUpdate{FirstUpdateId: 20, LastUpdateId: 21, Asks: []PriceLevel{{MustDecimal("101"), MustDecimal("5")}}})
+	if !errors.Is(err, ErrBookGap) {
+		t.Fatalf("err = %v, want ErrBookGap", err)
+	}
+	if _, err := book.BestAsk(); !errors.Is(err, ErrBookNotReady) {
+		t.Errorf("BestAsk after a gap: err = %v, want ErrBookNotReady", err)
+	}
+
+	// a newer snapshot covers the update, which is skipped
+	atomic.StoreInt64(id, 25)
+	if err := book.ApplyUpdate(ctx, &DepthUpdate{FirstUpdateId: 20, LastUpdateId: 21, Asks: []PriceLevel{{MustDecimal("101"), MustDecimal("5")}}}); err != nil {
+		t.Fatal(err)
+	}
+	assertLevels(t, "asks", book.Levels(AskSide, 0), []PriceLevel{{MustDecimal("101"), MustDecimal("1")}})
+
+	if err := book.ApplyUpdate(ctx, &DepthUpdate{FirstUpdateId: 26, LastUpdateId: 27, Asks: []PriceLevel{{MustDecimal("101"), MustDecimal("0")}, {MustDecimal("102"), MustDecimal("3")}}}); err != nil {
+		t.Fatal(err)
+	}
+	assertLevels(t, "asks", book.Levels(AskSide, 0), []PriceLevel{{MustDecimal("102"), MustDecimal("3")}})
+
+	if got := book.LastUpdateId(); got != 27 {
+		t.Errorf("LastUpdateId = %d, want 27", got)
+	}
+}
+
+func TestLocalOrderBookQueries(t *testing.T) {
+	book := NewLocalOrderBook(nil, "BTC/USD")
+
+	if _, err := book.Spread(); !errors.Is(err, ErrBookNotReady) {
+		t.Errorf("Spread of an empty book: err = %v, want ErrBookNotReady", err)
+	}
+	if _, err := book.VWAP(AskSide, MustDecimal("1")); !errors.Is(err, ErrBookNotReady) {
+		t.Errorf("VWAP of an empty book: err = %v, want ErrBookNotReady", err)
+	}
+
+	event := depthEvent(1000,
+		map[string]Decimal{"100": MustDecimal("1"), "99": MustDecimal("2"), "98": MustDecimal("3")},
+		map[string]Decimal{"101": MustDecimal("1"), "102": MustDecimal("2"), "103": MustDecimal("3")},
+	)
+	if err := book.ApplyDepthEvent(event); err != nil {
+		t.Fatal(err)
+	}
+
+	spread, err := book.Spread()
+	if err != nil || !spread.Equal(MustDecimal("1")) {
+		t.Errorf("Spread = %s %v, want 1", spread, err)
+	}
+
+	mid, err := book.Mid()
+	if err != nil || !mid.Equal(MustDecimal("100.5")) {
+		t.Errorf("Mid = %s %v, want 100.5", mid, err)
+	}
+
+	volumes := []struct {
+		side  BookSide
+		price string
+		want  string
+	}{
+		{BidSide, "99", "3"},
+		{BidSide, "100.5", "0"},
+		{BidSide, "1", "6"},
+		{AskSide, "102.5", "3"},
+		{AskSide, "100", "0"},
+	}
+	for _, v := range volumes {
+		if got := book.CumulativeVolume(v.side, MustDecimal(v.price)); !got.Equal(MustDecimal(v.want)) {
+			t.Errorf("CumulativeVolume(%d, %s) = %s, want %s", v.side, v.price, got, v.want)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		side     BookSide
+		quantity string
+		want     string
+		err      error
+	}{
+		{"best level", AskSide, "0.5", "101", nil},
+		{"two levels", AskSide, "2", "101.5", nil},
+		{"sell into bids", BidSide, "2", "99.5", nil},
+		{"too thin", AskSide, "10", MustDecimal("614").Div(MustDecimal("6")).String(), ErrNotEnoughLiquidity},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := book.VWAP(tt.side, MustDecimal(tt.quantity))
+			if !errors.Is(err, tt.err) || !got.Equal(MustDecimal(tt.want)) {
+				t.Errorf("VWAP = %s %v, want %s %v", got, err, tt.want, tt.err)
+			}
+		})
+	}
+
+	if _, err := book.VWAP(AskSide, MustDecimal("0")); err == nil {
+		t.Error("VWAP of zero: want error")
+	}
+}
+
+func TestLocalOrderBookRunInterval(t *testing.T) {
+	book := NewLocalOrderBook(nil, "BTC/USD")
+	if err := book.Run(context.Background(), 0); err == nil {
+		t.Error("Run with zero interval: want error")
+	}
+}
+
+func assertLevels(t *testing.T, name string, got, want []PriceLevel) {
+	t.Helper()
+
+	if len(got) != len(want) {
+		t.Fatalf("%s = %v, want %v", name, got, want)
+	}
+
+	for i := range want {
+		if !got[i].Price.Equal(want[i].Price) || !got[i].Quantity.Equal(want[i].Quantity) {
+			t.Errorf("%s[%d] = %v, want %v", name, i, got[i], want[i])
+		}
+	}
+}
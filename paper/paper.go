@@ -0,0 +1,404 @@
+// Package paper trades with simulated money against live prices. Client
+// implements currencycom.Trading and AccountInfo like RestAPI, so a bot can
+// switch between them:
+//
+//	source := paper.TickerSource(nil)
+//	api := paper.NewClient(source, currencycom.NewDecimalFromInt(10000), info.Symbols...)
+//	go api.Run(ctx, time.Second)
+//
+//	order, err := api.CreateOrder(&currencycom.CreateOrderRequest{...})
+//
+// Orders fill like in the backtest package: market orders at the current
+// price, limit and stop orders when a polled price reaches them.
+//
+// Every symbol, spot pairs included, is traded as a netted position
+// settled in the account currency: buying BTC/USD opens a long position
+// whose profit and loss go to the cash balance, it doesn't exchange USD
+// for a BTC balance. AccountInfo reports that one cash balance, open
+// positions are listed by ListOfLeverageTrades.
+package paper
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	currencycom "github.com/scientistnik/currency.com"
+	"github.com/scientistnik/currency.com/backtest"
+)
+
+var _ currencycom.Trading = (*Client)(nil)
+
+// DEFAULT_CURRENCY is the account currency when Currency is not set.
+const DEFAULT_CURRENCY = "USD"
+
+// Client is a paper-trading account. Balances, orders and positions live
+// in memory and are lost with the client.
+type Client struct {
+	Source    PriceSource
+	AccountId string
+	Currency  string // asset of the balance, "" means DEFAULT_CURRENCY
+
+	broker *backtest.Broker
+
+	mu        sync.Mutex
+	requestId int64
+}
+
+// NewClient creates an account with a cash balance trading symbols at
+// the prices of source.
+func NewClient(source PriceSource, cash currencycom.Decimal, symbols ...currencycom.ExchangeSymbolInfo) *Client {
+	broker := backtest.NewBroker(cash, symbols...)
+	broker.Immediate = true
+
+	return &Client{Source: source, AccountId: "paper", broker: broker}
+}
+
+// Broker returns the simulated account, e.g. for its fills and closed
+// trades.
+func (c *Client) Broker() *backtest.Broker {
+	return c.broker
+}
+
+// Refresh polls the price of every symbol with working orders or an open
+// position, filling what the prices reach. It goes on after a failed
+// symbol and returns the first error.
+func (c *Client) Refresh(ctx context.Context) error {
+	symbols := make(map[string]struct{})
+	for _, o := range c.broker.Orders() {
+		symbols[string(o.Request.Symbol)] = struct{}{}
+	}
+	for _, p := range c.broker.Positions() {
+		symbols[p.Symbol] = struct{}{}
+	}
+
+	var first error
+	for symbol := range symbols {
+		if err := c.refresh(ctx, symbol); err != nil && first == nil {
+			first = err
+		}
+	}
+
+	return first
+}
+
+// Run calls Refresh every interval until ctx is done.
+func (c *Client) Run(ctx context.Context, interval time.Duration) error {
+	if interval <= 0 {
+		return fmt.Errorf("error params: interval need to be positive")
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		_ = c.Refresh(ctx)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func (c *Client) refresh(ctx context.Context, symbol string) error {
+	price, err := c.Source.Price(ctx, symbol)
+	if err != nil {
+		return fmt.Errorf("error in price of %s, %w", symbol, err)
+	}
+
+	c.broker.UpdatePrice(symbol, time.Now(), price)
+
+	return nil
+}
+
+func (c *Client) nextRequestId() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.requestId++
+
+	return c.requestId
+}
+
+func (c *Client) CreateOrder(params *currencycom.CreateOrderRequest) (*currencycom.NewOrderResponseRESULT, error) {
+	return c.CreateOrderWithContext(context.Background(), params)
+}
+
+// CreateOrderWithContext places an order at the current price of the
+// symbol. Market orders and limit orders past the price fill at once.
+func (c *Client) CreateOrderWithContext(ctx context.Context, params *currencycom.CreateOrderRequest) (*currencycom.NewOrderResponseRESULT, error) {
+	if params == nil {
+		return nil, fmt.Errorf("error params: Symbol, Quantity, Side, Type need to set")
+	}
+
+	if !params.Symbol.IsValid() {
+		return nil, fmt.Errorf("error params: invalid Symbol %q", params.Symbol)
+	}
+
+	if _, ok := c.broker.Symbol(string(params.Symbol)); !ok {
+		return nil, fmt.Errorf("%w %q", currencycom.ErrInvalidSymbol, params.Symbol)
+	}
+
+	if err := c.refresh(ctx, string(params.Symbol)); err != nil {
+		return nil, err
+	}
+
+	out, err := c.broker.CreateOrder(params)
+	if err != nil {
+		return nil, err
+	}
+
+	if !out.ExecutedQty.IsZero() {
+		return out, nil
+	}
+
+	// a limit or stop order may be reached by the current price
+	price, _ := c.broker.Price(string(params.Symbol))
+	c.broker.UpdatePrice(string(params.Symbol), time.Now(), price)
+
+	fills := c.broker.Fills()
+	for i := len(fills) - 1; i >= 0; i-- {
+		if fills[i].OrderId == out.OrderId {
+			out.ExecutedQty, out.Price = params.Quantity, fills[i].Price
+			break
+		}
+	}
+
+	return out, nil
+}
+
+func (c *Client) CancelOrder(params *currencycom.CancelOrderRequest) (*currencycom.CancelOrderResponse, error) {
+	return c.CancelOrderWithContext(context.Background(), params)
+}
+
+func (c *Client) CancelOrderWithContext(ctx context.Context, params *currencycom.CancelOrderRequest) (*currencycom.CancelOrderResponse, error) {
+	if params == nil || params.OrderId == "" {
+		return nil, fmt.Errorf("error params: Symbol, OrderId need to set")
+	}
+
+	for _, o := range c.broker.Orders() {
+		if o.Id == params.OrderId && params.Symbol != "" && o.Request.Symbol != params.Symbol {
+			return nil, fmt.Errorf("%w: %s of %s", currencycom.ErrOrderNotFound, params.OrderId, params.Symbol)
+		}
+	}
+
+	o, err := c.broker.CancelOrder(params.OrderId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &currencycom.CancelOrderResponse{
+		ExecutedQty: currencycom.Decimal{},
+		OrderId:     o.Id,
+		OrigQty:     o.Request.Quantity,
+		Price:       o.Request.Price,
+		Side:        o.Request.Side,
+		Status:      o.Status,
+		Symbol:      string(o.Request.Symbol),
+		TimeInForce: currencycom.TimeInForceGTC,
+		Type:        o.Request.Type,
+	}, nil
+}
+
+func (c *Client) ListOfOpenOrder(params *currencycom.PositionHistoryRequest) ([]currencycom.QueryOrderResponse, error) {
+	return c.ListOfOpenOrderWithContext(context.Background(), params)
+}
+
+func (c *Client) ListOfOpenOrderWithContext(ctx context.Context, params *currencycom.PositionHistoryRequest) ([]currencycom.QueryOrderResponse, error) {
+	var symbol currencycom.Symbol
+	if params != nil {
+		symbol = params.Symbol
+	}
+
+	out := []currencycom.QueryOrderResponse{}
+	for _, o := range c.broker.Orders() {
+		if symbol != "" && o.Request.Symbol != symbol {
+			continue
+		}
+
+		out = append(out, c.queryOrder(o))
+	}
+
+	return out, nil
+}
+
+func (c *Client) queryOrder(o backtest.Order) currencycom.QueryOrderResponse {
+	return currencycom.QueryOrderResponse{
+		AccountId:          c.AccountId,
+		ExpireTimestamp:    o.Request.ExpireTimestamp,
+		GuaranteedStopLoss: o.Request.GuaranteedStopLoss,
+		Leverage:           o.Request.Symbol.IsLeverage(),
+		OrderId:            o.Id,
+		OrigQty:            o.Request.Quantity,
+		Price:              o.Request.Price,
+		Side:               o.Request.Side,
+		Status:             o.Status,
+		StopLoss:           o.Request.StopLoss,
+		Symbol:             string(o.Request.Symbol),
+		TakeProfit:         o.Request.TakeProfit,
+		Time:               o.Created.UnixMilli(),
+		TimeInForce:        currencycom.TimeInForceGTC,
+		Type:               o.Request.Type,
+		UpdateTime:         o.Updated.UnixMilli(),
+		Working:            true,
+	}
+}
+
+func (c *Client) LeverageOrdersEdit(params *currencycom.UpdateTradingOrderRequest) (*currencycom.TradingOrderUpdateResponse, error) {
+	return c.LeverageOrdersEditWithContext(context.Background(), params)
+}
+
+// LeverageOrdersEditWithContext changes a working order, a new price may
+// fill it at once.
+func (c *Client) LeverageOrdersEditWithContext(ctx context.Context, params *currencycom.UpdateTradingOrderRequest) (*currencycom.TradingOrderUpdateResponse, error) {
+	if params == nil || params.OrderId == "" {
+		return nil, fmt.Errorf("error params: OrderId need to set")
+	}
+
+	o, err := c.broker.UpdateOrder(params.OrderId, params.NewPrice, params.StopLoss, params.TakeProfit, params.ExpireTimestamp)
+	if err != nil {
+		return nil, err
+	}
+
+	if !params.NewPrice.IsZero() {
+		if err := c.refresh(ctx, string(o.Request.Symbol)); err != nil {
+			return nil, err
+		}
+	}
+
+	return &currencycom.TradingOrderUpdateResponse{
+		RequestId: c.nextRequestId(),
+		State:     currencycom.DtoStateProcessed,
+	}, nil
+}
+
+func (c *Client) ListOfLeverageTrades(params *currencycom.SignedRequest) (*currencycom.TradingPositionListResponse, error) {
+	return c.ListOfLeverageTradesWithContext(context.Background(), params)
+}
+
+// ListOfLeverageTradesWithContext returns the open positions with their
+// unrealized profit at the last polled price.
+func (c *Client) ListOfLeverageTradesWithContext(ctx context.Context, params *currencycom.SignedRequest) (*currencycom.TradingPositionListResponse, error) {
+	out := &currencycom.TradingPositionListResponse{Positions: []currencycom.PositionDto{}}
+
+	for _, p := range c.broker.Positions() {
+		price, _ := c.broker.Price(p.Symbol)
+		upl := p.Upl(price)
+
+		out.Positions = append(out.Positions, currencycom.PositionDto{
+			AccountId:        c.AccountId,
+			CreatedTimestamp: p.OpenTime.UnixMilli(),
+			Currency:         c.currency(),
+			Fee:              p.Fee,
+			Id:               p.Id,
+			OpenPrice:        p.OpenPrice,
+			OpenQuantity:     p.Quantity,
+			OpenTimestamp:    p.OpenTime.UnixMilli(),
+			OrderId:          p.OrderId,
+			State:            currencycom.PositionDtoStateActive,
+			StopLoss:         p.StopLoss,
+			Swap:             p.Swap,
+			SwapConverted:    p.Swap,
+			Symbol:           p.Symbol,
+			TakeProfit:       p.TakeProfit,
+			Type:             currencycom.PositionDtoTypeNet,
+			Upl:              upl,
+			UplConverted:     upl,
+		})
+	}
+
+	return out, nil
+}
+
+func (c *Client) TradingPositionClose(params *currencycom.CloseTradingPositionRequest) (*currencycom.TradingPositionCloseAllResponse, error) {
+	return c.TradingPositionCloseWithContext(context.Background(), params)
+}
+
+// TradingPositionCloseWithContext closes a position at the current price.
+func (c *Client) TradingPositionCloseWithContext(ctx context.Context, params *currencycom.CloseTradingPositionRequest) (*currencycom.TradingPositionCloseAllResponse, error) {
+	if params == nil || params.PositionId == "" {
+		return nil, fmt.Errorf("error params: PositionId need to set")
+	}
+
+	var symbol string
+	for _, p := range c.broker.Positions() {
+		if p.Id == params.PositionId {
+			symbol = p.Symbol
+		}
+	}
+
+	if symbol == "" {
+		return nil, fmt.Errorf("%w: %s", currencycom.ErrPositionNotFound, params.PositionId)
+	}
+
+	if err := c.refresh(ctx, symbol); err != nil {
+		return nil, err
+	}
+
+	// fails if the refresh hit the stop loss or take profit
+	if _, err := c.broker.ClosePosition(params.PositionId); err != nil {
+		return nil, err
+	}
+
+	return &currencycom.TradingPositionCloseAllResponse{Request: []currencycom.RequestDto{{
+		AccountId:        c.AccountId,
+		CreatedTimestamp: time.Now().UnixMilli(),
+		Id:               c.nextRequestId(),
+		PositionId:       params.PositionId,
+		RqType:           currencycom.DtoTypeOrderNew,
+		State:            currencycom.DtoStateProcessed,
+	}}}, nil
+}
+
+func (c *Client) LeverageTradeEdit(params *currencycom.UpdateTradingPositionRequest) (*currencycom.TradingPositionUpdateResponse, error) {
+	return c.LeverageTradeEditWithContext(context.Background(), params)
+}
+
+// LeverageTradeEditWithContext sets the stop loss and take profit of a
+// position, zero removes them.
+func (c *Client) LeverageTradeEditWithContext(ctx context.Context, params *currencycom.UpdateTradingPositionRequest) (*currencycom.TradingPositionUpdateResponse, error) {
+	if params == nil || params.PositionId == "" {
+		return nil, fmt.Errorf("error params: PositionId need to set")
+	}
+
+	if _, err := c.broker.UpdatePosition(params.PositionId, params.StopLoss, params.TakeProfit); err != nil {
+		return nil, err
+	}
+
+	return &currencycom.TradingPositionUpdateResponse{
+		RequestId: c.nextRequestId(),
+		State:     currencycom.DtoStateProcessed,
+	}, nil
+}
+
+func (c *Client) AccountInfo(params *currencycom.AccountRequest) (*currencycom.AccountResponse, error) {
+	return c.AccountInfoWithContext(context.Background(), params)
+}
+
+// AccountInfoWithContext returns the cash balance in Currency, without the
+// unrealized profit of positions. There are no balances of other assets,
+// spot buys are positions too.
+func (c *Client) AccountInfoWithContext(ctx context.Context, params *currencycom.AccountRequest) (*currencycom.AccountResponse, error) {
+	return &currencycom.AccountResponse{
+		Balances: []currencycom.AccountBalance{{
+			AccountId:          c.AccountId,
+			Asset:              c.currency(),
+			CollateralCurrency: true,
+			Default:            true,
+			Free:               c.broker.Cash(),
+		}},
+		CanTrade:   true,
+		UpdateTime: time.Now().UnixMilli(),
+	}, nil
+}
+
+func (c *Client) currency() string {
+	if c.Currency == "" {
+		return DEFAULT_CURRENCY
+	}
+
+	return c.Currency
+}
@@ -0,0 +1,279 @@
+package paper
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	currencycom "github.com/scientistnik/currency.com"
+)
+
+var d = currencycom.MustDecimal
+
+const btc = "BTC/USD_LEVERAGE"
+
+// prices is a PriceSource the test moves by hand.
+type prices struct {
+	mu     sync.Mutex
+	values map[string]currencycom.Decimal
+}
+
+func (p *prices) set(symbol, price string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.values[symbol] = d(price)
+}
+
+func (p *prices) Price(ctx context.Context, symbol string) (currencycom.Decimal, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	price, ok := p.values[symbol]
+	if !ok {
+		return currencycom.Decimal{}, currencycom.ErrInvalidSymbol
+	}
+
+	return price, nil
+}
+
+func testClient(t *testing.T) (*Client, *prices) {
+	t.Helper()
+
+	source := &prices{values: map[string]currencycom.Decimal{}}
+	source.set(btc, "100")
+
+	client := NewClient(source, d("10000"), currencycom.ExchangeSymbolInfo{Symbol: btc, MarketType: currencycom.MarketTypeLeverage})
+
+	return client, source
+}
+
+func order(side currencycom.OrderSide, orderType currencycom.OrderType, quantity, price string) *currencycom.CreateOrderRequest {
+	req := &currencycom.CreateOrderRequest{Symbol: btc, Side: side, Type: orderType, Quantity: d(quantity)}
+	if price != "" {
+		req.Price = d(price)
+	}
+
+	return req
+}
+
+func TestClientRunInterval(t *testing.T) {
+	client := NewClient(nil, currencycom.NewDecimalFromInt(10000))
+	if err := client.Run(context.Background(), 0); err == nil {
+		t.Error("Run with zero interval: want error")
+	}
+}
+
+func TestClientCreateOrder(t *testing.T) {
+	tests := []struct {
+		name     string
+		order    *currencycom.CreateOrderRequest
+		executed string
+		price    string
+	}{
+		{"market", order(currencycom.OrderSideBuy, currencycom.OrderTypeMarket, "2", ""), "2", "100"},
+		{"buy limit under the price rests", order(currencycom.OrderSideBuy, currencycom.OrderTypeLimit, "2", "95"), "0", "95"},
+		{"buy limit over the price fills at the price", order(currencycom.OrderSideBuy, currencycom.OrderTypeLimit, "2", "105"), "2", "100"},
+		{"sell stop under the price rests", order(currencycom.OrderSideSell, currencycom.OrderTypeStop, "2", "95"), "0", "95"},
+		{"sell stop over the price fills at the price", order(currencycom.OrderSideSell, currencycom.OrderTypeStop, "2", "105"), "2", "100"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, _ := testClient(t)
+
+			out, err := client.CreateOrder(tt.order)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !out.ExecutedQty.Equal(d(tt.executed)) || !out.Price.Equal(d(tt.price)) {
+				t.Errorf("executed %s at %s, want %s at %s", out.ExecutedQty, out.Price, tt.executed, tt.price)
+			}
+
+			open, _ := client.ListOfOpenOrder(nil)
+			if resting := tt.executed == "0"; resting != (len(open) == 1) {
+				t.Errorf("open orders %v, resting %v", open, resting)
+			}
+		})
+	}
+
+	client, _ := testClient(t)
+	if _, err := client.CreateOrder(&currencycom.CreateOrderRequest{Symbol: "ETH/USD", Side: currencycom.OrderSideBuy, Type: currencycom.OrderTypeMarket, Quantity: d("1")}); !errors.Is(err, currencycom.ErrInvalidSymbol) {
+		t.Errorf("order of an unknown symbol: err = %v, want ErrInvalidSymbol", err)
+	}
+}
+
+func TestClientTriggers(t *testing.T) {
+	client, source := testClient(t)
+	ctx := context.Background()
+
+	limit, err := client.CreateOrder(order(currencycom.OrderSideBuy, currencycom.OrderTypeLimit, "1", "95"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	stop, err := client.CreateOrder(order(currencycom.OrderSideBuy, currencycom.OrderTypeStop, "1", "110"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	source.set(btc, "94")
+	if err := client.Refresh(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	source.set(btc, "112")
+	if err := client.Refresh(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	fills := client.Broker().Fills()
+	if len(fills) != 2 || fills[0].OrderId != limit.OrderId || !fills[0].Price.Equal(d("94")) || fills[1].OrderId != stop.OrderId || !fills[1].Price.Equal(d("112")) {
+		t.Errorf("fills %+v, want the limit at 94 and the stop at 112", fills)
+	}
+}
+
+func TestClientCancelOrder(t *testing.T) {
+	client, _ := testClient(t)
+
+	out, err := client.CreateOrder(order(currencycom.OrderSideBuy, currencycom.OrderTypeLimit, "1", "90"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := client.CancelOrder(&currencycom.CancelOrderRequest{Symbol: "ETH/USD", OrderId: out.OrderId}); !errors.Is(err, currencycom.ErrOrderNotFound) {
+		t.Errorf("cancel with another symbol: err = %v, want ErrOrderNotFound", err)
+	}
+
+	canceled, err := client.CancelOrder(&currencycom.CancelOrderRequest{Symbol: btc, OrderId: out.OrderId})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if canceled.Status != currencycom.OrderStatusCanceled || !canceled.OrigQty.Equal(d("1")) {
+		t.Errorf("canceled %+v, want CANCELED of 1", canceled)
+	}
+
+	if _, err := client.CancelOrder(&currencycom.CancelOrderRequest{OrderId: out.OrderId}); !errors.Is(err, currencycom.ErrOrderNotFound) {
+		t.Errorf("cancel twice: err = %v, want ErrOrderNotFound", err)
+	}
+}
+
+func TestClientPositions(t *testing.T) {
+	client, source := testClient(t)
+
+	if _, err := client.CreateOrder(order(currencycom.OrderSideBuy, currencycom.OrderTypeMarket, "2", "")); err != nil {
+		t.Fatal(err)
+	}
+
+	source.set(btc, "110")
+	if err := client.Refresh(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	list, err := client.ListOfLeverageTrades(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list.Positions) != 1 {
+		t.Fatalf("positions %+v, want 1", list.Positions)
+	}
+
+	p := list.Positions[0]
+	if !p.OpenQuantity.Equal(d("2")) || !p.OpenPrice.Equal(d("100")) || !p.Upl.Equal(d("20")) || p.Currency != DEFAULT_CURRENCY {
+		t.Errorf("position %+v, want 2 at 100 with upl 20 USD", p)
+	}
+
+	// the stop loss closes the position on the next price under it
+	if _, err := client.LeverageTradeEdit(&currencycom.UpdateTradingPositionRequest{PositionId: p.Id, StopLoss: d("105")}); err != nil {
+		t.Fatal(err)
+	}
+	source.set(btc, "104")
+	if err := client.Refresh(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if list, _ := client.ListOfLeverageTrades(nil); len(list.Positions) != 0 {
+		t.Errorf("positions %+v, want closed by the stop loss", list.Positions)
+	}
+
+	account, err := client.AccountInfo(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(account.Balances) != 1 || account.Balances[0].Asset != DEFAULT_CURRENCY || !account.Balances[0].Free.Equal(d("10008")) {
+		t.Errorf("balances %+v, want 10008 USD after a profit of 4 a unit", account.Balances)
+	}
+}
+
+func TestClientTradingPositionClose(t *testing.T) {
+	client, source := testClient(t)
+	client.Currency = "EUR"
+
+	if _, err := client.CreateOrder(order(currencycom.OrderSideSell, currencycom.OrderTypeMarket, "3", "")); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := client.TradingPositionClose(&currencycom.CloseTradingPositionRequest{PositionId: "position-0"}); !errors.Is(err, currencycom.ErrPositionNotFound) {
+		t.Errorf("close an unknown position: err = %v, want ErrPositionNotFound", err)
+	}
+
+	positions := client.Broker().Positions()
+	if len(positions) != 1 {
+		t.Fatalf("positions %+v, want the short", positions)
+	}
+
+	source.set(btc, "90")
+	out, err := client.TradingPositionClose(&currencycom.CloseTradingPositionRequest{PositionId: positions[0].Id})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(out.Request) != 1 || out.Request[0].PositionId != positions[0].Id || out.Request[0].State != currencycom.DtoStateProcessed {
+		t.Errorf("close response %+v, want the processed request", out)
+	}
+
+	account, _ := client.AccountInfo(nil)
+	if account.Balances[0].Asset != "EUR" || !account.Balances[0].Free.Equal(d("10030")) {
+		t.Errorf("balances %+v, want 10030 EUR after the short closed at 90", account.Balances)
+	}
+}
+
+func TestClientLeverageOrdersEdit(t *testing.T) {
+	client, _ := testClient(t)
+
+	out, err := client.CreateOrder(order(currencycom.OrderSideBuy, currencycom.OrderTypeLimit, "1", "90"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := client.LeverageOrdersEdit(&currencycom.UpdateTradingOrderRequest{}); err == nil {
+		t.Error("edit without OrderId: want error")
+	}
+	if _, err := client.LeverageOrdersEdit(&currencycom.UpdateTradingOrderRequest{OrderId: "order-404", NewPrice: d("95")}); !errors.Is(err, currencycom.ErrOrderNotFound) {
+		t.Errorf("edit an unknown order: err = %v, want ErrOrderNotFound", err)
+	}
+
+	resp, err := client.LeverageOrdersEdit(&currencycom.UpdateTradingOrderRequest{OrderId: out.OrderId, StopLoss: d("80")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.State != currencycom.DtoStateProcessed {
+		t.Errorf("state %s, want PROCESSED", resp.State)
+	}
+	if open, _ := client.ListOfOpenOrder(nil); len(open) != 1 {
+		t.Fatalf("open orders %+v, want the limit still working", open)
+	}
+
+	// a new price over the market fills on the refresh of the edit
+	if _, err := client.LeverageOrdersEdit(&currencycom.UpdateTradingOrderRequest{OrderId: out.OrderId, NewPrice: d("105")}); err != nil {
+		t.Fatal(err)
+	}
+	if open, _ := client.ListOfOpenOrder(nil); len(open) != 0 {
+		t.Errorf("open orders %+v, want the limit filled", open)
+	}
+
+	fills := client.Broker().Fills()
+	if len(fills) != 1 || !fills[0].Price.Equal(d("100")) {
+		t.Errorf("fills %+v, want one at 100", fills)
+	}
+}
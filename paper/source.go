@@ -0,0 +1,59 @@
+package paper
+
+import (
+	"context"
+	"fmt"
+
+	currencycom "github.com/scientistnik/currency.com"
+)
+
+// PriceSource returns the current price of a symbol.
+type PriceSource interface {
+	Price(ctx context.Context, symbol string) (currencycom.Decimal, error)
+}
+
+// PriceSourceFunc adapts a function to PriceSource.
+type PriceSourceFunc func(ctx context.Context, symbol string) (currencycom.Decimal, error)
+
+func (f PriceSourceFunc) Price(ctx context.Context, symbol string) (currencycom.Decimal, error) {
+	return f(ctx, symbol)
+}
+
+// TickerSource polls the last price of PriceChange, nil client means the
+// default client.
+func TickerSource(client *currencycom.Client) PriceSource {
+	return PriceSourceFunc(func(ctx context.Context, symbol string) (currencycom.Decimal, error) {
+		var ticker *currencycom.Ticker24hr
+		var err error
+
+		params := &currencycom.BySymbolRequest{Symbol: currencycom.Symbol(symbol)}
+		if client == nil {
+			ticker, err = currencycom.PriceChangeWithContext(ctx, params)
+		} else {
+			ticker, err = client.PriceChangeWithContext(ctx, params)
+		}
+		if err != nil {
+			return currencycom.Decimal{}, err
+		}
+
+		return ticker.LastPrice, nil
+	})
+}
+
+// BookSource takes the mid price of local order books, kept current by
+// their owner, e.g. with LocalOrderBook.Run.
+func BookSource(books ...*currencycom.LocalOrderBook) PriceSource {
+	bySymbol := make(map[string]*currencycom.LocalOrderBook, len(books))
+	for _, b := range books {
+		bySymbol[string(b.Symbol)] = b
+	}
+
+	return PriceSourceFunc(func(ctx context.Context, symbol string) (currencycom.Decimal, error) {
+		book, ok := bySymbol[symbol]
+		if !ok {
+			return currencycom.Decimal{}, fmt.Errorf("%w: no order book of %s", currencycom.ErrInvalidSymbol, symbol)
+		}
+
+		return book.Mid()
+	})
+}
@@ -0,0 +1,219 @@
+package currencycom
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// Values of RateLimits.RateLimitType.
+const (
+	RateLimitTypeRequestWeight = "REQUEST_WEIGHT"
+	RateLimitTypeOrders        = "ORDERS"
+	RateLimitTypeRawRequests   = "RAW_REQUESTS"
+)
+
+// ErrRateLimited is returned by a RateLimiter in RateLimitFailFast mode
+// when a request would exceed the limits.
+var ErrRateLimited = errors.New("currencycom: client-side rate limit exceeded")
+
+type RateLimitMode int
+
+const (
+	RateLimitBlock    RateLimitMode = iota // wait until the request fits into the limits
+	RateLimitFailFast                      // return ErrRateLimited immediately
+)
+
+// RateLimiter is a client-side token bucket limiter built from the
+// exchange RateLimits. One limiter can be shared by several RestAPI
+// instances that use the same API key.
+type RateLimiter struct {
+	mode RateLimitMode
+
+	mu      sync.Mutex
+	buckets map[string][]*bucket
+}
+
+type bucket struct {
+	capacity float64
+	tokens   float64
+	perToken time.Duration
+	last     time.Time
+}
+
+// NewRateLimiter creates a limiter with the given limits. Limits can be
+// replaced later with SetLimits, e.g. from ExchangeInfo().RateLimits.
+func NewRateLimiter(mode RateLimitMode, limits ...RateLimits) *RateLimiter {
+	l := &RateLimiter{mode: mode}
+	l.SetLimits(limits)
+
+	return l
+}
+
+// SetLimits replaces the current limits. Limits with an unknown interval
+// or non-positive values are ignored.
+func (l *RateLimiter) SetLimits(limits []RateLimits) {
+	buckets := make(map[string][]*bucket)
+	now := time.Now()
+
+	for _, limit := range limits {
+		interval := rateLimitInterval(limit.Interval) * time.Duration(limit.IntervalNum)
+		if interval <= 0 || limit.Limit <= 0 {
+			continue
+		}
+
+		buckets[limit.RateLimitType] = append(buckets[limit.RateLimitType], &bucket{
+			capacity: float64(limit.Limit),
+			tokens:   float64(limit.Limit),
+			perToken: interval / time.Duration(limit.Limit),
+			last:     now,
+		})
+	}
+
+	l.mu.Lock()
+	l.buckets = buckets
+	l.mu.Unlock()
+}
+
+// Wait takes weight tokens from every limit of limitType, blocking or
+// failing according to the limiter mode.
+func (l *RateLimiter) Wait(ctx context.Context, limitType string, weight int) error {
+	return l.wait(ctx, map[string]int{limitType: weight})
+}
+
+// wait takes the weights from all limit types at once, so a request that
+// does not fit into one of them takes nothing from the others.
+func (l *RateLimiter) wait(ctx context.Context, weights map[string]int) error {
+	for {
+		wait := l.reserve(weights)
+		if wait == 0 {
+			return nil
+		}
+
+		if l.mode == RateLimitFailFast {
+			return ErrRateLimited
+		}
+
+		if err := sleep(ctx, wait); err != nil {
+			return err
+		}
+	}
+}
+
+// reserve takes the tokens if all buckets have enough of them, otherwise
+// it returns the time to wait until they do.
+func (l *RateLimiter) reserve(weights map[string]int) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	var wait time.Duration
+
+	for limitType, weight := range weights {
+		for _, b := range l.buckets[limitType] {
+			b.refill(now)
+
+			need := float64(weight)
+			if need > b.capacity {
+				need = b.capacity
+			}
+
+			if b.tokens < need {
+				if d := time.Duration((need - b.tokens) * float64(b.perToken)); d > wait {
+					wait = d
+				}
+			}
+		}
+	}
+
+	if wait > 0 {
+		return wait
+	}
+
+	for limitType, weight := range weights {
+		for _, b := range l.buckets[limitType] {
+			b.tokens -= float64(weight)
+			if b.tokens < 0 {
+				b.tokens = 0
+			}
+		}
+	}
+
+	return 0
+}
+
+func (b *bucket) refill(now time.Time) {
+	if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.tokens += float64(elapsed) / float64(b.perToken)
+		if b.tokens > b.capacity {
+			b.tokens = b.capacity
+		}
+		b.last = now
+	}
+}
+
+func rateLimitInterval(interval string) time.Duration {
+	switch interval {
+	case "SECOND":
+		return time.Second
+	case "MINUTE":
+		return time.Minute
+	case "HOUR":
+		return time.Hour
+	case "DAY":
+		return 24 * time.Hour
+	}
+
+	return 0
+}
+
+// SetRateLimiter sets the limiter used by the package-level market data
+// functions, nil disables limiting. It is safe to call while they run.
+func SetRateLimiter(limiter *RateLimiter) {
+	updateDefaultClient(func(c *Client) {
+		c.limiter = limiter
+	})
+}
+
+// WithRateLimiter makes the client wait on the limiter before every request.
+func WithRateLimiter(limiter *RateLimiter) Option {
+	return func(r *RestAPI) {
+		r.limiter = limiter
+	}
+}
+
+func (args *requestArgs) waitRateLimit(ctx context.Context) error {
+	limiter := args.client.limiter
+	if limiter == nil {
+		return nil
+	}
+
+	weight := args.weight
+	if weight <= 0 {
+		weight = 1
+	}
+
+	weights := map[string]int{
+		RateLimitTypeRequestWeight: weight,
+		RateLimitTypeRawRequests:   1,
+	}
+	if args.order {
+		weights[RateLimitTypeOrders] = 1
+	}
+
+	return limiter.wait(ctx, weights)
+}
+
+// depthWeight is the REQUEST_WEIGHT of the order book, it grows with the
+// limit.
+func depthWeight(limit int32) int {
+	switch {
+	case limit <= 100:
+		return 1
+	case limit <= 500:
+		return 5
+	default:
+		return 10
+	}
+}
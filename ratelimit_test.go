@@ -0,0 +1,58 @@
+package currencycom
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestRateLimiterAtomic(t *testing.T) {
+	limiter := NewRateLimiter(RateLimitFailFast,
+		RateLimits{Interval: "MINUTE", IntervalNum: 1, Limit: 10, RateLimitType: RateLimitTypeRequestWeight},
+		RateLimits{Interval: "MINUTE", IntervalNum: 1, Limit: 1, RateLimitType: RateLimitTypeOrders},
+		RateLimits{Interval: "MINUTE", IntervalNum: 1, Limit: 100, RateLimitType: RateLimitTypeRawRequests},
+	)
+	ctx := context.Background()
+	client := &Client{limiter: limiter}
+
+	order := &requestArgs{client: client, weight: 1, order: true}
+	if err := order.waitRateLimit(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	// ORDERS is exhausted, the other limits must keep their tokens
+	if err := order.waitRateLimit(ctx); !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("err = %v, want ErrRateLimited", err)
+	}
+
+	heavy := &requestArgs{client: client, weight: 9}
+	if err := heavy.waitRateLimit(ctx); err != nil {
+		t.Fatalf("weight 9 of 9 left: %v", err)
+	}
+
+	if err := heavy.waitRateLimit(ctx); !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("err = %v, want ErrRateLimited", err)
+	}
+
+	limiter.mu.Lock()
+	raw := limiter.buckets[RateLimitTypeRawRequests][0].tokens
+	limiter.mu.Unlock()
+	if raw < 97.9 || raw > 98.1 {
+		t.Errorf("RAW_REQUESTS tokens = %v, want 98 after two accepted requests", raw)
+	}
+}
+
+func TestDepthWeight(t *testing.T) {
+	tests := []struct {
+		limit int32
+		want  int
+	}{
+		{0, 1}, {100, 1}, {500, 5}, {1000, 10},
+	}
+
+	for _, tt := range tests {
+		if got := depthWeight(tt.limit); got != tt.want {
+			t.Errorf("depthWeight(%d) = %d, want %d", tt.limit, got, tt.want)
+		}
+	}
+}
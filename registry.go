@@ -0,0 +1,295 @@
+package currencycom
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// leverageSuffix marks the leveraged counterpart of a spot symbol, e.g.
+// BTC/USD_LEVERAGE for BTC/USD.
+const leverageSuffix = "_LEVERAGE"
+
+// SymbolRegistry keeps ExchangeInfo in memory and indexes its symbols.
+// Load it with Refresh or Load, or keep it current with Run. Lookups
+// return copies sorted by symbol name.
+type SymbolRegistry struct {
+	// OnRefresh is called with the new exchange info after every load,
+	// e.g. OrderValidator.SetExchangeInfo.
+	OnRefresh func(*ExchangeInfoResponse)
+
+	client *Client
+
+	mu          sync.RWMutex
+	info        *ExchangeInfoResponse
+	updatedAt   time.Time
+	symbols     []*ExchangeSymbolInfo // sorted by name
+	byName      map[string]*ExchangeSymbolInfo
+	byBase      map[string][]*ExchangeSymbolInfo
+	byQuote     map[string][]*ExchangeSymbolInfo
+	byAssetType map[AssetType][]*ExchangeSymbolInfo
+	byMarket    map[MarketType][]*ExchangeSymbolInfo
+	bySector    map[string][]*ExchangeSymbolInfo
+	byIndustry  map[string][]*ExchangeSymbolInfo
+	byCountry   map[string][]*ExchangeSymbolInfo
+}
+
+// NewSymbolRegistry creates an empty registry loading from client, nil
+// means the default client.
+func NewSymbolRegistry(client *Client) *SymbolRegistry {
+	if client == nil {
+		client = defaultClient()
+	}
+
+	return &SymbolRegistry{client: client}
+}
+
+// Refresh downloads ExchangeInfo and replaces the registry content.
+// On error the previous content is kept.
+func (s *SymbolRegistry) Refresh(ctx context.Context) error {
+	info, err := s.client.ExchangeInfoWithContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	s.Load(info)
+
+	return nil
+}
+
+// Run refreshes the registry every interval until ctx is done. Failed
+// refreshes are retried on the next tick.
+func (s *SymbolRegistry) Run(ctx context.Context, interval time.Duration) error {
+	if interval <= 0 {
+		return fmt.Errorf("error params: interval need to be positive")
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		_ = s.Refresh(ctx)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Load replaces the registry content with a copy of info, e.g. a saved
+// one, later changes of info don't reach the registry.
+func (s *SymbolRegistry) Load(info *ExchangeInfoResponse) {
+	copied := ExchangeInfoResponse{}
+	if info != nil {
+		copied = *info
+		copied.Symbols = append([]ExchangeSymbolInfo(nil), info.Symbols...)
+	}
+	info = &copied
+
+	byName := make(map[string]*ExchangeSymbolInfo, len(info.Symbols))
+	byBase := make(map[string][]*ExchangeSymbolInfo)
+	byQuote := make(map[string][]*ExchangeSymbolInfo)
+	byAssetType := make(map[AssetType][]*ExchangeSymbolInfo)
+	byMarket := make(map[MarketType][]*ExchangeSymbolInfo)
+	bySector := make(map[string][]*ExchangeSymbolInfo)
+	byIndustry := make(map[string][]*ExchangeSymbolInfo)
+	byCountry := make(map[string][]*ExchangeSymbolInfo)
+
+	symbols := make([]*ExchangeSymbolInfo, len(info.Symbols))
+	for i := range info.Symbols {
+		symbols[i] = &info.Symbols[i]
+	}
+	sort.Slice(symbols, func(i, j int) bool { return symbols[i].Symbol < symbols[j].Symbol })
+
+	for _, symbol := range symbols {
+		byName[symbol.Symbol] = symbol
+		byBase[symbol.BaseAsset] = append(byBase[symbol.BaseAsset], symbol)
+		byQuote[symbol.QuoteAsset] = append(byQuote[symbol.QuoteAsset], symbol)
+		byAssetType[symbol.AssetType] = append(byAssetType[symbol.AssetType], symbol)
+		byMarket[symbol.MarketType] = append(byMarket[symbol.MarketType], symbol)
+		bySector[symbol.Sector] = append(bySector[symbol.Sector], symbol)
+		byIndustry[symbol.Industry] = append(byIndustry[symbol.Industry], symbol)
+		byCountry[symbol.Country] = append(byCountry[symbol.Country], symbol)
+	}
+
+	s.mu.Lock()
+	s.info = info
+	s.updatedAt = time.Now()
+	s.symbols, s.byName = symbols, byName
+	s.byBase, s.byQuote = byBase, byQuote
+	s.byAssetType, s.byMarket = byAssetType, byMarket
+	s.bySector, s.byIndustry, s.byCountry = bySector, byIndustry, byCountry
+	s.mu.Unlock()
+
+	if s.OnRefresh != nil {
+		s.OnRefresh(info)
+	}
+}
+
+// Info returns the last loaded exchange info, nil before the first load.
+func (s *SymbolRegistry) Info() *ExchangeInfoResponse {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.info
+}
+
+// UpdatedAt returns the time of the last load, zero before the first one.
+func (s *SymbolRegistry) UpdatedAt() time.Time {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.updatedAt
+}
+
+// Symbol returns the symbol with exactly the name.
+func (s *SymbolRegistry) Symbol(name string) (ExchangeSymbolInfo, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	symbol, ok := s.byName[name]
+	if !ok {
+		return ExchangeSymbolInfo{}, false
+	}
+
+	return *symbol, true
+}
+
+// Validate checks that the symbol is well formed and listed by the
+// exchange, unknown symbols match ErrInvalidSymbol.
+func (s *SymbolRegistry) Validate(symbol Symbol) error {
+	if !symbol.IsValid() {
+		return fmt.Errorf("error params: invalid Symbol %q", symbol)
+	}
+
+	if _, ok := s.Symbol(string(symbol)); !ok {
+		return fmt.Errorf("%w: %s is not listed", ErrInvalidSymbol, symbol)
+	}
+
+	return nil
+}
+
+// Symbols returns all symbols.
+func (s *SymbolRegistry) Symbols() []ExchangeSymbolInfo {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return appendSymbols(nil, s.symbols)
+}
+
+func (s *SymbolRegistry) ByBaseAsset(asset string) []ExchangeSymbolInfo {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return appendSymbols(nil, s.byBase[asset])
+}
+
+func (s *SymbolRegistry) ByQuoteAsset(asset string) []ExchangeSymbolInfo {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return appendSymbols(nil, s.byQuote[asset])
+}
+
+func (s *SymbolRegistry) ByAssetType(assetType AssetType) []ExchangeSymbolInfo {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return appendSymbols(nil, s.byAssetType[assetType])
+}
+
+// ByMarketType returns the SPOT or the LEVERAGE symbols.
+func (s *SymbolRegistry) ByMarketType(marketType MarketType) []ExchangeSymbolInfo {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return appendSymbols(nil, s.byMarket[marketType])
+}
+
+func (s *SymbolRegistry) BySector(sector string) []ExchangeSymbolInfo {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return appendSymbols(nil, s.bySector[sector])
+}
+
+func (s *SymbolRegistry) ByIndustry(industry string) []ExchangeSymbolInfo {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return appendSymbols(nil, s.byIndustry[industry])
+}
+
+func (s *SymbolRegistry) ByCountry(country string) []ExchangeSymbolInfo {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return appendSymbols(nil, s.byCountry[country])
+}
+
+// Leverage resolves a symbol to its leveraged counterpart, the one
+// LeverageSettings and leverage orders expect: BTC/USD resolves to
+// BTC/USD_LEVERAGE. A leverage symbol resolves to itself.
+func (s *SymbolRegistry) Leverage(name string) (ExchangeSymbolInfo, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	symbol, ok := s.byName[name]
+	if ok && symbol.MarketType == MarketTypeLeverage {
+		return *symbol, true
+	}
+
+	if leverage, ok := s.byName[name+leverageSuffix]; ok {
+		return *leverage, true
+	}
+
+	// fall back to the leverage market of the same pair
+	if ok {
+		for _, leverage := range s.byBase[symbol.BaseAsset] {
+			if leverage.MarketType == MarketTypeLeverage && leverage.QuoteAsset == symbol.QuoteAsset {
+				return *leverage, true
+			}
+		}
+	}
+
+	return ExchangeSymbolInfo{}, false
+}
+
+// Spot resolves a leverage symbol to the spot market of the same pair:
+// BTC/USD_LEVERAGE resolves to BTC/USD. A spot symbol resolves to itself.
+func (s *SymbolRegistry) Spot(name string) (ExchangeSymbolInfo, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	symbol, ok := s.byName[name]
+	if ok && symbol.MarketType == MarketTypeSpot {
+		return *symbol, true
+	}
+
+	if spot, ok := s.byName[strings.TrimSuffix(name, leverageSuffix)]; ok && spot.MarketType == MarketTypeSpot {
+		return *spot, true
+	}
+
+	if ok {
+		for _, spot := range s.byBase[symbol.BaseAsset] {
+			if spot.MarketType == MarketTypeSpot && spot.QuoteAsset == symbol.QuoteAsset {
+				return *spot, true
+			}
+		}
+	}
+
+	return ExchangeSymbolInfo{}, false
+}
+
+func appendSymbols(out []ExchangeSymbolInfo, symbols []*ExchangeSymbolInfo) []ExchangeSymbolInfo {
+	for _, symbol := range symbols {
+		out = append(out, *symbol)
+	}
+
+	return out
+}
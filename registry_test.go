@@ -0,0 +1,171 @@
+package currencycom
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func registryInfo() *ExchangeInfoResponse {
+	return &ExchangeInfoResponse{Symbols: []ExchangeSymbolInfo{
+		{Symbol: "TSLA", BaseAsset: "TSLA", QuoteAsset: "USD", AssetType: AssetTypeEquity, MarketType: MarketTypeSpot, Sector: "Consumer Cyclical", Country: "US"},
+		{Symbol: "BTC/USD_LEVERAGE", BaseAsset: "BTC", QuoteAsset: "USD", AssetType: AssetTypeCryptocurrency, MarketType: MarketTypeLeverage},
+		{Symbol: "ETH/BTC", BaseAsset: "ETH", QuoteAsset: "BTC", AssetType: AssetTypeCryptocurrency, MarketType: MarketTypeSpot},
+		{Symbol: "Gold", BaseAsset: "XAU", QuoteAsset: "USD", AssetType: AssetTypeCommodity, MarketType: MarketTypeLeverage},
+		{Symbol: "BTC/USD", BaseAsset: "BTC", QuoteAsset: "USD", AssetType: AssetTypeCryptocurrency, MarketType: MarketTypeSpot},
+		{Symbol: "Tesla", BaseAsset: "TSLA", QuoteAsset: "USD", AssetType: AssetTypeEquity, MarketType: MarketTypeLeverage, Sector: "Consumer Cyclical", Industry: "Auto Manufacturers", Country: "US"},
+	}}
+}
+
+func symbolNames(symbols []ExchangeSymbolInfo) []string {
+	var out []string
+	for _, s := range symbols {
+		out = append(out, s.Symbol)
+	}
+
+	return out
+}
+
+func TestSymbolRegistryRunInterval(t *testing.T) {
+	registry := NewSymbolRegistry(nil)
+	if err := registry.Run(context.Background(), 0); err == nil {
+		t.Error("Run with zero interval: want error")
+	}
+}
+
+func TestSymbolRegistryIndexes(t *testing.T) {
+	registry := NewSymbolRegistry(nil)
+	registry.Load(registryInfo())
+
+	tests := []struct {
+		name string
+		got  []ExchangeSymbolInfo
+		want []string
+	}{
+		{"all", registry.Symbols(), []string{"BTC/USD", "BTC/USD_LEVERAGE", "ETH/BTC", "Gold", "TSLA", "Tesla"}},
+		{"base", registry.ByBaseAsset("BTC"), []string{"BTC/USD", "BTC/USD_LEVERAGE"}},
+		{"quote", registry.ByQuoteAsset("BTC"), []string{"ETH/BTC"}},
+		{"asset type", registry.ByAssetType(AssetTypeEquity), []string{"TSLA", "Tesla"}},
+		{"market type", registry.ByMarketType(MarketTypeLeverage), []string{"BTC/USD_LEVERAGE", "Gold", "Tesla"}},
+		{"sector", registry.BySector("Consumer Cyclical"), []string{"TSLA", "Tesla"}},
+		{"industry", registry.ByIndustry("Auto Manufacturers"), []string{"Tesla"}},
+		{"country", registry.ByCountry("US"), []string{"TSLA", "Tesla"}},
+		{"unknown", registry.ByBaseAsset("DOGE"), nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := symbolNames(tt.got); !equalStrings(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if s, ok := registry.Symbol("Gold"); !ok || s.BaseAsset != "XAU" {
+		t.Errorf("Symbol(Gold) = %+v, %v, want XAU", s, ok)
+	}
+	if _, ok := registry.Symbol("gold"); ok {
+		t.Error("Symbol(gold) found, want names matched exactly")
+	}
+}
+
+func TestSymbolRegistryLoadCopies(t *testing.T) {
+	info := registryInfo()
+
+	var refreshed *ExchangeInfoResponse
+	registry := NewSymbolRegistry(nil)
+	registry.OnRefresh = func(info *ExchangeInfoResponse) { refreshed = info }
+	registry.Load(info)
+
+	// the caller reuses its slice
+	info.Symbols[0].Symbol = "NFLX"
+	info.Symbols[0].MarketType = MarketTypeLeverage
+
+	if s, ok := registry.Symbol("TSLA"); !ok || s.MarketType != MarketTypeSpot {
+		t.Errorf("Symbol(TSLA) = %+v, %v, want the spot symbol as loaded", s, ok)
+	}
+	if _, ok := registry.Symbol("NFLX"); ok {
+		t.Error("Symbol(NFLX) found, want the change of the caller ignored")
+	}
+	if got := registry.Info().Symbols[0].Symbol; got != "TSLA" {
+		t.Errorf("Info().Symbols[0] = %s, want TSLA", got)
+	}
+	if refreshed != registry.Info() {
+		t.Error("OnRefresh got another info than Info returns")
+	}
+
+	// lookups are copies too
+	registry.Symbols()[0].Symbol = "changed"
+	if _, ok := registry.Symbol("BTC/USD"); !ok {
+		t.Error("Symbol(BTC/USD) lost after a change of a lookup")
+	}
+
+	registry.Load(nil)
+	if n := len(registry.Symbols()); n != 0 {
+		t.Errorf("got %d symbols after Load(nil), want 0", n)
+	}
+}
+
+func TestSymbolRegistryResolve(t *testing.T) {
+	registry := NewSymbolRegistry(nil)
+	registry.Load(registryInfo())
+
+	tests := []struct {
+		name     string
+		leverage string
+		spot     string
+	}{
+		{name: "BTC/USD", leverage: "BTC/USD_LEVERAGE", spot: "BTC/USD"},
+		{name: "BTC/USD_LEVERAGE", leverage: "BTC/USD_LEVERAGE", spot: "BTC/USD"},
+		// another name for the same pair
+		{name: "TSLA", leverage: "Tesla", spot: "TSLA"},
+		{name: "Tesla", leverage: "Tesla", spot: "TSLA"},
+		// one market only
+		{name: "ETH/BTC", spot: "ETH/BTC"},
+		{name: "Gold", leverage: "Gold"},
+		{name: "DOGE/USD"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			leverage, ok := registry.Leverage(tt.name)
+			if ok != (tt.leverage != "") || leverage.Symbol != tt.leverage {
+				t.Errorf("Leverage(%s) = %q, %v, want %q", tt.name, leverage.Symbol, ok, tt.leverage)
+			}
+
+			spot, ok := registry.Spot(tt.name)
+			if ok != (tt.spot != "") || spot.Symbol != tt.spot {
+				t.Errorf("Spot(%s) = %q, %v, want %q", tt.name, spot.Symbol, ok, tt.spot)
+			}
+		})
+	}
+}
+
+func TestSymbolRegistryValidate(t *testing.T) {
+	registry := NewSymbolRegistry(nil)
+	registry.Load(registryInfo())
+
+	tests := []struct {
+		symbol   Symbol
+		err      bool
+		unlisted bool
+	}{
+		{symbol: "BTC/USD"},
+		{symbol: "Gold"},
+		{symbol: "DOGE/USD", err: true, unlisted: true},
+		{symbol: "", err: true},
+		{symbol: " Gold", err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.symbol), func(t *testing.T) {
+			err := registry.Validate(tt.symbol)
+			if (err != nil) != tt.err {
+				t.Fatalf("Validate(%q) = %v, want error %v", tt.symbol, err, tt.err)
+			}
+			if errors.Is(err, ErrInvalidSymbol) != tt.unlisted {
+				t.Errorf("errors.Is(%v, ErrInvalidSymbol) = %v, want %v", err, !tt.unlisted, tt.unlisted)
+			}
+		})
+	}
+}
@@ -1,10 +1,12 @@
 package currencycom
 
 import (
+	"context"
 	"crypto/hmac"
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
@@ -14,17 +16,14 @@ import (
 
 const DEFAULT_ENDPOINT string = "https://api-adapter.backend.currency.com"
 const VERSION_API string = "v2"
-
-var baseEndpoint string
-
-func init() {
-	baseEndpoint = DEFAULT_ENDPOINT
-}
+const DEFAULT_TIMEOUT time.Duration = 30 * time.Second
 
 type RestAPI struct {
-	apiKey   string
-	secret   string
-	Endpoint string
+	Client
+	apiKey    string
+	secret    string
+	clock     *serverClock
+	validator *OrderValidator
 }
 
 type requestArgs struct {
@@ -33,13 +32,48 @@ type requestArgs struct {
 	methodName string
 	params     map[string]string
 	restApi    *RestAPI
+	client     *Client
+	weight     int  // REQUEST_WEIGHT of the endpoint, 0 means 1
+	order      bool // counts against the ORDERS rate limit
+}
+
+func request(ctx context.Context, args *requestArgs) ([]byte, error) {
+	if args.client == nil {
+		args.client = defaultClient()
+	}
+	policy := &args.client.retry
+
+	resynced := false
+	for attempt := 1; ; attempt++ {
+		body, err := doRequest(ctx, args)
+
+		// the request was rejected before processing, so it is safe to
+		// repeat it once with a fresh clock offset
+		if !resynced && args.restApi != nil && args.restApi.clock != nil && errors.Is(err, ErrInvalidTimestamp) {
+			resynced = true
+			if args.restApi.clock.sync(ctx, args.restApi) == nil {
+				body, err = doRequest(ctx, args)
+			}
+		}
+
+		if err == nil || attempt >= policy.MaxAttempts || !policy.canRetry(args.httpMethod) || !isRetryable(ctx, err) {
+			return body, err
+		}
+
+		if err := sleep(ctx, policy.delay(attempt, err)); err != nil {
+			return body, err
+		}
+	}
 }
 
-func request(args *requestArgs) ([]byte, error) {
+func doRequest(ctx context.Context, args *requestArgs) ([]byte, error) {
+	if err := args.waitRateLimit(ctx); err != nil {
+		return nil, err
+	}
+
 	url := args.endpoint + "/api/" + VERSION_API + "/" + args.methodName
 
-	client := &http.Client{}
-	req, err := http.NewRequest(args.httpMethod, url, nil)
+	req, err := http.NewRequestWithContext(ctx, args.httpMethod, url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("error in prepare request, %w", err)
 	}
@@ -52,7 +86,12 @@ func request(args *requestArgs) ([]byte, error) {
 	if args.restApi != nil {
 		req.Header.Set("X-MBX-APIKEY", args.restApi.apiKey)
 
-		query.Add("timestamp", strconv.Itoa(int(time.Now().UnixMilli())))
+		now := time.Now()
+		if args.restApi.clock != nil {
+			now = args.restApi.clock.now(ctx, args.restApi)
+		}
+
+		query.Add("timestamp", strconv.Itoa(int(now.UnixMilli())))
 
 		sig := hmac.New(sha256.New, []byte(args.restApi.secret))
 		sig.Write([]byte(query.Encode()))
@@ -60,11 +99,17 @@ func request(args *requestArgs) ([]byte, error) {
 	}
 
 	req.URL.RawQuery = query.Encode()
-	resp, err := client.Do(req)
+	httpClient := args.client.httpClient
+	if httpClient == nil {
+		httpClient = defaultClient().httpClient
+	}
+
+	resp, err := httpClient.Do(req)
 
 	if err != nil {
 		return nil, fmt.Errorf("error in call, %w", err)
 	}
+	defer resp.Body.Close()
 
 	body, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
@@ -72,19 +117,25 @@ func request(args *requestArgs) ([]byte, error) {
 	}
 
 	if resp.StatusCode >= 400 {
-		return body, fmt.Errorf("bad response from server, " + resp.Status + ": " + string(body))
+		return body, newAPIError(resp, body)
 	}
 
 	return body, nil
 }
 
-func ServerTime() (*ServerTimeResponse, error) {
-	body, err := request(&requestArgs{
+func (c Client) ServerTime() (*ServerTimeResponse, error) {
+	return c.ServerTimeWithContext(context.Background())
+}
+
+func (c Client) ServerTimeWithContext(ctx context.Context) (*ServerTimeResponse, error) {
+	body, err := request(ctx, &requestArgs{
 		httpMethod: "GET",
-		endpoint:   baseEndpoint,
+		endpoint:   c.Endpoint,
 		methodName: "time",
+		weight:     1,
 		params:     nil,
 		restApi:    nil,
+		client:     &c,
 	})
 	if err != nil {
 		return nil, err
@@ -96,13 +147,21 @@ func ServerTime() (*ServerTimeResponse, error) {
 	return &out, err
 }
 
-func TradesAggregated(params *AggTradesRequest) ([]AggTrades, error) {
+func (c Client) TradesAggregated(params *AggTradesRequest) ([]AggTrades, error) {
+	return c.TradesAggregatedWithContext(context.Background(), params)
+}
+
+func (c Client) TradesAggregatedWithContext(ctx context.Context, params *AggTradesRequest) ([]AggTrades, error) {
 	if params == nil || params.Symbol == "" {
 		return nil, fmt.Errorf("error params: Symbol need to set")
 	}
 
+	if !params.Symbol.IsValid() {
+		return nil, fmt.Errorf("error params: invalid Symbol %q", params.Symbol)
+	}
+
 	reqParams := map[string]string{
-		"symbol": params.Symbol,
+		"symbol": string(params.Symbol),
 	}
 
 	if params.EndTime != 0 {
@@ -117,12 +176,14 @@ func TradesAggregated(params *AggTradesRequest) ([]AggTrades, error) {
 		reqParams["startTime"] = strconv.FormatUint(uint64(params.StartTime), 10)
 	}
 
-	body, err := request(&requestArgs{
+	body, err := request(ctx, &requestArgs{
 		httpMethod: "GET",
-		endpoint:   baseEndpoint,
+		endpoint:   c.Endpoint,
 		methodName: "aggTrades",
+		weight:     1,
 		params:     reqParams,
 		restApi:    nil,
+		client:     &c,
 	})
 	if err != nil {
 		return nil, err
@@ -134,25 +195,35 @@ func TradesAggregated(params *AggTradesRequest) ([]AggTrades, error) {
 	return out, err
 }
 
-func OrderBook(params *DepthRequest) (*DepthResponse, error) {
+func (c Client) OrderBook(params *DepthRequest) (*DepthResponse, error) {
+	return c.OrderBookWithContext(context.Background(), params)
+}
+
+func (c Client) OrderBookWithContext(ctx context.Context, params *DepthRequest) (*DepthResponse, error) {
 	if params == nil || params.Symbol == "" {
 		return nil, fmt.Errorf("error params: Symbol need to set")
 	}
 
+	if !params.Symbol.IsValid() {
+		return nil, fmt.Errorf("error params: invalid Symbol %q", params.Symbol)
+	}
+
 	reqParams := map[string]string{
-		"symbol": params.Symbol,
+		"symbol": string(params.Symbol),
 	}
 
 	if params.Limit != 0 {
 		reqParams["limit"] = strconv.FormatUint(uint64(params.Limit), 10)
 	}
 
-	body, err := request(&requestArgs{
+	body, err := request(ctx, &requestArgs{
 		httpMethod: "GET",
-		endpoint:   baseEndpoint,
+		endpoint:   c.Endpoint,
 		methodName: "depth",
+		weight:     depthWeight(params.Limit),
 		params:     reqParams,
 		restApi:    nil,
+		client:     &c,
 	})
 	if err != nil {
 		return nil, err
@@ -164,13 +235,19 @@ func OrderBook(params *DepthRequest) (*DepthResponse, error) {
 	return &out, err
 }
 
-func ExchangeInfo() (*ExchangeInfoResponse, error) {
-	body, err := request(&requestArgs{
+func (c Client) ExchangeInfo() (*ExchangeInfoResponse, error) {
+	return c.ExchangeInfoWithContext(context.Background())
+}
+
+func (c Client) ExchangeInfoWithContext(ctx context.Context) (*ExchangeInfoResponse, error) {
+	body, err := request(ctx, &requestArgs{
 		httpMethod: "GET",
-		endpoint:   baseEndpoint,
+		endpoint:   c.Endpoint,
 		methodName: "exchangeInfo",
+		weight:     1,
 		params:     nil,
 		restApi:    nil,
+		client:     &c,
 	})
 	if err != nil {
 		return nil, err
@@ -182,14 +259,30 @@ func ExchangeInfo() (*ExchangeInfoResponse, error) {
 	return &out, err
 }
 
-func Klines(params *KLinesRequest) ([]KLinesResponseStruct, error) {
+func (c Client) Klines(params *KLinesRequest) ([]Kline, error) {
+	return c.KlinesWithContext(context.Background(), params)
+}
+
+func (c Client) KlinesWithContext(ctx context.Context, params *KLinesRequest) ([]Kline, error) {
 	if params == nil {
 		return nil, fmt.Errorf("error params: Symbol and Interval need to set")
 	}
 
+	if !params.Symbol.IsValid() {
+		return nil, fmt.Errorf("error params: invalid Symbol %q", params.Symbol)
+	}
+
+	if !params.Interval.IsValid() {
+		return nil, fmt.Errorf("error params: unknown Interval %q", params.Interval)
+	}
+
+	if params.Type != "" && !params.Type.IsValid() {
+		return nil, fmt.Errorf("error params: unknown Type %q", params.Type)
+	}
+
 	reqParams := map[string]string{
-		"symbol":   params.Symbol,
-		"interval": params.Interval,
+		"symbol":   string(params.Symbol),
+		"interval": string(params.Interval),
 	}
 
 	if params.StartTime != 0 {
@@ -205,53 +298,60 @@ func Klines(params *KLinesRequest) ([]KLinesResponseStruct, error) {
 	}
 
 	if params.Type != "" {
-		reqParams["type"] = params.Type
+		reqParams["type"] = string(params.Type)
 	}
 
-	body, err := request(&requestArgs{
+	body, err := request(ctx, &requestArgs{
 		httpMethod: "GET",
-		endpoint:   baseEndpoint,
+		endpoint:   c.Endpoint,
 		methodName: "klines",
+		weight:     1,
 		params:     reqParams,
 		restApi:    nil,
+		client:     &c,
 	})
 	if err != nil {
 		return nil, err
 	}
 
-	var middle [][6]interface{}
-	err = json.Unmarshal(body, &middle)
+	var out []Kline
+	if err := json.Unmarshal(body, &out); err != nil {
+		return nil, err
+	}
 
-	out := make([]KLinesResponseStruct, len(middle))
-	for _, line := range middle {
-		out = append(out, KLinesResponseStruct{
-			OpenTime: line[0].(float64),
-			Open:     line[1].(string),
-			High:     line[2].(string),
-			Low:      line[3].(string),
-			Close:    line[4].(string),
-			Volume:   line[5].(float64),
-		})
+	d := params.Interval.Duration()
+	for i := range out {
+		out[i].CloseTime = out[i].OpenTime.Add(d - time.Millisecond)
 	}
 
-	return out, err
+	return out, nil
+}
+
+func (c Client) PriceChange(params *BySymbolRequest) (*Ticker24hr, error) {
+	return c.PriceChangeWithContext(context.Background(), params)
 }
 
-func PriceChange(params *BySymbolRequest) (*Ticker24hr, error) {
+func (c Client) PriceChangeWithContext(ctx context.Context, params *BySymbolRequest) (*Ticker24hr, error) {
 	if params == nil || params.Symbol == "" {
 		return nil, fmt.Errorf("error params: Symbol need to set")
 	}
 
+	if !params.Symbol.IsValid() {
+		return nil, fmt.Errorf("error params: invalid Symbol %q", params.Symbol)
+	}
+
 	reqParams := map[string]string{
-		"symbol": params.Symbol,
+		"symbol": string(params.Symbol),
 	}
 
-	body, err := request(&requestArgs{
+	body, err := request(ctx, &requestArgs{
 		httpMethod: "GET",
-		endpoint:   baseEndpoint,
+		endpoint:   c.Endpoint,
 		methodName: "ticker/24hr",
+		weight:     1,
 		params:     reqParams,
 		restApi:    nil,
+		client:     &c,
 	})
 	if err != nil {
 		return nil, err
@@ -263,15 +363,20 @@ func PriceChange(params *BySymbolRequest) (*Ticker24hr, error) {
 	return &out, err
 }
 
-func NewRestAPI(apiKey string, secret string, endpoint string) *RestAPI {
-	if endpoint == "" {
-		endpoint = baseEndpoint
+func NewRestAPI(apiKey string, secret string, endpoint string, opts ...Option) *RestAPI {
+	api := &RestAPI{Client: newClient(endpoint), apiKey: apiKey, secret: secret}
+	for _, opt := range opts {
+		opt(api)
 	}
 
-	return &RestAPI{apiKey: apiKey, secret: secret, Endpoint: endpoint}
+	return api
 }
 
 func (r RestAPI) AccountInfo(params *AccountRequest) (*AccountResponse, error) {
+	return r.AccountInfoWithContext(context.Background(), params)
+}
+
+func (r RestAPI) AccountInfoWithContext(ctx context.Context, params *AccountRequest) (*AccountResponse, error) {
 	var reqParams map[string]string
 
 	if params != nil {
@@ -284,12 +389,14 @@ func (r RestAPI) AccountInfo(params *AccountRequest) (*AccountResponse, error) {
 		}
 	}
 
-	body, err := request(&requestArgs{
+	body, err := request(ctx, &requestArgs{
 		httpMethod: "GET",
 		endpoint:   r.Endpoint,
 		methodName: "account",
+		weight:     5,
 		params:     reqParams,
 		restApi:    &r,
+		client:     &r.Client,
 	})
 	if err != nil {
 		return nil, err
@@ -303,6 +410,10 @@ func (r RestAPI) AccountInfo(params *AccountRequest) (*AccountResponse, error) {
 }
 
 func (r RestAPI) TradingPositionClose(params *CloseTradingPositionRequest) (*TradingPositionCloseAllResponse, error) {
+	return r.TradingPositionCloseWithContext(context.Background(), params)
+}
+
+func (r RestAPI) TradingPositionCloseWithContext(ctx context.Context, params *CloseTradingPositionRequest) (*TradingPositionCloseAllResponse, error) {
 	if params == nil || params.PositionId == "" {
 		return nil, fmt.Errorf("error params: PositionId need to set")
 	}
@@ -315,12 +426,15 @@ func (r RestAPI) TradingPositionClose(params *CloseTradingPositionRequest) (*Tra
 		reqParams["recvWindow"] = strconv.FormatUint(uint64(params.RecvWindow), 10)
 	}
 
-	body, err := request(&requestArgs{
+	body, err := request(ctx, &requestArgs{
 		httpMethod: "POST",
 		endpoint:   r.Endpoint,
 		methodName: "closeTradingPosition",
+		weight:     1,
 		params:     reqParams,
 		restApi:    &r,
+		client:     &r.Client,
+		order:      true,
 	})
 	if err != nil {
 		return nil, err
@@ -334,6 +448,10 @@ func (r RestAPI) TradingPositionClose(params *CloseTradingPositionRequest) (*Tra
 }
 
 func (r RestAPI) ListOfCurrencies(params *SignedRequest) ([]CurrencyDtoResponse, error) {
+	return r.ListOfCurrenciesWithContext(context.Background(), params)
+}
+
+func (r RestAPI) ListOfCurrenciesWithContext(ctx context.Context, params *SignedRequest) ([]CurrencyDtoResponse, error) {
 	var reqParams map[string]string
 
 	if params != nil {
@@ -342,12 +460,14 @@ func (r RestAPI) ListOfCurrencies(params *SignedRequest) ([]CurrencyDtoResponse,
 		}
 	}
 
-	body, err := request(&requestArgs{
+	body, err := request(ctx, &requestArgs{
 		httpMethod: "GET",
 		endpoint:   r.Endpoint,
 		methodName: "currencies",
+		weight:     1,
 		params:     reqParams,
 		restApi:    &r,
+		client:     &r.Client,
 	})
 	if err != nil {
 		return nil, err
@@ -361,6 +481,10 @@ func (r RestAPI) ListOfCurrencies(params *SignedRequest) ([]CurrencyDtoResponse,
 }
 
 func (r RestAPI) StringOfAddress(params *BlockchainAddressRequest) (*BlockchainAddressGetResponse, error) {
+	return r.StringOfAddressWithContext(context.Background(), params)
+}
+
+func (r RestAPI) StringOfAddressWithContext(ctx context.Context, params *BlockchainAddressRequest) (*BlockchainAddressGetResponse, error) {
 	if params == nil || params.Coin == "" {
 		return nil, fmt.Errorf("error params: Coin need to set")
 	}
@@ -373,12 +497,14 @@ func (r RestAPI) StringOfAddress(params *BlockchainAddressRequest) (*BlockchainA
 		reqParams["recvWindow"] = strconv.FormatUint(uint64(params.RecvWindow), 10)
 	}
 
-	body, err := request(&requestArgs{
+	body, err := request(ctx, &requestArgs{
 		httpMethod: "GET",
 		endpoint:   r.Endpoint,
 		methodName: "depositAddress",
+		weight:     1,
 		params:     reqParams,
 		restApi:    &r,
+		client:     &r.Client,
 	})
 	if err != nil {
 		return nil, err
@@ -392,6 +518,10 @@ func (r RestAPI) StringOfAddress(params *BlockchainAddressRequest) (*BlockchainA
 }
 
 func (r RestAPI) ListOfDeposits(params *TransactionsRequest) ([]TransactionDTOResponse, error) {
+	return r.ListOfDepositsWithContext(context.Background(), params)
+}
+
+func (r RestAPI) ListOfDepositsWithContext(ctx context.Context, params *TransactionsRequest) ([]TransactionDTOResponse, error) {
 	var reqParams map[string]string
 
 	if params != nil {
@@ -414,12 +544,14 @@ func (r RestAPI) ListOfDeposits(params *TransactionsRequest) ([]TransactionDTORe
 		}
 	}
 
-	body, err := request(&requestArgs{
+	body, err := request(ctx, &requestArgs{
 		httpMethod: "GET",
 		endpoint:   r.Endpoint,
 		methodName: "deposits",
+		weight:     1,
 		params:     reqParams,
 		restApi:    &r,
+		client:     &r.Client,
 	})
 	if err != nil {
 		return nil, err
@@ -433,6 +565,10 @@ func (r RestAPI) ListOfDeposits(params *TransactionsRequest) ([]TransactionDTORe
 }
 
 func (r RestAPI) ListOfLedgers(params *TransactionsRequest) ([]TransactionDTOResponse, error) {
+	return r.ListOfLedgersWithContext(context.Background(), params)
+}
+
+func (r RestAPI) ListOfLedgersWithContext(ctx context.Context, params *TransactionsRequest) ([]TransactionDTOResponse, error) {
 	var reqParams map[string]string
 
 	if params != nil {
@@ -455,12 +591,14 @@ func (r RestAPI) ListOfLedgers(params *TransactionsRequest) ([]TransactionDTORes
 		}
 	}
 
-	body, err := request(&requestArgs{
+	body, err := request(ctx, &requestArgs{
 		httpMethod: "GET",
 		endpoint:   r.Endpoint,
 		methodName: "ledger",
+		weight:     1,
 		params:     reqParams,
 		restApi:    &r,
+		client:     &r.Client,
 	})
 	if err != nil {
 		return nil, err
@@ -474,24 +612,34 @@ func (r RestAPI) ListOfLedgers(params *TransactionsRequest) ([]TransactionDTORes
 }
 
 func (r RestAPI) LeverageSettings(params *LeverageSettingsRequest) (*LeverageSettingsResponse, error) {
+	return r.LeverageSettingsWithContext(context.Background(), params)
+}
+
+func (r RestAPI) LeverageSettingsWithContext(ctx context.Context, params *LeverageSettingsRequest) (*LeverageSettingsResponse, error) {
 	if params == nil || params.Symbol == "" {
 		return nil, fmt.Errorf("error params: Symbol need to set")
 	}
 
+	if !params.Symbol.IsValid() {
+		return nil, fmt.Errorf("error params: invalid Symbol %q", params.Symbol)
+	}
+
 	reqParams := map[string]string{
-		"symbol": params.Symbol,
+		"symbol": string(params.Symbol),
 	}
 
 	if params.RecvWindow != 0 {
 		reqParams["recvWindow"] = strconv.FormatUint(uint64(params.RecvWindow), 10)
 	}
 
-	body, err := request(&requestArgs{
+	body, err := request(ctx, &requestArgs{
 		httpMethod: "GET",
 		endpoint:   r.Endpoint,
 		methodName: "leverageSettings",
+		weight:     1,
 		params:     reqParams,
 		restApi:    &r,
+		client:     &r.Client,
 	})
 	if err != nil {
 		return nil, err
@@ -505,12 +653,20 @@ func (r RestAPI) LeverageSettings(params *LeverageSettingsRequest) (*LeverageSet
 }
 
 func (r RestAPI) ListOfTrades(params *AllMyTradesRequest) ([]MyTradesResponse, error) {
+	return r.ListOfTradesWithContext(context.Background(), params)
+}
+
+func (r RestAPI) ListOfTradesWithContext(ctx context.Context, params *AllMyTradesRequest) ([]MyTradesResponse, error) {
 	if params == nil || params.Symbol == "" {
 		return nil, fmt.Errorf("error params: Symbol need to set")
 	}
 
+	if !params.Symbol.IsValid() {
+		return nil, fmt.Errorf("error params: invalid Symbol %q", params.Symbol)
+	}
+
 	reqParams := map[string]string{
-		"symbol": params.Symbol,
+		"symbol": string(params.Symbol),
 	}
 
 	if params.StartTime != 0 {
@@ -529,12 +685,14 @@ func (r RestAPI) ListOfTrades(params *AllMyTradesRequest) ([]MyTradesResponse, e
 		reqParams["recvWindow"] = strconv.FormatUint(uint64(params.RecvWindow), 10)
 	}
 
-	body, err := request(&requestArgs{
+	body, err := request(ctx, &requestArgs{
 		httpMethod: "GET",
 		endpoint:   r.Endpoint,
 		methodName: "myTrades",
+		weight:     5,
 		params:     reqParams,
 		restApi:    &r,
+		client:     &r.Client,
 	})
 	if err != nil {
 		return nil, err
@@ -548,13 +706,21 @@ func (r RestAPI) ListOfTrades(params *AllMyTradesRequest) ([]MyTradesResponse, e
 }
 
 func (r RestAPI) ListOfOpenOrder(params *PositionHistoryRequest) ([]QueryOrderResponse, error) {
+	return r.ListOfOpenOrderWithContext(context.Background(), params)
+}
+
+func (r RestAPI) ListOfOpenOrderWithContext(ctx context.Context, params *PositionHistoryRequest) ([]QueryOrderResponse, error) {
 	var reqParams map[string]string
 
 	if params != nil {
 		reqParams = make(map[string]string)
 
 		if params.Symbol != "" {
-			reqParams["symbol"] = params.Symbol
+			if !params.Symbol.IsValid() {
+				return nil, fmt.Errorf("error params: invalid Symbol %q", params.Symbol)
+			}
+
+			reqParams["symbol"] = string(params.Symbol)
 		}
 
 		if params.RecvWindow != 0 {
@@ -562,12 +728,20 @@ func (r RestAPI) ListOfOpenOrder(params *PositionHistoryRequest) ([]QueryOrderRe
 		}
 	}
 
-	body, err := request(&requestArgs{
+	// all symbols at once cost much more
+	weight := 40
+	if reqParams["symbol"] != "" {
+		weight = 1
+	}
+
+	body, err := request(ctx, &requestArgs{
 		httpMethod: "GET",
 		endpoint:   r.Endpoint,
 		methodName: "openOrders",
+		weight:     weight,
 		params:     reqParams,
 		restApi:    &r,
+		client:     &r.Client,
 	})
 	if err != nil {
 		return nil, err
@@ -581,15 +755,40 @@ func (r RestAPI) ListOfOpenOrder(params *PositionHistoryRequest) ([]QueryOrderRe
 }
 
 func (r RestAPI) CreateOrder(params *CreateOrderRequest) (*NewOrderResponseRESULT, error) {
+	return r.CreateOrderWithContext(context.Background(), params)
+}
+
+func (r RestAPI) CreateOrderWithContext(ctx context.Context, params *CreateOrderRequest) (*NewOrderResponseRESULT, error) {
 	if params == nil {
 		return nil, fmt.Errorf("error params: Symbol, Quantity, Side, Type need to set")
 	}
 
+	if !params.Symbol.IsValid() {
+		return nil, fmt.Errorf("error params: invalid Symbol %q", params.Symbol)
+	}
+
+	if !params.Side.IsValid() {
+		return nil, fmt.Errorf("error params: unknown Side %q", params.Side)
+	}
+
+	if !params.Type.IsValid() {
+		return nil, fmt.Errorf("error params: unknown Type %q", params.Type)
+	}
+
+	if r.validator != nil {
+		// the validator may round the fields, keep the caller's request intact
+		checked := *params
+		if err := r.validator.ValidateOrder(&checked); err != nil {
+			return nil, err
+		}
+		params = &checked
+	}
+
 	reqParams := map[string]string{
-		"symbol":   params.Symbol,
-		"quantity": strconv.FormatFloat(params.Quantity, 'f', 8, 64),
-		"side":     params.Side,
-		"type":     params.Type,
+		"symbol":   string(params.Symbol),
+		"quantity": params.Quantity.String(),
+		"side":     string(params.Side),
+		"type":     string(params.Type),
 	}
 
 	if params.AccountId != 0 {
@@ -612,28 +811,31 @@ func (r RestAPI) CreateOrder(params *CreateOrderRequest) (*NewOrderResponseRESUL
 		reqParams["newOrderRespType"] = params.NewOrderRespType
 	}
 
-	if params.Price != 0 {
-		reqParams["price"] = strconv.FormatFloat(params.Price, 'f', 8, 64)
+	if !params.Price.IsZero() {
+		reqParams["price"] = params.Price.String()
 	}
 
 	if params.RecvWindow != 0 {
 		reqParams["recvWindow"] = strconv.FormatUint(uint64(params.RecvWindow), 10)
 	}
 
-	if params.StopLoss != 0 {
-		reqParams["stopLoss"] = strconv.FormatFloat(params.StopLoss, 'f', 8, 64)
+	if !params.StopLoss.IsZero() {
+		reqParams["stopLoss"] = params.StopLoss.String()
 	}
 
-	if params.TakeProfit != 0 {
-		reqParams["takeProfit"] = strconv.FormatFloat(params.TakeProfit, 'f', 8, 64)
+	if !params.TakeProfit.IsZero() {
+		reqParams["takeProfit"] = params.TakeProfit.String()
 	}
 
-	body, err := request(&requestArgs{
+	body, err := request(ctx, &requestArgs{
 		httpMethod: "POST",
 		endpoint:   r.Endpoint,
 		methodName: "order",
+		weight:     1,
 		params:     reqParams,
 		restApi:    &r,
+		client:     &r.Client,
+		order:      true,
 	})
 	if err != nil {
 		return nil, err
@@ -647,12 +849,20 @@ func (r RestAPI) CreateOrder(params *CreateOrderRequest) (*NewOrderResponseRESUL
 }
 
 func (r RestAPI) CancelOrder(params *CancelOrderRequest) (*CancelOrderResponse, error) {
+	return r.CancelOrderWithContext(context.Background(), params)
+}
+
+func (r RestAPI) CancelOrderWithContext(ctx context.Context, params *CancelOrderRequest) (*CancelOrderResponse, error) {
 	if params == nil {
 		return nil, fmt.Errorf("error params: Symbol, OrderId need to set")
 	}
 
+	if !params.Symbol.IsValid() {
+		return nil, fmt.Errorf("error params: invalid Symbol %q", params.Symbol)
+	}
+
 	reqParams := map[string]string{
-		"symbol":  params.Symbol,
+		"symbol":  string(params.Symbol),
 		"orderId": params.OrderId,
 	}
 
@@ -660,12 +870,15 @@ func (r RestAPI) CancelOrder(params *CancelOrderRequest) (*CancelOrderResponse,
 		reqParams["recvWindow"] = strconv.FormatUint(uint64(params.RecvWindow), 10)
 	}
 
-	body, err := request(&requestArgs{
+	body, err := request(ctx, &requestArgs{
 		httpMethod: "DELETE",
 		endpoint:   r.Endpoint,
 		methodName: "order",
+		weight:     1,
 		params:     reqParams,
 		restApi:    &r,
+		client:     &r.Client,
+		order:      true,
 	})
 	if err != nil {
 		return nil, err
@@ -679,6 +892,10 @@ func (r RestAPI) CancelOrder(params *CancelOrderRequest) (*CancelOrderResponse,
 }
 
 func (r RestAPI) ListOfLeverageTrades(params *SignedRequest) (*TradingPositionListResponse, error) {
+	return r.ListOfLeverageTradesWithContext(context.Background(), params)
+}
+
+func (r RestAPI) ListOfLeverageTradesWithContext(ctx context.Context, params *SignedRequest) (*TradingPositionListResponse, error) {
 	var reqParams map[string]string
 
 	if params != nil && params.RecvWindow != 0 {
@@ -686,12 +903,14 @@ func (r RestAPI) ListOfLeverageTrades(params *SignedRequest) (*TradingPositionLi
 		reqParams["recvWindow"] = strconv.FormatUint(uint64(params.RecvWindow), 10)
 	}
 
-	body, err := request(&requestArgs{
+	body, err := request(ctx, &requestArgs{
 		httpMethod: "GET",
 		endpoint:   r.Endpoint,
 		methodName: "tradingPositions",
+		weight:     1,
 		params:     reqParams,
 		restApi:    &r,
+		client:     &r.Client,
 	})
 	if err != nil {
 		return nil, err
@@ -705,6 +924,10 @@ func (r RestAPI) ListOfLeverageTrades(params *SignedRequest) (*TradingPositionLi
 }
 
 func (r RestAPI) ListOfHistoricalPositions(params *PositionHistoryRequest) (*TradingPositionHistoryResponse, error) {
+	return r.ListOfHistoricalPositionsWithContext(context.Background(), params)
+}
+
+func (r RestAPI) ListOfHistoricalPositionsWithContext(ctx context.Context, params *PositionHistoryRequest) (*TradingPositionHistoryResponse, error) {
 	var reqParams map[string]string
 
 	if params != nil {
@@ -715,7 +938,11 @@ func (r RestAPI) ListOfHistoricalPositions(params *PositionHistoryRequest) (*Tra
 		}
 
 		if params.Symbol != "" {
-			reqParams["symbol"] = params.Symbol
+			if !params.Symbol.IsValid() {
+				return nil, fmt.Errorf("error params: invalid Symbol %q", params.Symbol)
+			}
+
+			reqParams["symbol"] = string(params.Symbol)
 		}
 
 		if params.Limit != 0 {
@@ -723,12 +950,14 @@ func (r RestAPI) ListOfHistoricalPositions(params *PositionHistoryRequest) (*Tra
 		}
 	}
 
-	body, err := request(&requestArgs{
+	body, err := request(ctx, &requestArgs{
 		httpMethod: "GET",
 		endpoint:   r.Endpoint,
 		methodName: "tradingPositionsHistory",
+		weight:     1,
 		params:     reqParams,
 		restApi:    &r,
+		client:     &r.Client,
 	})
 	if err != nil {
 		return nil, err
@@ -742,6 +971,10 @@ func (r RestAPI) ListOfHistoricalPositions(params *PositionHistoryRequest) (*Tra
 }
 
 func (r RestAPI) ListOfTransactions(params *TransactionsRequest) ([]TransactionDTOResponse, error) {
+	return r.ListOfTransactionsWithContext(context.Background(), params)
+}
+
+func (r RestAPI) ListOfTransactionsWithContext(ctx context.Context, params *TransactionsRequest) ([]TransactionDTOResponse, error) {
 	var reqParams map[string]string
 
 	if params != nil {
@@ -764,12 +997,14 @@ func (r RestAPI) ListOfTransactions(params *TransactionsRequest) ([]TransactionD
 		}
 	}
 
-	body, err := request(&requestArgs{
+	body, err := request(ctx, &requestArgs{
 		httpMethod: "GET",
 		endpoint:   r.Endpoint,
 		methodName: "transactions",
+		weight:     1,
 		params:     reqParams,
 		restApi:    &r,
+		client:     &r.Client,
 	})
 	if err != nil {
 		return nil, err
@@ -783,6 +1018,10 @@ func (r RestAPI) ListOfTransactions(params *TransactionsRequest) ([]TransactionD
 }
 
 func (r RestAPI) LeverageOrdersEdit(params *UpdateTradingOrderRequest) (*TradingOrderUpdateResponse, error) {
+	return r.LeverageOrdersEditWithContext(context.Background(), params)
+}
+
+func (r RestAPI) LeverageOrdersEditWithContext(ctx context.Context, params *UpdateTradingOrderRequest) (*TradingOrderUpdateResponse, error) {
 	if params == nil {
 		return nil, fmt.Errorf("error params: OrderId need to set")
 	}
@@ -803,24 +1042,27 @@ func (r RestAPI) LeverageOrdersEdit(params *UpdateTradingOrderRequest) (*Trading
 		reqParams["guaranteedStopLoss"] = strconv.FormatBool(params.GuaranteedStopLoss)
 	}
 
-	if params.NewPrice != 0 {
-		reqParams["newPrice"] = strconv.FormatFloat(params.NewPrice, 'f', 8, 64)
+	if !params.NewPrice.IsZero() {
+		reqParams["newPrice"] = params.NewPrice.String()
 	}
 
-	if params.StopLoss != 0 {
-		reqParams["stopLoss"] = strconv.FormatFloat(params.StopLoss, 'f', 8, 64)
+	if !params.StopLoss.IsZero() {
+		reqParams["stopLoss"] = params.StopLoss.String()
 	}
 
-	if params.TakeProfit != 0 {
-		reqParams["takeProfit"] = strconv.FormatFloat(params.TakeProfit, 'f', 8, 64)
+	if !params.TakeProfit.IsZero() {
+		reqParams["takeProfit"] = params.TakeProfit.String()
 	}
 
-	body, err := request(&requestArgs{
+	body, err := request(ctx, &requestArgs{
 		httpMethod: "POST",
 		endpoint:   r.Endpoint,
 		methodName: "updateTradingOrder",
+		weight:     1,
 		params:     reqParams,
 		restApi:    &r,
+		client:     &r.Client,
+		order:      true,
 	})
 	if err != nil {
 		return nil, err
@@ -834,6 +1076,10 @@ func (r RestAPI) LeverageOrdersEdit(params *UpdateTradingOrderRequest) (*Trading
 }
 
 func (r RestAPI) LeverageTradeEdit(params *UpdateTradingPositionRequest) (*TradingPositionUpdateResponse, error) {
+	return r.LeverageTradeEditWithContext(context.Background(), params)
+}
+
+func (r RestAPI) LeverageTradeEditWithContext(ctx context.Context, params *UpdateTradingPositionRequest) (*TradingPositionUpdateResponse, error) {
 	if params == nil {
 		return nil, fmt.Errorf("error params: PositionId need to set")
 	}
@@ -850,20 +1096,23 @@ func (r RestAPI) LeverageTradeEdit(params *UpdateTradingPositionRequest) (*Tradi
 		reqParams["guaranteedStopLoss"] = strconv.FormatBool(params.GuaranteedStopLoss)
 	}
 
-	if params.StopLoss != 0 {
-		reqParams["stopLoss"] = strconv.FormatFloat(params.StopLoss, 'f', 8, 64)
+	if !params.StopLoss.IsZero() {
+		reqParams["stopLoss"] = params.StopLoss.String()
 	}
 
-	if params.TakeProfit != 0 {
-		reqParams["takeProfit"] = strconv.FormatFloat(params.TakeProfit, 'f', 8, 64)
+	if !params.TakeProfit.IsZero() {
+		reqParams["takeProfit"] = params.TakeProfit.String()
 	}
 
-	body, err := request(&requestArgs{
+	body, err := request(ctx, &requestArgs{
 		httpMethod: "POST",
 		endpoint:   r.Endpoint,
 		methodName: "updateTradingPosition",
+		weight:     1,
 		params:     reqParams,
 		restApi:    &r,
+		client:     &r.Client,
+		order:      true,
 	})
 	if err != nil {
 		return nil, err
@@ -877,6 +1126,10 @@ func (r RestAPI) LeverageTradeEdit(params *UpdateTradingPositionRequest) (*Tradi
 }
 
 func (r RestAPI) ListOfWithdrawals(params *TransactionsRequest) ([]TransactionDTOResponse, error) {
+	return r.ListOfWithdrawalsWithContext(context.Background(), params)
+}
+
+func (r RestAPI) ListOfWithdrawalsWithContext(ctx context.Context, params *TransactionsRequest) ([]TransactionDTOResponse, error) {
 	var reqParams map[string]string
 
 	if params != nil {
@@ -899,12 +1152,14 @@ func (r RestAPI) ListOfWithdrawals(params *TransactionsRequest) ([]TransactionDT
 		}
 	}
 
-	body, err := request(&requestArgs{
+	body, err := request(ctx, &requestArgs{
 		httpMethod: "GET",
 		endpoint:   r.Endpoint,
 		methodName: "withdrawals",
+		weight:     1,
 		params:     reqParams,
 		restApi:    &r,
+		client:     &r.Client,
 	})
 	if err != nil {
 		return nil, err
@@ -0,0 +1,129 @@
+package currencycom
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// restServer answers the first fails requests with 503 and the rest with
+// an empty response of the method.
+func restServer(t *testing.T, fails int32) (*httptest.Server, *int32) {
+	t.Helper()
+
+	responses := map[string]string{
+		"time":         `{"serverTime":1600000000000}`,
+		"exchangeInfo": `{"symbols":[]}`,
+		"depth":        `{"asks":[],"bids":[]}`,
+		"klines":       `[]`,
+		"account":      `{"balances":[]}`,
+	}
+
+	var hits int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&hits, 1) <= fails {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		_, _ = w.Write([]byte(responses[r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]]))
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv, &hits
+}
+
+func TestWithContext(t *testing.T) {
+	calls := []struct {
+		name string
+		call func(ctx context.Context, api *RestAPI) error
+	}{
+		{"ServerTime", func(ctx context.Context, api *RestAPI) error {
+			_, err := api.ServerTimeWithContext(ctx)
+			return err
+		}},
+		{"ExchangeInfo", func(ctx context.Context, api *RestAPI) error {
+			_, err := api.ExchangeInfoWithContext(ctx)
+			return err
+		}},
+		{"OrderBook", func(ctx context.Context, api *RestAPI) error {
+			_, err := api.OrderBookWithContext(ctx, &DepthRequest{Symbol: "BTC/USD"})
+			return err
+		}},
+		{"Klines", func(ctx context.Context, api *RestAPI) error {
+			_, err := api.KlinesWithContext(ctx, &KLinesRequest{Symbol: "BTC/USD", Interval: Interval1h})
+			return err
+		}},
+		{"AccountInfo", func(ctx context.Context, api *RestAPI) error {
+			_, err := api.AccountInfoWithContext(ctx, &AccountRequest{})
+			return err
+		}},
+	}
+
+	tests := []struct {
+		name   string
+		fails  int32
+		policy RetryPolicy
+		ctx    func() (context.Context, context.CancelFunc)
+		is     error
+		hits   int32
+	}{
+		{
+			name:   "retried",
+			fails:  1,
+			policy: testRetryPolicy,
+			ctx:    func() (context.Context, context.CancelFunc) { return context.WithCancel(context.Background()) },
+			hits:   2,
+		},
+		{
+			name:   "canceled before the call",
+			policy: testRetryPolicy,
+			ctx: func() (context.Context, context.CancelFunc) {
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+				return ctx, cancel
+			},
+			is:   context.Canceled,
+			hits: 0,
+		},
+		{
+			name:   "deadline in the backoff",
+			fails:  5,
+			policy: RetryPolicy{MaxAttempts: 3, MinBackoff: time.Second, MaxBackoff: time.Second},
+			ctx: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), 50*time.Millisecond)
+			},
+			is:   context.DeadlineExceeded,
+			hits: 1,
+		},
+	}
+
+	for _, c := range calls {
+		for _, tt := range tests {
+			t.Run(c.name+"/"+tt.name, func(t *testing.T) {
+				srv, hits := restServer(t, tt.fails)
+				api := NewRestAPI("key", "secret", srv.URL, WithRetryPolicy(tt.policy))
+
+				ctx, cancel := tt.ctx()
+				defer cancel()
+
+				begin := time.Now()
+				err := c.call(ctx, api)
+				if !errors.Is(err, tt.is) {
+					t.Errorf("err = %v, want %v", err, tt.is)
+				}
+				if got := atomic.LoadInt32(hits); got != tt.hits {
+					t.Errorf("hits = %d, want %d", got, tt.hits)
+				}
+				if elapsed := time.Since(begin); elapsed > 500*time.Millisecond {
+					t.Errorf("returned after %v, want the context to cut the backoff", elapsed)
+				}
+			})
+		}
+	}
+}
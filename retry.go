@@ -0,0 +1,138 @@
+package currencycom
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy describes how failed requests are repeated. Transport errors,
+// 5xx responses and throttling rejections are retried, everything else,
+// including a 418 IP ban, is returned to the caller right away.
+type RetryPolicy struct {
+	MaxAttempts int           // total number of attempts, values <= 1 disable retries
+	MinBackoff  time.Duration // delay before the first retry
+	MaxBackoff  time.Duration // upper bound of a single delay, Retry-After is not limited by it
+	RetryUnsafe bool          // retry POST and DELETE requests too (orders, positions)
+}
+
+// DefaultRetryPolicy retries GET requests up to 3 times.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 3,
+	MinBackoff:  200 * time.Millisecond,
+	MaxBackoff:  5 * time.Second,
+}
+
+// NoRetry disables retries.
+var NoRetry = RetryPolicy{MaxAttempts: 1}
+
+// SetRetryPolicy replaces the retry policy used by the package-level
+// market data functions. It is safe to call while they run.
+func SetRetryPolicy(policy RetryPolicy) {
+	updateDefaultClient(func(c *Client) {
+		c.retry = policy
+	})
+}
+
+// WithRetryPolicy sets the retry policy of the client.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(r *RestAPI) {
+		r.retry = policy
+	}
+}
+
+func (p *RetryPolicy) canRetry(httpMethod string) bool {
+	switch httpMethod {
+	case http.MethodGet, http.MethodHead:
+		return true
+	default:
+		return p.RetryUnsafe
+	}
+}
+
+// delay returns a pause before the next attempt: exponential backoff with
+// jitter, or the server's Retry-After if it is longer.
+func (p *RetryPolicy) delay(attempt int, err error) time.Duration {
+	d := backoff(p.MinBackoff, p.MaxBackoff, attempt)
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.RetryAfter > d {
+		return apiErr.RetryAfter
+	}
+
+	return d
+}
+
+// backoff doubles min for every attempt after the first one, caps it with
+// max (if positive) and picks a random value in the upper half.
+func backoff(min, max time.Duration, attempt int) time.Duration {
+	d := min
+	for i := 1; i < attempt && d < time.Duration(math.MaxInt64/2) && (max <= 0 || d < max); i++ {
+		d *= 2
+	}
+
+	if max > 0 && d > max {
+		d = max
+	}
+
+	if d > 0 {
+		d = d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+	}
+
+	return d
+}
+
+func isRetryable(ctx context.Context, err error) bool {
+	if ctx.Err() != nil || errors.Is(err, ErrRateLimited) {
+		return false
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		// 418 is an IP ban for ignoring 429s, retrying makes it longer
+		if apiErr.StatusCode == http.StatusTeapot {
+			return false
+		}
+
+		return apiErr.StatusCode >= 500 || errors.Is(apiErr, ErrThrottling)
+	}
+
+	// transport errors: connection resets, timeouts, etc.
+	return true
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// parseRetryAfter parses the Retry-After header, which is either a number
+// of seconds or an HTTP date.
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+
+	if at, err := http.ParseTime(value); err == nil {
+		if d := time.Until(at); d > 0 {
+			return d
+		}
+	}
+
+	return 0
+}
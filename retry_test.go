@@ -0,0 +1,143 @@
+package currencycom
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+var testRetryPolicy = RetryPolicy{
+	MaxAttempts: 3,
+	MinBackoff:  time.Millisecond,
+	MaxBackoff:  10 * time.Millisecond,
+}
+
+// failingServer answers the first fails requests with the status and the
+// header, and the rest with a server time.
+func failingServer(t *testing.T, fails int32, status int, header http.Header) (*httptest.Server, *int32) {
+	t.Helper()
+
+	var hits int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&hits, 1) <= fails {
+			for k, v := range header {
+				w.Header()[k] = v
+			}
+			w.WriteHeader(status)
+			_, _ = w.Write([]byte(`{"code":-1000,"msg":"failed"}`))
+			return
+		}
+
+		if r.Method == http.MethodPost {
+			_, _ = w.Write([]byte(`{"orderId":"1","symbol":"BTC/USD","status":"FILLED"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"serverTime":1600000000000}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv, &hits
+}
+
+func TestRetry(t *testing.T) {
+	tests := []struct {
+		name   string
+		fails  int32
+		status int
+		ok     bool
+		hits   int32
+	}{
+		{"server error", 2, http.StatusServiceUnavailable, true, 3},
+		{"throttling", 1, http.StatusTooManyRequests, true, 2},
+		{"attempts exhausted", 5, http.StatusBadGateway, false, 3},
+		{"ip ban", 1, http.StatusTeapot, false, 1},
+		{"bad request", 1, http.StatusBadRequest, false, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, hits := failingServer(t, tt.fails, tt.status, nil)
+			client := NewClient(srv.URL, WithRetryPolicy(testRetryPolicy))
+
+			_, err := client.ServerTime()
+			if (err == nil) != tt.ok {
+				t.Fatalf("err = %v, want ok %v", err, tt.ok)
+			}
+			if got := atomic.LoadInt32(hits); got != tt.hits {
+				t.Errorf("hits = %d, want %d", got, tt.hits)
+			}
+
+			var apiErr *APIError
+			if !tt.ok && (!errors.As(err, &apiErr) || apiErr.StatusCode != tt.status) {
+				t.Errorf("err = %v, want APIError with status %d", err, tt.status)
+			}
+		})
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	srv, hits := failingServer(t, 1, http.StatusTooManyRequests, http.Header{"Retry-After": {"1"}})
+	client := NewClient(srv.URL, WithRetryPolicy(testRetryPolicy))
+
+	start := time.Now()
+	if _, err := client.ServerTime(); err != nil {
+		t.Fatal(err)
+	}
+
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("retried after %v, want at least Retry-After 1s", elapsed)
+	}
+	if got := atomic.LoadInt32(hits); got != 2 {
+		t.Errorf("hits = %d, want 2", got)
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	policy := RetryPolicy{MinBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+
+	for attempt := 1; attempt <= 6; attempt++ {
+		d := policy.delay(attempt, errors.New("reset"))
+		if d < 50*time.Millisecond || d > time.Second {
+			t.Errorf("attempt %d: delay %v out of [50ms, 1s]", attempt, d)
+		}
+	}
+
+	err := &APIError{StatusCode: http.StatusTooManyRequests, RetryAfter: 3 * time.Second}
+	if d := policy.delay(1, err); d != 3*time.Second {
+		t.Errorf("delay = %v, want Retry-After 3s", d)
+	}
+}
+
+func TestRetryUnsafe(t *testing.T) {
+	order := &CreateOrderRequest{Symbol: "BTC/USD", Quantity: NewDecimalFromInt(1), Side: OrderSideBuy, Type: OrderTypeMarket}
+
+	tests := []struct {
+		name   string
+		unsafe bool
+		ok     bool
+		hits   int32
+	}{
+		{"post is not retried", false, false, 1},
+		{"post with RetryUnsafe", true, true, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, hits := failingServer(t, 1, http.StatusServiceUnavailable, nil)
+			policy := testRetryPolicy
+			policy.RetryUnsafe = tt.unsafe
+			api := NewRestAPI("key", "secret", srv.URL, WithRetryPolicy(policy))
+
+			_, err := api.CreateOrder(order)
+			if (err == nil) != tt.ok {
+				t.Fatalf("err = %v, want ok %v", err, tt.ok)
+			}
+			if got := atomic.LoadInt32(hits); got != tt.hits {
+				t.Errorf("hits = %d, want %d", got, tt.hits)
+			}
+		})
+	}
+}
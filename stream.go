@@ -0,0 +1,319 @@
+package currencycom
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ErrStaleConnection is the reason a connection is dropped by StreamManager
+// when nothing was received for StaleTimeout.
+var ErrStaleConnection = errors.New("currencycom: stale connection")
+
+// StreamGap tells that events between From and To may be missing because
+// the connection was lost.
+type StreamGap struct {
+	From time.Time // last message before the connection was lost
+	To   time.Time // subscriptions were restored
+	Err  error     // why the connection was lost
+}
+
+// StreamManager keeps a WebSocketAPI connected: it pings the server,
+// drops connections that went silent, reconnects with exponential backoff
+// and restores all subscriptions. Configure the exported fields before Run.
+type StreamManager struct {
+	PingInterval time.Duration   // how often to ping the server
+	StaleTimeout time.Duration   // reconnect if nothing was received for this long
+	MinBackoff   time.Duration   // delay before the first reconnect
+	MaxBackoff   time.Duration   // upper bound of a delay between reconnects
+	OnGap        func(StreamGap) // called after every successful reconnect, one at a time with the handlers
+
+	api *WebSocketAPI
+
+	mu     sync.Mutex
+	quotes map[Symbol]struct{}
+	depth  map[Symbol]struct{}
+	trades map[Symbol]struct{}
+	ohlc   map[ohlcSubscription]struct{}
+}
+
+type ohlcSubscription struct {
+	candleType CandleType
+	interval   Interval
+	symbol     Symbol
+}
+
+// NewStreamManager creates a manager for the endpoint, empty endpoint means
+// DEFAULT_WS_ENDPOINT. Events are delivered to handler.
+func NewStreamManager(endpoint string, handler StreamHandler) *StreamManager {
+	return &StreamManager{
+		PingInterval: 15 * time.Second,
+		StaleTimeout: 45 * time.Second,
+		MinBackoff:   500 * time.Millisecond,
+		MaxBackoff:   30 * time.Second,
+		api:          NewWebSocketAPI(endpoint, handler),
+		quotes:       make(map[Symbol]struct{}),
+		depth:        make(map[Symbol]struct{}),
+		trades:       make(map[Symbol]struct{}),
+		ohlc:         make(map[ohlcSubscription]struct{}),
+	}
+}
+
+// API returns the underlying client, e.g. to configure its Dialer.
+func (m *StreamManager) API() *WebSocketAPI {
+	return m.api
+}
+
+// Run connects and keeps the connection alive until ctx is done.
+// It always returns a non-nil error, ctx.Err() on cancellation.
+func (m *StreamManager) Run(ctx context.Context) error {
+	var lostAt time.Time
+	var lostErr error
+
+	for attempt := 1; ; attempt++ {
+		if err := m.connect(ctx); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+
+			m.api.onError(err)
+
+			if err := sleep(ctx, backoff(m.MinBackoff, m.MaxBackoff, attempt)); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		attempt = 0
+
+		if !lostAt.IsZero() && m.OnGap != nil {
+			gap := StreamGap{From: lostAt, To: time.Now(), Err: lostErr}
+			m.api.emit(func() { m.OnGap(gap) })
+		}
+		lostAt, lostErr = time.Time{}, nil
+
+		done := m.api.Done()
+		go m.heartbeat(ctx, done)
+
+		select {
+		case <-ctx.Done():
+			m.api.Close()
+			return ctx.Err()
+		case <-done:
+		}
+
+		lostAt, lostErr = m.api.LastMessage(), m.api.Err()
+
+		// don't hammer a server that accepts and drops connections
+		if err := sleep(ctx, backoff(m.MinBackoff, m.MaxBackoff, 1)); err != nil {
+			return err
+		}
+	}
+}
+
+// SubscribeQuotes subscribes to quotes now, if connected, and after every
+// reconnect.
+func (m *StreamManager) SubscribeQuotes(ctx context.Context, symbols ...Symbol) error {
+	// a bad symbol must not be kept, it would break every reconnect
+	if err := m.api.checkSymbols(symbols); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	addSymbols(m.quotes, symbols)
+	m.mu.Unlock()
+
+	_, err := m.api.SubscribeQuotes(ctx, symbols...)
+
+	return ignoreClosed(err)
+}
+
+// SubscribeDepth subscribes to order book updates now, if connected, and
+// after every reconnect.
+func (m *StreamManager) SubscribeDepth(ctx context.Context, symbols ...Symbol) error {
+	if err := m.api.checkSymbols(symbols); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	addSymbols(m.depth, symbols)
+	m.mu.Unlock()
+
+	_, err := m.api.SubscribeDepth(ctx, symbols...)
+
+	return ignoreClosed(err)
+}
+
+// SubscribeTrades subscribes to trades now, if connected, and after every
+// reconnect.
+func (m *StreamManager) SubscribeTrades(ctx context.Context, symbols ...Symbol) error {
+	if err := m.api.checkSymbols(symbols); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	addSymbols(m.trades, symbols)
+	m.mu.Unlock()
+
+	_, err := m.api.SubscribeTrades(ctx, symbols...)
+
+	return ignoreClosed(err)
+}
+
+// SubscribeOHLC subscribes to candlesticks now, if connected, and after
+// every reconnect.
+func (m *StreamManager) SubscribeOHLC(ctx context.Context, params *OHLCSubscribeRequest) error {
+	if params == nil {
+		return errors.New("error params: Symbols need to set")
+	}
+
+	if err := m.api.checkOHLC(params); err != nil {
+		return err
+	}
+
+	intervals := params.Intervals
+	if len(intervals) == 0 {
+		intervals = []Interval{""}
+	}
+
+	m.mu.Lock()
+	for _, symbol := range params.Symbols {
+		for _, interval := range intervals {
+			m.ohlc[ohlcSubscription{candleType: params.Type, interval: interval, symbol: symbol}] = struct{}{}
+		}
+	}
+	m.mu.Unlock()
+
+	_, err := m.api.SubscribeOHLC(ctx, params)
+
+	return ignoreClosed(err)
+}
+
+// connect dials and restores all subscriptions.
+func (m *StreamManager) connect(ctx context.Context) error {
+	if err := m.api.Connect(ctx); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	quotes, depth, trades := symbolList(m.quotes), symbolList(m.depth), symbolList(m.trades)
+	ohlc := ohlcRequests(m.ohlc)
+	m.mu.Unlock()
+
+	err := func() error {
+		if len(quotes) > 0 {
+			if _, err := m.api.SubscribeQuotes(ctx, quotes...); err != nil {
+				return err
+			}
+		}
+
+		if len(depth) > 0 {
+			if _, err := m.api.SubscribeDepth(ctx, depth...); err != nil {
+				return err
+			}
+		}
+
+		if len(trades) > 0 {
+			if _, err := m.api.SubscribeTrades(ctx, trades...); err != nil {
+				return err
+			}
+		}
+
+		for _, req := range ohlc {
+			if _, err := m.api.SubscribeOHLC(ctx, req); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}()
+	if err != nil {
+		m.api.Close()
+	}
+
+	return err
+}
+
+// heartbeat pings the server and drops the connection if it went silent.
+func (m *StreamManager) heartbeat(ctx context.Context, done <-chan struct{}) {
+	if m.PingInterval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(m.PingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-done:
+			return
+		case <-ticker.C:
+		}
+
+		if m.StaleTimeout > 0 && time.Since(m.api.LastMessage()) > m.StaleTimeout {
+			m.api.drop(ErrStaleConnection)
+			return
+		}
+
+		// the answer refreshes LastMessage, a missing one is caught above
+		pingCtx, cancel := context.WithTimeout(ctx, m.PingInterval)
+		_ = m.api.Ping(pingCtx)
+		cancel()
+	}
+}
+
+func addSymbols(set map[Symbol]struct{}, symbols []Symbol) {
+	for _, symbol := range symbols {
+		set[symbol] = struct{}{}
+	}
+}
+
+func symbolList(set map[Symbol]struct{}) []Symbol {
+	out := make([]Symbol, 0, len(set))
+	for symbol := range set {
+		out = append(out, symbol)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i] < out[j] })
+
+	return out
+}
+
+// ohlcRequests groups OHLC subscriptions by candle type and interval.
+func ohlcRequests(set map[ohlcSubscription]struct{}) []*OHLCSubscribeRequest {
+	type key struct {
+		candleType CandleType
+		interval   Interval
+	}
+
+	groups := make(map[key]*OHLCSubscribeRequest)
+	var out []*OHLCSubscribeRequest
+
+	for sub := range set {
+		k := key{sub.candleType, sub.interval}
+		req, ok := groups[k]
+		if !ok {
+			req = &OHLCSubscribeRequest{Type: sub.candleType}
+			if sub.interval != "" {
+				req.Intervals = []Interval{sub.interval}
+			}
+			groups[k] = req
+			out = append(out, req)
+		}
+		req.Symbols = append(req.Symbols, sub.symbol)
+	}
+
+	return out
+}
+
+func ignoreClosed(err error) error {
+	if errors.Is(err, ErrStreamClosed) {
+		return nil
+	}
+
+	return err
+}
@@ -0,0 +1,231 @@
+package currencycom
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// wsAnswerAll answers every request of the connection with OK until it is
+// closed, passing the requests to seen if it is not nil.
+func wsAnswerAll(conn *websocket.Conn, seen chan<- wsRequest) {
+	for {
+		var req wsRequest
+		if err := conn.ReadJSON(&req); err != nil {
+			return
+		}
+
+		if seen != nil {
+			seen <- req
+		}
+		_ = wsAnswer(conn, req.CorrelationId, req.Destination, "OK", `{"subscriptions":{}}`)
+	}
+}
+
+// runManager runs m until the returned stop is called, stop returns what
+// Run returned. The test stops it at the latest on cleanup.
+func runManager(t *testing.T, m *StreamManager) (stop func() error) {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- m.Run(ctx) }()
+
+	var once sync.Once
+	var err error
+	stop = func() error {
+		once.Do(func() {
+			cancel()
+			err = <-done
+		})
+
+		return err
+	}
+	t.Cleanup(func() { _ = stop() })
+
+	return stop
+}
+
+func TestStreamManagerResubscribe(t *testing.T) {
+	resubscribed := make(chan wsRequest, 10)
+	endpoint := wsServer(t, func(n int, conn *websocket.Conn) {
+		if n == 1 {
+			// answer the two subscriptions, push a quote and hang up
+			for i := 0; i < 2; i++ {
+				var req wsRequest
+				if err := conn.ReadJSON(&req); err != nil {
+					return
+				}
+				_ = wsAnswer(conn, req.CorrelationId, req.Destination, "OK", `{"subscriptions":{}}`)
+			}
+			_ = wsEvent(conn, wsQuoteEvent, `{"symbolName":"BTC/USD","bid":"100"}`)
+			return
+		}
+
+		wsAnswerAll(conn, resubscribed)
+	})
+
+	// plain counters, -race catches handlers running at the same time
+	var quotes, failures int
+	gaps := make(chan StreamGap, 1)
+
+	m := NewStreamManager(endpoint, StreamHandler{
+		OnQuote: func(InternalQuote) { quotes++ },
+		OnError: func(error) { failures++ },
+	})
+	m.PingInterval = 0
+	m.MinBackoff, m.MaxBackoff = 10*time.Millisecond, 50*time.Millisecond
+	m.OnGap = func(gap StreamGap) { gaps <- gap }
+
+	ctx := context.Background()
+	if err := m.SubscribeQuotes(ctx, "BTC/USD"); err != nil {
+		t.Fatalf("SubscribeQuotes before Run: %v", err)
+	}
+	if err := m.SubscribeOHLC(ctx, &OHLCSubscribeRequest{Symbols: []Symbol{"ETH/USD"}, Intervals: []Interval{Interval1h}, Type: CandleTypeHeikinAshi}); err != nil {
+		t.Fatalf("SubscribeOHLC before Run: %v", err)
+	}
+
+	stop := runManager(t, m)
+
+	var gap StreamGap
+	select {
+	case gap = <-gaps:
+	case <-time.After(5 * time.Second):
+		t.Fatal("no gap after the server hung up")
+	}
+
+	if gap.Err == nil || gap.From.After(gap.To) {
+		t.Errorf("gap %+v, want the read error and From before To", gap)
+	}
+
+	// both subscriptions are restored on the second connection
+	destinations := map[string]bool{}
+	for i := 0; i < 2; i++ {
+		select {
+		case req := <-resubscribed:
+			destinations[req.Destination] = true
+		case <-time.After(5 * time.Second):
+			t.Fatalf("resubscribed %v, want quotes and OHLC", destinations)
+		}
+	}
+	if !destinations[wsQuotesSubscribe] || !destinations[wsOHLCSubscribe] {
+		t.Errorf("resubscribed %v, want quotes and OHLC", destinations)
+	}
+
+	if err := stop(); !errors.Is(err, context.Canceled) {
+		t.Errorf("Run = %v, want context.Canceled", err)
+	}
+
+	if quotes != 1 || failures != 1 {
+		t.Errorf("quotes %d failures %d, want 1 and the lost connection", quotes, failures)
+	}
+}
+
+func TestStreamManagerBackoff(t *testing.T) {
+	var mu sync.Mutex
+	var dials []time.Time
+	upgrader := websocket.Upgrader{}
+
+	// the first 3 dials are refused
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		dials = append(dials, time.Now())
+		n := len(dials)
+		mu.Unlock()
+
+		if n <= 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		wsAnswerAll(conn, nil)
+	}))
+	t.Cleanup(srv.Close)
+
+	var failures int32
+	connected := make(chan struct{})
+	m := NewStreamManager("ws"+strings.TrimPrefix(srv.URL, "http"), StreamHandler{
+		OnError: func(error) { atomic.AddInt32(&failures, 1) },
+	})
+	m.PingInterval = 0
+	m.MinBackoff, m.MaxBackoff = 20*time.Millisecond, time.Second
+
+	// the quote subscription is sent once the 4th dial succeeds
+	if err := m.SubscribeQuotes(context.Background(), "BTC/USD"); err != nil {
+		t.Fatal(err)
+	}
+	go func() {
+		for {
+			mu.Lock()
+			n := len(dials)
+			mu.Unlock()
+			if n >= 4 {
+				close(connected)
+				return
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	}()
+
+	runManager(t, m)
+
+	select {
+	case <-connected:
+	case <-time.After(5 * time.Second):
+		t.Fatal("never reconnected")
+	}
+
+	if n := atomic.LoadInt32(&failures); n != 3 {
+		t.Errorf("OnError called %d times, want one per refused dial", n)
+	}
+
+	// delays of 20, 40 and 80ms, each at least half of it with the jitter
+	mu.Lock()
+	elapsed := dials[3].Sub(dials[0])
+	mu.Unlock()
+	if elapsed < 70*time.Millisecond {
+		t.Errorf("reconnected after %v, want the backoff of at least 70ms", elapsed)
+	}
+}
+
+func TestStreamManagerStale(t *testing.T) {
+	// the server never answers, not even pings
+	endpoint := wsServer(t, func(n int, conn *websocket.Conn) {
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	})
+
+	gaps := make(chan StreamGap, 10)
+	m := NewStreamManager(endpoint, StreamHandler{})
+	m.PingInterval, m.StaleTimeout = 20*time.Millisecond, 60*time.Millisecond
+	m.MinBackoff, m.MaxBackoff = 10*time.Millisecond, 10*time.Millisecond
+	m.OnGap = func(gap StreamGap) { gaps <- gap }
+
+	runManager(t, m)
+
+	select {
+	case gap := <-gaps:
+		if !errors.Is(gap.Err, ErrStaleConnection) {
+			t.Errorf("gap error %v, want ErrStaleConnection", gap.Err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("silent connection not dropped")
+	}
+}
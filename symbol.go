@@ -0,0 +1,87 @@
+package currencycom
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Symbol is a market name: "BTC/USD" for a spot pair, "BTC/USD_LEVERAGE"
+// for its leveraged counterpart, or a ticker like "AAPL".
+type Symbol string
+
+// ParseSymbol checks the syntax of a symbol. Whether the market exists is
+// told by SymbolRegistry.
+func ParseSymbol(s string) (Symbol, error) {
+	symbol := Symbol(s)
+	if !symbol.IsValid() {
+		return "", fmt.Errorf("error in symbol %q, need a name without surrounding or control whitespace", s)
+	}
+
+	return symbol, nil
+}
+
+// IsValid reports whether the symbol is well formed: not empty, without
+// leading or trailing spaces and without tabs, line breaks or other
+// control characters. Listed names are as varied as "BTC/USD_LEVERAGE",
+// "AAPL" and "Oil - Crude", so anything else is left to
+// SymbolRegistry.Validate, which tells whether the market exists.
+func (s Symbol) IsValid() bool {
+	name := strings.TrimSuffix(string(s), leverageSuffix)
+	if name == "" || strings.TrimSpace(name) != name {
+		return false
+	}
+
+	if base, quote, pair := strings.Cut(name, "/"); pair && (strings.TrimSpace(base) == "" || strings.TrimSpace(quote) == "") {
+		return false
+	}
+
+	for _, r := range name {
+		if unicode.IsControl(r) || unicode.IsSpace(r) && r != ' ' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Base returns the base asset, "BTC" of "BTC/USD", or the ticker.
+func (s Symbol) Base() string {
+	base, _, _ := strings.Cut(strings.TrimSuffix(string(s), leverageSuffix), "/")
+
+	return base
+}
+
+// Quote returns the quote asset, "USD" of "BTC/USD", empty for a ticker.
+func (s Symbol) Quote() string {
+	_, quote, _ := strings.Cut(strings.TrimSuffix(string(s), leverageSuffix), "/")
+
+	return quote
+}
+
+// IsLeverage reports whether the symbol has the _LEVERAGE suffix. Tickers
+// like "AAPL" are leverage markets too, but the name doesn't tell it, ask
+// SymbolRegistry.
+func (s Symbol) IsLeverage() bool {
+	return strings.HasSuffix(string(s), leverageSuffix)
+}
+
+// Leverage returns the leveraged counterpart of a spot pair, BTC/USD_LEVERAGE
+// for BTC/USD. Other symbols are returned as is.
+func (s Symbol) Leverage() Symbol {
+	if s.IsLeverage() || s.Quote() == "" {
+		return s
+	}
+
+	return s + leverageSuffix
+}
+
+// Spot returns the spot pair of a leverage symbol, BTC/USD for
+// BTC/USD_LEVERAGE.
+func (s Symbol) Spot() Symbol {
+	return Symbol(strings.TrimSuffix(string(s), leverageSuffix))
+}
+
+func (s Symbol) String() string {
+	return string(s)
+}
@@ -0,0 +1,97 @@
+package currencycom
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestSymbolIsValid(t *testing.T) {
+	tests := []struct {
+		symbol Symbol
+		valid  bool
+	}{
+		{"BTC/USD", true},
+		{"BTC/USD_LEVERAGE", true},
+		{"USDT/USD", true},
+		{"AAPL", true},
+		{"BRK.B", true},
+		{"US500", true},
+		{"AAPL_LEVERAGE", true},
+		{"BTCUSD", true},
+		{"ETHBTC", true},
+		{"EURUSD_LEVERAGE", true},
+		{"Gold", true},
+		{"Oil - Crude", true},
+		{"", false},
+		{" AAPL", false},
+		{"AAPL ", false},
+		{"BTC\tUSD", false},
+		{"BTC\nUSD", false},
+		{"BTC/", false},
+		{"/USD", false},
+		{"BTC/ ", false},
+		{"_LEVERAGE", false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.symbol.IsValid(); got != tt.valid {
+			t.Errorf("Symbol(%q).IsValid() = %v, want %v", tt.symbol, got, tt.valid)
+		}
+	}
+}
+
+func TestSubscribeSymbols(t *testing.T) {
+	registry := NewSymbolRegistry(nil)
+	registry.Load(&ExchangeInfoResponse{Symbols: []ExchangeSymbolInfo{{Symbol: "BTC/USD"}, {Symbol: "AAPL"}}})
+
+	ws := NewWebSocketAPI("", StreamHandler{})
+	ctx := context.Background()
+
+	if _, err := ws.SubscribeQuotes(ctx); err == nil {
+		t.Error("SubscribeQuotes without symbols: want error")
+	}
+
+	if _, err := ws.SubscribeDepth(ctx, "BTC/USD", " BTC/USD"); err == nil || errors.Is(err, ErrStreamClosed) {
+		t.Errorf("SubscribeDepth with a leading space: err = %v, want invalid Symbol", err)
+	}
+
+	if _, err := ws.SubscribeOHLC(ctx, &OHLCSubscribeRequest{Symbols: []Symbol{"BTC/USD"}, Intervals: []Interval{"2m"}}); err == nil {
+		t.Error("SubscribeOHLC with 2m: want error")
+	}
+
+	if _, err := ws.SubscribeOHLC(ctx, &OHLCSubscribeRequest{Symbols: []Symbol{"BTC/USD"}, Type: "renko"}); err == nil || errors.Is(err, ErrStreamClosed) {
+		t.Errorf("SubscribeOHLC with renko: err = %v, want unknown Type", err)
+	}
+
+	ws.Registry = registry
+	if _, err := ws.SubscribeTrades(ctx, "TSLA"); !errors.Is(err, ErrInvalidSymbol) {
+		t.Errorf("SubscribeTrades with an unlisted ticker: err = %v, want ErrInvalidSymbol", err)
+	}
+
+	// valid symbols get as far as the missing connection
+	if _, err := ws.SubscribeTrades(ctx, "AAPL", "BTC/USD"); !errors.Is(err, ErrStreamClosed) {
+		t.Errorf("SubscribeTrades with listed symbols: err = %v, want ErrStreamClosed", err)
+	}
+}
+
+func TestCandleType(t *testing.T) {
+	m := NewStreamManager("", StreamHandler{})
+	if err := m.SubscribeOHLC(context.Background(), &OHLCSubscribeRequest{Symbols: []Symbol{"BTC/USD"}, Type: "Classic"}); err == nil {
+		t.Error("StreamManager.SubscribeOHLC with Classic: want error")
+	}
+	if len(m.ohlc) != 0 {
+		t.Errorf("stored %v, want the bad type kept out of resubscribes", m.ohlc)
+	}
+
+	if err := m.SubscribeOHLC(context.Background(), &OHLCSubscribeRequest{Symbols: []Symbol{"BTC/USD"}, Type: CandleTypeHeikinAshi}); err != nil {
+		t.Fatal(err)
+	}
+	if len(m.ohlc) != 1 {
+		t.Errorf("stored %v, want the heikin-ashi subscription", m.ohlc)
+	}
+
+	if _, err := NewClient("").Klines(&KLinesRequest{Symbol: "BTC/USD", Interval: Interval1h, Type: "renko"}); err == nil {
+		t.Error("Klines with renko: want error")
+	}
+}
@@ -0,0 +1,477 @@
+package currencycom
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Filter types of SymbolFilter.
+const (
+	FilterTypePrice       = "PRICE_FILTER"
+	FilterTypeLotSize     = "LOT_SIZE"
+	FilterTypeMinNotional = "MIN_NOTIONAL"
+)
+
+// OrderValidator checks orders against the symbol metadata of ExchangeInfo
+// before they are sent, so mistakes are reported with every broken rule
+// instead of one server rejection at a time.
+//
+// SL/TP gaps are the distance between the stop and the reference price in
+// percent of the reference price, the order price for new orders and the
+// open price for positions.
+type OrderValidator struct {
+	// Round makes the validator round prices, stop losses and take profits
+	// to the nearest tick and quantities down to the step before checking,
+	// instead of reporting them. The request is changed in place.
+	Round bool
+
+	mu      sync.RWMutex
+	symbols map[string]*ExchangeSymbolInfo
+}
+
+// OrderViolation is one rule broken by a request.
+type OrderViolation struct {
+	Field   string // request field, e.g. "Price"
+	Message string
+}
+
+// OrderValidationError lists every rule broken by a request. It matches
+// ErrInvalidOrder with errors.Is, as well as ErrInvalidSymbol,
+// ErrMarketClosed, ErrInvalidPrice and ErrInvalidQuantity when one of the
+// violations is about them.
+type OrderValidationError struct {
+	Symbol     string
+	Violations []OrderViolation
+}
+
+func (e *OrderValidationError) Error() string {
+	msgs := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		msgs[i] = v.Field + " " + v.Message
+	}
+
+	return fmt.Sprintf("currencycom: invalid order for %s, %s", e.Symbol, strings.Join(msgs, "; "))
+}
+
+func (e *OrderValidationError) Is(target error) bool {
+	switch target {
+	case ErrInvalidOrder:
+		return true
+	case ErrInvalidSymbol:
+		return e.has("Symbol")
+	case ErrMarketClosed:
+		return e.has("Status") || e.has("MarketModes")
+	case ErrInvalidPrice:
+		return e.has("Price") || e.has("StopLoss") || e.has("TakeProfit")
+	case ErrInvalidQuantity:
+		return e.has("Quantity")
+	}
+
+	return false
+}
+
+func (e *OrderValidationError) has(field string) bool {
+	for _, v := range e.Violations {
+		if v.Field == field {
+			return true
+		}
+	}
+
+	return false
+}
+
+// NewOrderValidator creates a validator for the symbols of info.
+func NewOrderValidator(info *ExchangeInfoResponse) *OrderValidator {
+	v := &OrderValidator{}
+	v.SetExchangeInfo(info)
+
+	return v
+}
+
+// WithOrderValidator makes CreateOrder check every order with the validator
+// before sending it. With Round set the rounded values are sent, the
+// caller's request is left as is.
+func WithOrderValidator(v *OrderValidator) Option {
+	return func(r *RestAPI) {
+		r.validator = v
+	}
+}
+
+// SetExchangeInfo replaces the symbol metadata, e.g. after a refresh.
+func (v *OrderValidator) SetExchangeInfo(info *ExchangeInfoResponse) {
+	symbols := make(map[string]*ExchangeSymbolInfo)
+	if info != nil {
+		for i := range info.Symbols {
+			symbols[info.Symbols[i].Symbol] = &info.Symbols[i]
+		}
+	}
+
+	v.mu.Lock()
+	v.symbols = symbols
+	v.mu.Unlock()
+}
+
+// ValidateOrder checks a new order: symbol status and market modes, order
+// type, price and quantity increments and limits, minimal notional and
+// SL/TP placement.
+func (v *OrderValidator) ValidateOrder(params *CreateOrderRequest) error {
+	if params == nil {
+		return fmt.Errorf("error params: Symbol, Quantity, Side, Type need to set")
+	}
+
+	info, err := v.symbol(string(params.Symbol))
+	if err != nil {
+		return err
+	}
+
+	if v.Round {
+		params.Price = roundPrice(info, params.Price)
+		params.StopLoss = roundPrice(info, params.StopLoss)
+		params.TakeProfit = roundPrice(info, params.TakeProfit)
+		params.Quantity = roundQuantity(info, params.Quantity)
+	}
+
+	c := &orderCheck{info: info}
+
+	if !params.Side.IsValid() {
+		c.add("Side", "%q is unknown", params.Side)
+	}
+
+	c.market(params.Side)
+	c.orderType(params.Type)
+	c.quantity(params.Quantity)
+
+	if params.Type != OrderTypeMarket && params.Price.IsZero() {
+		c.add("Price", "is required for %s orders", params.Type)
+	} else if !params.Price.IsZero() {
+		c.price("Price", params.Price)
+		c.notional(params.Price, params.Quantity)
+	}
+
+	c.stops(params.Side, params.Price, params.StopLoss, params.TakeProfit, params.GuaranteedStopLoss)
+
+	return c.err()
+}
+
+// ValidatePositionUpdate checks new SL/TP of the position against the
+// symbol metadata.
+func (v *OrderValidator) ValidatePositionUpdate(params *UpdateTradingPositionRequest, position *PositionDto) error {
+	if params == nil {
+		return fmt.Errorf("error params: PositionId need to set")
+	}
+
+	if position == nil {
+		return fmt.Errorf("error params: position need to set")
+	}
+
+	info, err := v.symbol(position.Symbol)
+	if err != nil {
+		return err
+	}
+
+	if v.Round {
+		params.StopLoss = roundPrice(info, params.StopLoss)
+		params.TakeProfit = roundPrice(info, params.TakeProfit)
+	}
+
+	c := &orderCheck{info: info}
+
+	if position.Id != "" && params.PositionId != position.Id {
+		c.add("PositionId", "%q doesn't match position %q", params.PositionId, position.Id)
+	}
+
+	side := OrderSideBuy
+	if position.OpenQuantity.IsNegative() {
+		side = OrderSideSell
+	}
+
+	c.stops(side, position.OpenPrice, params.StopLoss, params.TakeProfit, params.GuaranteedStopLoss)
+
+	return c.err()
+}
+
+func (v *OrderValidator) symbol(name string) (*ExchangeSymbolInfo, error) {
+	v.mu.RLock()
+	info, ok := v.symbols[name]
+	v.mu.RUnlock()
+
+	if !ok {
+		return nil, &OrderValidationError{
+			Symbol:     name,
+			Violations: []OrderViolation{{Field: "Symbol", Message: "is unknown"}},
+		}
+	}
+
+	return info, nil
+}
+
+// orderCheck collects the violations of one request.
+type orderCheck struct {
+	info       *ExchangeSymbolInfo
+	violations []OrderViolation
+}
+
+func (c *orderCheck) add(field, format string, args ...interface{}) {
+	c.violations = append(c.violations, OrderViolation{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+func (c *orderCheck) err() error {
+	if len(c.violations) == 0 {
+		return nil
+	}
+
+	return &OrderValidationError{Symbol: c.info.Symbol, Violations: c.violations}
+}
+
+func (c *orderCheck) market(side OrderSide) {
+	if c.info.Status != "" && c.info.Status != ExchangeStatusTrading {
+		c.add("Status", "of the symbol is %s", c.info.Status)
+	}
+
+	if len(c.info.MarketModes) == 0 {
+		return
+	}
+
+	longOnly := false
+	for _, mode := range c.info.MarketModes {
+		switch mode {
+		case MarketModeRegular:
+			return
+		case MarketModeLongOnly:
+			longOnly = true
+		}
+	}
+
+	switch {
+	case !longOnly:
+		c.add("MarketModes", "%v don't allow new orders", c.info.MarketModes)
+	case side == OrderSideSell && c.info.MarketType == MarketTypeLeverage:
+		c.add("MarketModes", "%s allows only long positions", MarketModeLongOnly)
+	}
+}
+
+func (c *orderCheck) orderType(orderType OrderType) {
+	if !orderType.IsValid() {
+		c.add("Type", "%q is unknown", orderType)
+		return
+	}
+
+	if len(c.info.OrderTypes) == 0 {
+		return
+	}
+
+	for _, t := range c.info.OrderTypes {
+		if t == orderType {
+			return
+		}
+	}
+
+	c.add("Type", "%s is not supported, use one of %v", orderType, c.info.OrderTypes)
+}
+
+func (c *orderCheck) price(field string, price Decimal) {
+	if !price.IsPositive() {
+		c.add(field, "%s need to be positive", price)
+		return
+	}
+
+	if tick := priceTick(c.info); tick.IsPositive() {
+		if !price.Mod(tick).IsZero() {
+			c.add(field, "%s is not a multiple of tick size %s", price, tick)
+		}
+	} else if c.info.hasQuotePrecision() && !price.Equal(price.Truncate(c.info.QuotePrecision)) {
+		c.add(field, "%s has more than %d decimals", price, c.info.QuotePrecision)
+	}
+
+	if filter, ok := symbolFilter(c.info, FilterTypePrice); ok {
+		if filter.MinPrice.IsPositive() && price.LessThan(filter.MinPrice) {
+			c.add(field, "%s is below the minimum %s", price, filter.MinPrice)
+		}
+
+		if filter.MaxPrice.IsPositive() && price.GreaterThan(filter.MaxPrice) {
+			c.add(field, "%s is above the maximum %s", price, filter.MaxPrice)
+		}
+	}
+}
+
+func (c *orderCheck) quantity(quantity Decimal) {
+	if !quantity.IsPositive() {
+		c.add("Quantity", "%s need to be positive", quantity)
+		return
+	}
+
+	if c.info.hasBaseAssetPrecision() && !quantity.Equal(quantity.Truncate(c.info.BaseAssetPrecision)) {
+		c.add("Quantity", "%s has more than %d decimals", quantity, c.info.BaseAssetPrecision)
+	}
+
+	filter, ok := symbolFilter(c.info, FilterTypeLotSize)
+	if !ok {
+		return
+	}
+
+	if filter.MinQty.IsPositive() && quantity.LessThan(filter.MinQty) {
+		c.add("Quantity", "%s is below the minimum %s", quantity, filter.MinQty)
+	}
+
+	if filter.MaxQty.IsPositive() && quantity.GreaterThan(filter.MaxQty) {
+		c.add("Quantity", "%s is above the maximum %s", quantity, filter.MaxQty)
+	}
+
+	if filter.StepSize.IsPositive() && !quantity.Mod(filter.StepSize).IsZero() {
+		c.add("Quantity", "%s is not a multiple of step size %s", quantity, filter.StepSize)
+	}
+}
+
+func (c *orderCheck) notional(price, quantity Decimal) {
+	filter, ok := symbolFilter(c.info, FilterTypeMinNotional)
+	if !ok || !filter.MinNotional.IsPositive() || !quantity.IsPositive() {
+		return
+	}
+
+	if notional := price.Mul(quantity); notional.LessThan(filter.MinNotional) {
+		c.add("Quantity", "notional %s is below the minimum %s", notional, filter.MinNotional)
+	}
+}
+
+// stops checks that SL/TP are valid prices on the right side of the
+// reference price and within the allowed gaps. A zero reference price,
+// e.g. of a market order, skips the side and gap checks.
+func (c *orderCheck) stops(side OrderSide, reference, stopLoss, takeProfit Decimal, guaranteed bool) {
+	if guaranteed && stopLoss.IsZero() {
+		c.add("StopLoss", "is required by GuaranteedStopLoss")
+	}
+
+	long := side != OrderSideSell
+
+	if !stopLoss.IsZero() {
+		c.price("StopLoss", stopLoss)
+		if reference.IsPositive() {
+			c.stop("StopLoss", stopLoss, reference, long, c.info.MinSLGap, c.info.MaxSLGap)
+		}
+	}
+
+	if !takeProfit.IsZero() {
+		c.price("TakeProfit", takeProfit)
+		if reference.IsPositive() {
+			c.stop("TakeProfit", takeProfit, reference, !long, c.info.MinTPGap, c.info.MaxTPGap)
+		}
+	}
+}
+
+// stop checks one stop, below is true when it must be under the reference.
+func (c *orderCheck) stop(field string, stop, reference Decimal, below bool, minGap, maxGap Decimal) {
+	if below && stop.GreaterThanOrEqual(reference) {
+		c.add(field, "%s need to be below %s", stop, reference)
+		return
+	}
+
+	if !below && stop.LessThanOrEqual(reference) {
+		c.add(field, "%s need to be above %s", stop, reference)
+		return
+	}
+
+	gap := stop.Sub(reference).Abs().Div(reference).Mul(NewDecimalFromInt(100))
+
+	if minGap.IsPositive() && gap.LessThan(minGap) {
+		c.add(field, "%s is %s%% from %s, the minimum gap is %s%%", stop, gap.Round(4), reference, minGap)
+	}
+
+	if maxGap.IsPositive() && gap.GreaterThan(maxGap) {
+		c.add(field, "%s is %s%% from %s, the maximum gap is %s%%", stop, gap.Round(4), reference, maxGap)
+	}
+}
+
+// priceTick returns the tick size of the symbol, falling back to the one
+// of PRICE_FILTER.
+func priceTick(info *ExchangeSymbolInfo) Decimal {
+	if info.TickSize.IsPositive() {
+		return info.TickSize
+	}
+
+	filter, _ := symbolFilter(info, FilterTypePrice)
+
+	return filter.TickSize
+}
+
+func symbolFilter(info *ExchangeSymbolInfo, filterType string) (SymbolFilter, bool) {
+	for _, filter := range info.Filters {
+		if filter.FilterType == filterType {
+			return filter, true
+		}
+	}
+
+	return SymbolFilter{}, false
+}
+
+// roundPrice rounds a price to the nearest tick, zero stays zero.
+func roundPrice(info *ExchangeSymbolInfo, price Decimal) Decimal {
+	if price.IsZero() {
+		return price
+	}
+
+	if tick := priceTick(info); tick.IsPositive() {
+		return price.Div(tick).Round(0).Mul(tick)
+	}
+
+	if info.hasQuotePrecision() {
+		return price.Round(info.QuotePrecision)
+	}
+
+	return price
+}
+
+// roundQuantity rounds a quantity down, so it never grows past what the
+// caller can afford.
+func roundQuantity(info *ExchangeSymbolInfo, quantity Decimal) Decimal {
+	if filter, ok := symbolFilter(info, FilterTypeLotSize); ok && filter.StepSize.IsPositive() {
+		quantity = quantity.Div(filter.StepSize).Truncate(0).Mul(filter.StepSize)
+	}
+
+	if info.hasBaseAssetPrecision() {
+		quantity = quantity.Truncate(info.BaseAssetPrecision)
+	}
+
+	return quantity
+}
+
+// UnmarshalJSON decodes the symbol and remembers which precisions were
+// sent, so a precision of 0 (whole units) is told apart from a missing one.
+func (s *ExchangeSymbolInfo) UnmarshalJSON(data []byte) error {
+	type plain ExchangeSymbolInfo
+	var raw struct {
+		plain
+		BaseAssetPrecision *int32 `json:"baseAssetPrecision"`
+		QuotePrecision     *int32 `json:"quotePrecision"`
+	}
+
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	*s = ExchangeSymbolInfo(raw.plain)
+
+	if raw.BaseAssetPrecision != nil {
+		s.BaseAssetPrecision = *raw.BaseAssetPrecision
+		s.baseAssetPrecisionSet = true
+	}
+
+	if raw.QuotePrecision != nil {
+		s.QuotePrecision = *raw.QuotePrecision
+		s.quotePrecisionSet = true
+	}
+
+	return nil
+}
+
+// hasBaseAssetPrecision reports whether the quantity precision is known:
+// decoded from the exchange or set to a non-zero value by hand.
+func (s *ExchangeSymbolInfo) hasBaseAssetPrecision() bool {
+	return s.baseAssetPrecisionSet || s.BaseAssetPrecision > 0
+}
+
+// hasQuotePrecision is hasBaseAssetPrecision for prices.
+func (s *ExchangeSymbolInfo) hasQuotePrecision() bool {
+	return s.quotePrecisionSet || s.QuotePrecision > 0
+}
@@ -0,0 +1,174 @@
+package currencycom
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+const testExchangeInfo = `{"symbols":[
+	{"symbol":"BTC/USD","status":"TRADING","marketModes":["REGULAR"],"marketType":"SPOT",
+	 "orderTypes":["MARKET","LIMIT"],"baseAssetPrecision":4,"quotePrecision":2,"tickSize":"0.5",
+	 "minSLGap":"1","maxSLGap":"10","minTPGap":"1","maxTPGap":"10",
+	 "filters":[
+		{"filterType":"LOT_SIZE","minQty":"0.001","maxQty":"100","stepSize":"0.001"},
+		{"filterType":"PRICE_FILTER","minPrice":"1","maxPrice":"100000","tickSize":"0.5"},
+		{"filterType":"MIN_NOTIONAL","minNotional":"10"}]},
+	{"symbol":"AAPL","status":"TRADING","orderTypes":["MARKET","LIMIT"],
+	 "baseAssetPrecision":0,"quotePrecision":0},
+	{"symbol":"OLD","status":"BREAK","orderTypes":["MARKET"]}
+]}`
+
+func testValidator(t *testing.T) *OrderValidator {
+	t.Helper()
+
+	var info ExchangeInfoResponse
+	if err := json.Unmarshal([]byte(testExchangeInfo), &info); err != nil {
+		t.Fatal(err)
+	}
+
+	return NewOrderValidator(&info)
+}
+
+func TestValidateOrder(t *testing.T) {
+	v := testValidator(t)
+
+	tests := []struct {
+		name   string
+		order  CreateOrderRequest
+		fields []string
+		is     []error
+	}{
+		{
+			name:  "valid limit",
+			order: CreateOrderRequest{Symbol: "BTC/USD", Side: OrderSideBuy, Type: OrderTypeLimit, Price: MustDecimal("20000"), Quantity: MustDecimal("0.01")},
+		},
+		{
+			name:   "every violation is collected",
+			order:  CreateOrderRequest{Symbol: "BTC/USD", Side: OrderSideBuy, Type: OrderTypeLimit, Price: MustDecimal("0.75"), Quantity: MustDecimal("0.00015")},
+			fields: []string{"Quantity", "Quantity", "Quantity", "Price", "Price", "Quantity"},
+			is:     []error{ErrInvalidOrder, ErrInvalidPrice, ErrInvalidQuantity},
+		},
+		{
+			name:   "unsupported type and missing price",
+			order:  CreateOrderRequest{Symbol: "BTC/USD", Side: OrderSideBuy, Type: OrderTypeStop, Quantity: MustDecimal("1")},
+			fields: []string{"Type", "Price"},
+			is:     []error{ErrInvalidOrder, ErrInvalidPrice},
+		},
+		{
+			name: "stops on the wrong side and too far",
+			order: CreateOrderRequest{Symbol: "BTC/USD", Side: OrderSideBuy, Type: OrderTypeLimit, Price: MustDecimal("20000"), Quantity: MustDecimal("1"),
+				StopLoss: MustDecimal("20100"), TakeProfit: MustDecimal("30000")},
+			fields: []string{"StopLoss", "TakeProfit"},
+			is:     []error{ErrInvalidPrice},
+		},
+		{
+			name:   "zero precision means whole units",
+			order:  CreateOrderRequest{Symbol: "AAPL", Side: OrderSideBuy, Type: OrderTypeLimit, Price: MustDecimal("150.5"), Quantity: MustDecimal("1.5")},
+			fields: []string{"Quantity", "Price"},
+			is:     []error{ErrInvalidQuantity, ErrInvalidPrice},
+		},
+		{
+			name:   "closed market",
+			order:  CreateOrderRequest{Symbol: "OLD", Side: OrderSideBuy, Type: OrderTypeMarket, Quantity: MustDecimal("1")},
+			fields: []string{"Status"},
+			is:     []error{ErrMarketClosed},
+		},
+		{
+			name:   "unknown symbol",
+			order:  CreateOrderRequest{Symbol: "ETH/USD", Side: OrderSideBuy, Type: OrderTypeMarket, Quantity: MustDecimal("1")},
+			fields: []string{"Symbol"},
+			is:     []error{ErrInvalidSymbol},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := v.ValidateOrder(&tt.order)
+			if len(tt.fields) == 0 {
+				if err != nil {
+					t.Fatalf("unexpected error %v", err)
+				}
+				return
+			}
+
+			var verr *OrderValidationError
+			if !errors.As(err, &verr) {
+				t.Fatalf("err = %v, want OrderValidationError", err)
+			}
+
+			var fields []string
+			for _, violation := range verr.Violations {
+				fields = append(fields, violation.Field)
+			}
+			if !equalStrings(fields, tt.fields) {
+				t.Errorf("violations %v, want fields %v", verr.Violations, tt.fields)
+			}
+
+			for _, target := range tt.is {
+				if !errors.Is(err, target) {
+					t.Errorf("errors.Is(%v, %v) = false", err, target)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateOrderRound(t *testing.T) {
+	v := testValidator(t)
+	v.Round = true
+
+	tests := []struct {
+		name                                  string
+		order                                 CreateOrderRequest
+		price, quantity, stopLoss, takeProfit string
+	}{
+		{
+			name:       "tick and step",
+			order:      CreateOrderRequest{Symbol: "BTC/USD", Side: OrderSideBuy, Type: OrderTypeLimit, Price: MustDecimal("20000.3"), Quantity: MustDecimal("0.0129"), StopLoss: MustDecimal("19500.2"), TakeProfit: MustDecimal("20600.8")},
+			price:      "20000.5",
+			quantity:   "0.012",
+			stopLoss:   "19500",
+			takeProfit: "20601",
+		},
+		{
+			name:       "zero precision",
+			order:      CreateOrderRequest{Symbol: "AAPL", Side: OrderSideBuy, Type: OrderTypeLimit, Price: MustDecimal("150.5"), Quantity: MustDecimal("2.9")},
+			price:      "151",
+			quantity:   "2",
+			stopLoss:   "0",
+			takeProfit: "0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := v.ValidateOrder(&tt.order); err != nil {
+				t.Fatal(err)
+			}
+
+			got := []Decimal{tt.order.Price, tt.order.Quantity, tt.order.StopLoss, tt.order.TakeProfit}
+			want := []string{tt.price, tt.quantity, tt.stopLoss, tt.takeProfit}
+			for i := range want {
+				if !got[i].Equal(MustDecimal(want[i])) {
+					t.Errorf("rounded %v, want %v", got, want)
+					break
+				}
+			}
+		})
+	}
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
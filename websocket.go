@@ -0,0 +1,423 @@
+package currencycom
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const DEFAULT_WS_ENDPOINT string = "wss://api-adapter.backend.currency.com/connect"
+
+const (
+	wsQuotesSubscribe = "marketData.subscribe"
+	wsDepthSubscribe  = "depthMarketData.subscribe"
+	wsOHLCSubscribe   = "OHLCMarketData.subscribe"
+	wsTradesSubscribe = "trades.subscribe"
+	wsPing            = "ping"
+
+	wsQuoteEvent = "internal.quote"
+	wsDepthEvent = "marketdepth.event"
+	wsOHLCEvent  = "ohlc.event"
+	wsTradeEvent = "internal.trade"
+)
+
+// ErrStreamClosed is returned by calls on a closed WebSocketAPI.
+var ErrStreamClosed = errors.New("currencycom: stream closed")
+
+// StreamHandler receives the events of a WebSocketAPI. Handlers are called
+// one at a time, events from the read loop and connection failures from
+// wherever they are noticed, so a slow handler delays the others. Nil
+// handlers are skipped.
+type StreamHandler struct {
+	OnQuote func(InternalQuote)
+	OnDepth func(MarketDepthEvent)
+	OnOHLC  func(OHLCBar)
+	OnTrade func(TradeEventRes)
+	OnError func(error) // malformed events and connection failures
+}
+
+// WebSocketAPI is a streaming market data client.
+type WebSocketAPI struct {
+	Endpoint string
+	Dialer   *websocket.Dialer
+
+	// Registry, when set, rejects subscriptions to symbols it doesn't list
+	// before they are sent.
+	Registry *SymbolRegistry
+
+	handler     StreamHandler
+	handlerMu   sync.Mutex // held while a handler runs
+	lastMessage int64      // unix nanoseconds, accessed atomically
+
+	writeMu sync.Mutex
+	conn    *websocket.Conn
+
+	mu            sync.Mutex
+	correlationId int64
+	pending       map[int64]chan *wsMessage
+	done          chan struct{}
+	err           error
+}
+
+type wsRequest struct {
+	Destination   string      `json:"destination"`
+	CorrelationId int64       `json:"correlationId"`
+	Payload       interface{} `json:"payload"`
+}
+
+type wsMessage struct {
+	Status        string          `json:"status"`
+	Destination   string          `json:"destination"`
+	CorrelationId json.Number     `json:"correlationId"`
+	Payload       json.RawMessage `json:"payload"`
+}
+
+// NewWebSocketAPI creates a client for the endpoint, empty endpoint means
+// DEFAULT_WS_ENDPOINT. Call Connect before subscribing.
+func NewWebSocketAPI(endpoint string, handler StreamHandler) *WebSocketAPI {
+	if endpoint == "" {
+		endpoint = DEFAULT_WS_ENDPOINT
+	}
+
+	return &WebSocketAPI{
+		Endpoint: endpoint,
+		Dialer:   websocket.DefaultDialer,
+		handler:  handler,
+	}
+}
+
+// Connect dials the endpoint and starts reading events.
+func (w *WebSocketAPI) Connect(ctx context.Context) error {
+	w.mu.Lock()
+	connected := w.conn != nil && w.err == nil
+	w.mu.Unlock()
+
+	if connected {
+		return fmt.Errorf("error in dial, already connected")
+	}
+
+	conn, _, err := w.Dialer.DialContext(ctx, w.Endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("error in dial, %w", err)
+	}
+
+	done := make(chan struct{})
+
+	w.mu.Lock()
+	w.conn = conn
+	w.pending = make(map[int64]chan *wsMessage)
+	w.done = done
+	w.err = nil
+	w.mu.Unlock()
+
+	atomic.StoreInt64(&w.lastMessage, time.Now().UnixNano())
+
+	go w.readLoop(conn)
+
+	return nil
+}
+
+// Close closes the connection. Pending calls return ErrStreamClosed.
+func (w *WebSocketAPI) Close() error {
+	w.mu.Lock()
+	conn := w.conn
+	w.mu.Unlock()
+
+	if conn == nil {
+		return nil
+	}
+
+	w.writeMu.Lock()
+	_ = conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+	w.writeMu.Unlock()
+
+	w.shutdown(conn, ErrStreamClosed)
+
+	return nil
+}
+
+// Done is closed when the connection is lost or closed.
+func (w *WebSocketAPI) Done() <-chan struct{} {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.done
+}
+
+// Err returns the reason the connection ended, nil while it is alive.
+func (w *WebSocketAPI) Err() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.err
+}
+
+// LastMessage returns the time the last message was received, or the time
+// of connection if there were none.
+func (w *WebSocketAPI) LastMessage() time.Time {
+	return time.Unix(0, atomic.LoadInt64(&w.lastMessage))
+}
+
+// SubscribeQuotes subscribes to best bid/offer updates delivered to OnQuote.
+func (w *WebSocketAPI) SubscribeQuotes(ctx context.Context, symbols ...Symbol) (*SubscribeResponse, error) {
+	return w.subscribe(ctx, wsQuotesSubscribe, &SubscribeRequest{Symbols: symbols})
+}
+
+// SubscribeDepth subscribes to order book updates delivered to OnDepth.
+func (w *WebSocketAPI) SubscribeDepth(ctx context.Context, symbols ...Symbol) (*SubscribeResponse, error) {
+	return w.subscribe(ctx, wsDepthSubscribe, &SubscribeRequest{Symbols: symbols})
+}
+
+// SubscribeOHLC subscribes to candlesticks delivered to OnOHLC.
+func (w *WebSocketAPI) SubscribeOHLC(ctx context.Context, params *OHLCSubscribeRequest) (*SubscribeResponse, error) {
+	if params == nil {
+		return nil, fmt.Errorf("error params: Symbols need to set")
+	}
+
+	if err := w.checkOHLC(params); err != nil {
+		return nil, err
+	}
+
+	return w.subscribe(ctx, wsOHLCSubscribe, params)
+}
+
+// checkOHLC checks the symbols, intervals and candle type of an OHLC
+// subscription.
+func (w *WebSocketAPI) checkOHLC(params *OHLCSubscribeRequest) error {
+	if err := w.checkSymbols(params.Symbols); err != nil {
+		return err
+	}
+
+	for _, interval := range params.Intervals {
+		if !interval.IsValid() {
+			return fmt.Errorf("error params: unknown Interval %q", interval)
+		}
+	}
+
+	if params.Type != "" && !params.Type.IsValid() {
+		return fmt.Errorf("error params: unknown Type %q", params.Type)
+	}
+
+	return nil
+}
+
+// SubscribeTrades subscribes to public trades delivered to OnTrade.
+func (w *WebSocketAPI) SubscribeTrades(ctx context.Context, symbols ...Symbol) (*SubscribeResponse, error) {
+	return w.subscribe(ctx, wsTradesSubscribe, &SubscribeRequest{Symbols: symbols})
+}
+
+// Ping checks that the server answers on the connection.
+func (w *WebSocketAPI) Ping(ctx context.Context) error {
+	_, err := w.call(ctx, wsPing, &PingRequest{})
+
+	return err
+}
+
+func (w *WebSocketAPI) subscribe(ctx context.Context, destination string, payload interface{}) (*SubscribeResponse, error) {
+	if req, ok := payload.(*SubscribeRequest); ok {
+		if err := w.checkSymbols(req.Symbols); err != nil {
+			return nil, err
+		}
+	}
+
+	msg, err := w.call(ctx, destination, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	var out SubscribeResponse
+	err = json.Unmarshal(msg.Payload, &out)
+
+	return &out, err
+}
+
+// checkSymbols validates the symbols of a subscription, against Registry
+// when it is set.
+func (w *WebSocketAPI) checkSymbols(symbols []Symbol) error {
+	if len(symbols) == 0 {
+		return fmt.Errorf("error params: Symbols need to set")
+	}
+
+	for _, symbol := range symbols {
+		if w.Registry != nil {
+			if err := w.Registry.Validate(symbol); err != nil {
+				return err
+			}
+		} else if !symbol.IsValid() {
+			return fmt.Errorf("error params: invalid Symbol %q", symbol)
+		}
+	}
+
+	return nil
+}
+
+// call sends a request and waits for the response with the same
+// correlation id.
+func (w *WebSocketAPI) call(ctx context.Context, destination string, payload interface{}) (*wsMessage, error) {
+	w.mu.Lock()
+	if w.conn == nil || w.err == ErrStreamClosed {
+		w.mu.Unlock()
+		return nil, ErrStreamClosed
+	}
+
+	if w.err != nil {
+		err := w.err
+		w.mu.Unlock()
+		return nil, fmt.Errorf("%w, %v", ErrStreamClosed, err)
+	}
+
+	w.correlationId++
+	id := w.correlationId
+	conn, done := w.conn, w.done
+	response := make(chan *wsMessage, 1)
+	w.pending[id] = response
+	w.mu.Unlock()
+
+	defer func() {
+		w.mu.Lock()
+		delete(w.pending, id)
+		w.mu.Unlock()
+	}()
+
+	w.writeMu.Lock()
+	err := conn.WriteJSON(&wsRequest{Destination: destination, CorrelationId: id, Payload: payload})
+	w.writeMu.Unlock()
+	if err != nil {
+		return nil, fmt.Errorf("error in write, %w", err)
+	}
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-done:
+		return nil, w.Err()
+	case msg := <-response:
+		if msg.Status != "" && msg.Status != "OK" {
+			return nil, fmt.Errorf("bad response from server, %s %s: %s", destination, msg.Status, string(msg.Payload))
+		}
+
+		return msg, nil
+	}
+}
+
+func (w *WebSocketAPI) readLoop(conn *websocket.Conn) {
+	for {
+		var msg wsMessage
+		if err := conn.ReadJSON(&msg); err != nil {
+			w.shutdown(conn, fmt.Errorf("error in read, %w", err))
+			return
+		}
+
+		atomic.StoreInt64(&w.lastMessage, time.Now().UnixNano())
+
+		w.emit(func() {
+			if err := w.dispatch(&msg); err != nil && w.handler.OnError != nil {
+				w.handler.OnError(err)
+			}
+		})
+	}
+}
+
+// emit runs a call of the handlers, one at a time.
+func (w *WebSocketAPI) emit(call func()) {
+	w.handlerMu.Lock()
+	defer w.handlerMu.Unlock()
+
+	call()
+}
+
+// onError passes err to OnError, if set.
+func (w *WebSocketAPI) onError(err error) {
+	if w.handler.OnError != nil {
+		w.emit(func() { w.handler.OnError(err) })
+	}
+}
+
+func (w *WebSocketAPI) dispatch(msg *wsMessage) error {
+	var err error
+
+	switch msg.Destination {
+	case wsQuoteEvent:
+		var event InternalQuote
+		if err = json.Unmarshal(msg.Payload, &event); err == nil && w.handler.OnQuote != nil {
+			w.handler.OnQuote(event)
+		}
+	case wsDepthEvent:
+		var event MarketDepthEvent
+		if err = json.Unmarshal(msg.Payload, &event); err == nil && w.handler.OnDepth != nil {
+			w.handler.OnDepth(event)
+		}
+	case wsOHLCEvent:
+		var event OHLCBar
+		if err = json.Unmarshal(msg.Payload, &event); err == nil && w.handler.OnOHLC != nil {
+			w.handler.OnOHLC(event)
+		}
+	case wsTradeEvent:
+		var event TradeEventRes
+		if err = json.Unmarshal(msg.Payload, &event); err == nil && w.handler.OnTrade != nil {
+			w.handler.OnTrade(event)
+		}
+	default:
+		id, _ := strconv.ParseInt(msg.CorrelationId.String(), 10, 64)
+
+		w.mu.Lock()
+		response, ok := w.pending[id]
+		w.mu.Unlock()
+
+		// a duplicate response must not block the read loop, the caller
+		// only waits for the first one
+		if ok {
+			select {
+			case response <- msg:
+			default:
+			}
+		}
+
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("error in decode %s, %w", msg.Destination, err)
+	}
+
+	return nil
+}
+
+// drop closes the current connection without the close handshake, e.g.
+// when it stopped responding.
+func (w *WebSocketAPI) drop(reason error) {
+	w.mu.Lock()
+	conn := w.conn
+	w.mu.Unlock()
+
+	if conn != nil {
+		w.shutdown(conn, reason)
+	}
+}
+
+// shutdown closes conn and records the reason once, later calls for the
+// same connection are ignored.
+func (w *WebSocketAPI) shutdown(conn *websocket.Conn, reason error) {
+	w.mu.Lock()
+	if w.conn != conn || w.err != nil {
+		w.mu.Unlock()
+		return
+	}
+
+	w.err = reason
+	close(w.done)
+	w.mu.Unlock()
+
+	conn.Close()
+
+	if reason != ErrStreamClosed {
+		w.onError(reason)
+	}
+}
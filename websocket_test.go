@@ -0,0 +1,199 @@
+package currencycom
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// wsServer is a fake stream endpoint, serve runs for every connection with
+// its number, starting at 1.
+func wsServer(t *testing.T, serve func(n int, conn *websocket.Conn)) string {
+	t.Helper()
+
+	var conns int32
+	upgrader := websocket.Upgrader{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		serve(int(atomic.AddInt32(&conns, 1)), conn)
+	}))
+	t.Cleanup(srv.Close)
+
+	return "ws" + strings.TrimPrefix(srv.URL, "http")
+}
+
+// wsAnswer answers req with status and a raw JSON payload.
+func wsAnswer(conn *websocket.Conn, id int64, destination, status, payload string) error {
+	return conn.WriteMessage(websocket.TextMessage, []byte(fmt.Sprintf(
+		`{"status":%q,"destination":%q,"correlationId":%d,"payload":%s}`, status, destination, id, payload)))
+}
+
+// wsEvent pushes an event with a raw JSON payload.
+func wsEvent(conn *websocket.Conn, destination, payload string) error {
+	return conn.WriteMessage(websocket.TextMessage, []byte(fmt.Sprintf(`{"destination":%q,"payload":%s}`, destination, payload)))
+}
+
+func TestWebSocketAPI(t *testing.T) {
+	endpoint := wsServer(t, func(n int, conn *websocket.Conn) {
+		for {
+			var req wsRequest
+			if err := conn.ReadJSON(&req); err != nil {
+				return
+			}
+
+			switch req.Destination {
+			case wsQuotesSubscribe:
+				// a stray id is ignored and a repeated answer doesn't block
+				_ = wsAnswer(conn, req.CorrelationId+100, req.Destination, "OK", `{"subscriptions":{"ETH/USD":"OK"}}`)
+				_ = wsAnswer(conn, req.CorrelationId, req.Destination, "OK", `{"subscriptions":{"BTC/USD":"OK"}}`)
+				_ = wsAnswer(conn, req.CorrelationId, req.Destination, "OK", `{"subscriptions":{"BTC/USD":"OK"}}`)
+
+				_ = wsEvent(conn, wsQuoteEvent, `{"symbolName":"BTC/USD","bid":"100","ofr":"101"}`)
+				_ = wsEvent(conn, wsDepthEvent, `{"symbol":"BTC/USD","data":{"ts":1000,"bid":{"100":"1"},"ofr":{"101":"2"}}}`)
+				_ = wsEvent(conn, wsOHLCEvent, `{"symbol":"BTC/USD","interval":"1m","c":"100.5"}`)
+				_ = wsEvent(conn, wsTradeEvent, `{"symbol":"BTC/USD","price":"100.25","size":"0.5"}`)
+				_ = wsEvent(conn, wsQuoteEvent, `[1, 2]`)
+			case wsTradesSubscribe:
+				_ = wsAnswer(conn, req.CorrelationId, req.Destination, "ERROR", `{"message":"unknown symbol"}`)
+			default:
+				_ = wsAnswer(conn, req.CorrelationId, req.Destination, "OK", `{}`)
+			}
+		}
+	})
+
+	events := make(chan string, 10)
+	ws := NewWebSocketAPI(endpoint, StreamHandler{
+		OnQuote: func(q InternalQuote) { events <- "quote " + q.SymbolName + " " + q.Bid.String() },
+		OnDepth: func(d MarketDepthEvent) { events <- "depth " + d.Symbol + " " + d.Data.Ofr["101"].String() },
+		OnOHLC:  func(bar OHLCBar) { events <- "ohlc " + bar.Interval + " " + bar.C.String() },
+		OnTrade: func(trade TradeEventRes) { events <- "trade " + trade.Price.String() },
+		OnError: func(err error) { events <- "error" },
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := ws.Connect(ctx); err != nil {
+		t.Fatal(err)
+	}
+	defer ws.Close()
+
+	resp, err := ws.SubscribeQuotes(ctx, "BTC/USD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Subscriptions["BTC/USD"] != "OK" || len(resp.Subscriptions) != 1 {
+		t.Errorf("subscriptions %v, want the answer with the request id", resp.Subscriptions)
+	}
+
+	want := []string{"quote BTC/USD 100", "depth BTC/USD 2", "ohlc 1m 100.5", "trade 100.25", "error"}
+	for _, w := range want {
+		select {
+		case got := <-events:
+			if got != w {
+				t.Errorf("event %q, want %q", got, w)
+			}
+		case <-ctx.Done():
+			t.Fatalf("no event, want %q", w)
+		}
+	}
+
+	if err := ws.Ping(ctx); err != nil {
+		t.Errorf("Ping: %v", err)
+	}
+
+	if _, err := ws.SubscribeTrades(ctx, "BTC/USD"); err == nil || !strings.Contains(err.Error(), "ERROR") {
+		t.Errorf("SubscribeTrades answered with ERROR: err = %v", err)
+	}
+}
+
+func TestWebSocketClose(t *testing.T) {
+	// the server reads requests and never answers
+	endpoint := wsServer(t, func(n int, conn *websocket.Conn) {
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	})
+
+	var errorsSeen int32
+	ws := NewWebSocketAPI(endpoint, StreamHandler{OnError: func(error) { atomic.AddInt32(&errorsSeen, 1) }})
+	if err := ws.Connect(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	pinged := make(chan error, 1)
+	go func() { pinged <- ws.Ping(context.Background()) }()
+
+	time.Sleep(50 * time.Millisecond)
+	if err := ws.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case err := <-pinged:
+		if !errors.Is(err, ErrStreamClosed) {
+			t.Errorf("pending Ping: err = %v, want ErrStreamClosed", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("pending Ping not released by Close")
+	}
+
+	if _, err := ws.SubscribeQuotes(context.Background(), "BTC/USD"); !errors.Is(err, ErrStreamClosed) {
+		t.Errorf("SubscribeQuotes after Close: err = %v, want ErrStreamClosed", err)
+	}
+
+	if n := atomic.LoadInt32(&errorsSeen); n != 0 {
+		t.Errorf("OnError called %d times, want none for Close", n)
+	}
+}
+
+func TestWebSocketDrop(t *testing.T) {
+	// the server hangs up on the first request
+	endpoint := wsServer(t, func(n int, conn *websocket.Conn) {
+		_, _, _ = conn.ReadMessage()
+	})
+
+	reasons := make(chan error, 1)
+	ws := NewWebSocketAPI(endpoint, StreamHandler{OnError: func(err error) { reasons <- err }})
+	if err := ws.Connect(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ws.Ping(context.Background()); err == nil {
+		t.Fatal("Ping on a dropped connection: want error")
+	}
+
+	select {
+	case <-ws.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("Done not closed")
+	}
+
+	select {
+	case err := <-reasons:
+		if err == nil || errors.Is(err, ErrStreamClosed) || err != ws.Err() {
+			t.Errorf("OnError(%v), want the read error of Err() %v", err, ws.Err())
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("OnError not called for the lost connection")
+	}
+
+	if _, err := ws.SubscribeQuotes(context.Background(), "BTC/USD"); !errors.Is(err, ErrStreamClosed) {
+		t.Errorf("SubscribeQuotes after the drop: err = %v, want ErrStreamClosed", err)
+	}
+}